@@ -16,9 +16,12 @@ import (
 	"github.com/DioGolang/GoFleet/internal/application/usecase/order"
 	"github.com/DioGolang/GoFleet/internal/domain/event"
 	"github.com/DioGolang/GoFleet/internal/infra/database"
-	infraEvent "github.com/DioGolang/GoFleet/internal/infra/event"
+	"github.com/DioGolang/GoFleet/internal/infra/ratelimit"
+	"github.com/DioGolang/GoFleet/internal/infra/security"
+	"github.com/DioGolang/GoFleet/internal/infra/web"
 	"github.com/DioGolang/GoFleet/internal/infra/web/handler"
 	middlewareMetrics "github.com/DioGolang/GoFleet/internal/infra/web/middleware"
+	"github.com/DioGolang/GoFleet/internal/infra/web/openapi"
 	"github.com/DioGolang/GoFleet/pkg/logger"
 	"github.com/DioGolang/GoFleet/pkg/metrics"
 	"github.com/DioGolang/GoFleet/pkg/otel"
@@ -27,7 +30,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
 	"github.com/riandyrn/otelchi"
 )
 
@@ -93,68 +96,50 @@ func main() {
 	}(db)
 
 	// =========================================================================
-	// MESSAGING (RabbitMQ)
+	// DEPENDENCIES: OUTBOX & UNIT OF WORK
 	// =========================================================================
-	rabbitURL := fmt.Sprintf("amqp://guest:guest@%s:%s/", config.RabbitMQHost, config.AMQPort)
-	conn, err := amqp.Dial(rabbitURL)
-	if err != nil {
-		fail("rabbitmq connection failed", err)
-	}
-	defer func(conn *amqp.Connection) {
-		zapLogger.Info(ctx, "Closing RabbitMQ...")
-		err := conn.Close()
-		if err != nil {
-			fmt.Printf("Error closing RabbitMQ: %v\n", err)
-		}
-	}(conn)
-
-	ch, err := conn.Channel()
-	if err != nil {
-		fail("rabbitmq channel failed", err)
-	}
-	defer func(ch *amqp.Channel) {
-		zapLogger.Info(ctx, "Closing rabbitmq channel...")
-		err := ch.Close()
-		if err != nil {
-			fmt.Printf("Error closing rabbitmq channel: %v\n", err)
-		}
-	}(ch)
-
-	err = ch.ExchangeDeclare(
-		"orders_exchange", "direct", true, false, false, false, nil,
-	)
+	// A API só grava no outbox; a publicação fica no cmd/relay.
+	// Todo evento é validado contra o schema da sua versão antes da gravação.
+	schemas, err := event.NewSchemaRegistry()
 	if err != nil {
-		fail("failed to declare exchange", err)
+		fail("event schema registry load failed", err)
 	}
+	uow := database.NewUnitOfWork(db, database.WithSchemaRegistry(schemas))
 
 	// =========================================================================
-	// DEPENDENCIES: OUTBOX & UNIT OF WORK
+	// REDIS (Rate Limit distribuído)
 	// =========================================================================
-
-	uow := database.NewUnitOfWork(db)
-	eventDispatcher := infraEvent.NewDispatcher(ch, zapLogger)
-	queries := database.New(db)
-	relay := infraEvent.NewOutboxRelay(queries, db, eventDispatcher, zapLogger)
-
-	go func() {
-		zapLogger.Info(ctx, "Starting Outbox Relay...")
-		relay.Run(ctx)
-	}()
-
-	go func() {
-		go relay.RunRescuer(ctx)
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         fmt.Sprintf("%s:%s", config.RedisHost, config.RedisPort),
+		MinIdleConns: 10,
+		PoolSize:     50,
+	})
+	pingCtx, pingCancel := context.WithTimeout(ctx, 5*time.Second)
+	if err := rdb.Ping(pingCtx).Err(); err != nil {
+		// Não é fatal: o rate limiter cai para o modo em memória.
+		zapLogger.Warn(ctx, "Redis unreachable at startup, rate limit will use in-memory fallback", logger.WithError(err))
+	}
+	pingCancel()
+	defer func() {
+		if err := rdb.Close(); err != nil {
+			zapLogger.Error(ctx, "Error closing Redis", logger.WithError(err))
+		}
 	}()
 
 	// =========================================================================
 	// RATE LIMITER (Defense in Depth)
 	// =========================================================================
-	// Config: 10 req/s, Burst de 20. Limpeza a cada 1 min.
-	rateLimiter := middlewareMetrics.NewRateLimiter(middlewareMetrics.RateLimiterConfig{
-		RequestsPerSecond: 10,
-		Burst:             20,
-		CleanupInterval:   1 * time.Minute,
-		ClientTimeout:     3 * time.Minute,
-	})
+	rateLimitPolicy, err := ratelimit.LoadPolicy(config.RateLimitConfigFile)
+	if err != nil {
+		fail("rate limit config load failed", err)
+	}
+	rateLimiter := ratelimit.NewFallbackLimiter(
+		ratelimit.NewRedisLimiter(rdb),
+		ratelimit.NewMemoryLimiter(ctx, 1*time.Minute, 3*time.Minute),
+		50*time.Millisecond,
+		zapLogger,
+		ratelimit.WithFallbackMetrics(prometheusMetrics),
+	)
 
 	// =========================================================================
 	// DEPENDENCIES & HANDLERS
@@ -166,7 +151,33 @@ func main() {
 		Next:    createOrderUseCase,
 		Metrics: prometheusMetrics,
 	}
-	orderHandler := handler.NewOrderHandler(createOrderUseCaseWithMetrics, zapLogger)
+	orderRepository := database.NewOrderRepository(db)
+	orderHandler := handler.NewOrderHandler(
+		createOrderUseCaseWithMetrics,
+		order.NewGetOrderUseCase(orderRepository),
+		order.NewCancelOrderUseCase(uow, event.NewOrderCancelled(), zapLogger),
+		order.NewDeliverOrderUseCase(uow, zapLogger),
+		order.NewAssignOrderUseCase(uow, zapLogger),
+		zapLogger,
+	)
+
+	// =========================================================================
+	// AUTH (JWT HS256 / RS256 via JWKS local)
+	// =========================================================================
+	jwtVerifier, err := security.NewJWTVerifier(security.VerifierConfig{
+		HS256Secret: config.JWTHS256Secret,
+		JWKSFile:    config.JWTJWKSFile,
+		Issuer:      config.JWTIssuer,
+		Audience:    config.JWTAudience,
+	})
+	if err != nil {
+		fail("jwt verifier init failed", err)
+	}
+
+	apiSpec, err := openapi.Load()
+	if err != nil {
+		fail("openapi spec load failed", err)
+	}
 
 	// ROUTER COM OTEL MIDDLEWARE
 	r := chi.NewRouter()
@@ -190,14 +201,6 @@ func main() {
 			return err
 		}),
 
-		handler.WithRabbitMQ(func(ctx context.Context) error {
-			zapLogger.Info(ctx, "HEALTH CHECK: Checking RabbitMQ Connection...")
-			if conn.IsClosed() {
-				return fmt.Errorf("connection closed")
-			}
-			return nil
-		}),
-
 		// handler.WithCheck("fake-failure", 1*time.Second, func(ctx context.Context) error {
 		// 	 return fmt.Errorf("simulated chaos monkey error")
 		// }),
@@ -208,13 +211,21 @@ func main() {
 	}
 
 	//API
-	r.Use(rateLimiter.Handler(zapLogger))
 	r.Use(middlewareMetrics.MetricsWrapper(prometheusMetrics))
 	r.Use(middlewareMetrics.RequestLogger(zapLogger))
 	r.Use(middleware.Recoverer)
+	// Limite por IP antes de qualquer autenticação; a cota por cliente roda dentro de /api/v1.
+	r.Use(middlewareMetrics.RateLimitByIP(rateLimiter, rateLimitPolicy, zapLogger))
 
 	r.Get("/health", healthHandler.ServeHTTP)
-	r.Post("/api/v1/orders", orderHandler.Create)
+	web.RegisterAPIRoutes(r, web.APIHandlers{
+		Order:        orderHandler,
+		OpenAPI:      openapi.Handler(),
+		Authenticate: middlewareMetrics.Authenticate(jwtVerifier, zapLogger),
+		Tenant:       middlewareMetrics.ResolveTenant(zapLogger),
+		RateLimit:    middlewareMetrics.RateLimit(rateLimiter, rateLimitPolicy, zapLogger),
+		Validate:     middlewareMetrics.ValidateRequest(apiSpec, zapLogger),
+	})
 
 	// HTTP SERVER SHUTDOWN
 	srv := &http.Server{
@@ -19,6 +19,7 @@ import (
 	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
 	"github.com/DioGolang/GoFleet/internal/infra/grpc/service"
 	"github.com/DioGolang/GoFleet/pkg/otel"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
@@ -110,6 +111,7 @@ func main() {
 
 	grpcServer := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(tenant.UnaryServerInterceptor()),
 	)
 
 	pb.RegisterFleetServiceServer(grpcServer, fleetService)
@@ -131,7 +133,11 @@ func main() {
 	zapLogger.Info(ctx, "Service exited cleanly")
 }
 
+// seedTenant é o tenant de demonstração usado pelos tokens do orders.http.
+const seedTenant = "demo"
+
 func setupSeedData(ctx context.Context, s *service.FleetService) {
+	ctx = tenant.WithTenant(ctx, seedTenant)
 	_ = s.UpdateDriverPosition(ctx, "Joao-da-Silva", -23.55, -46.63)
 	_ = s.UpdateDriverPosition(ctx, "Maria-Longe", -23.60, -46.70)
 	fmt.Println("Simulated GPS data loaded into Redis!")
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"os/user"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DioGolang/GoFleet/configs"
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/database"
+	"github.com/DioGolang/GoFleet/internal/infra/parking"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	_ "github.com/lib/pq"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const usage = `usage: parking <command> [flags]
+
+commands:
+  list     lista mensagens estacionadas      (-reason, -type, -limit, -json)
+  show     mostra headers e corpo             (-id)
+  replay   devolve à fila original (auditado) (-ids | -reason/-type | -all)
+  purge    descarta mensagens (auditado)      (-ids | -reason/-type | -all) -yes
+
+flags comuns: -queue (default orders.created), -actor
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := run(os.Args[1], os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "parking:", err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd string, args []string) error {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	queue := fs.String("queue", "orders.created", "fila de trabalho dona do Parking")
+	actor := fs.String("actor", defaultActor(), "operador gravado na auditoria")
+	reason := fs.String("reason", "", "filtra por x-fail-reason")
+	eventType := fs.String("type", "", "filtra por tipo de evento (OrderCreated ou io.gofleet.order.OrderCreated)")
+	limit := fs.Int("limit", 50, "máximo de mensagens listadas")
+	asJSON := fs.Bool("json", false, "saída em JSON")
+	id := fs.String("id", "", "id da mensagem")
+	ids := fs.String("ids", "", "ids separados por vírgula")
+	all := fs.Bool("all", false, "seleciona todas as mensagens")
+	yes := fs.Bool("yes", false, "confirma o purge")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := parking.Filter{Reason: *reason, EventType: *eventType, All: *all}
+	if *ids != "" {
+		filter.IDs = strings.Split(*ids, ",")
+	}
+
+	switch cmd {
+	case "list", "show", "replay":
+	case "purge":
+		if !*yes {
+			return fmt.Errorf("purge is irreversible: pass -yes to confirm")
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if cmd == "show" && *id == "" {
+		return fmt.Errorf("show requires -id")
+	}
+	if (cmd == "replay" || cmd == "purge") && filter.Empty() {
+		return parking.ErrEmptySelection
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	manager, closeAll, err := connect()
+	if err != nil {
+		return err
+	}
+	defer closeAll()
+
+	op := parking.Actor{ID: *actor, Role: string(auth.RoleAdmin)}
+	switch cmd {
+	case "list":
+		msgs, err := manager.List(ctx, *queue, filter, *limit)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(msgs)
+		}
+		printTable(msgs)
+	case "show":
+		msg, err := manager.Get(ctx, *queue, *id)
+		if err != nil {
+			return err
+		}
+		return printJSON(msg)
+	case "replay":
+		res, err := manager.Replay(ctx, *queue, filter, op)
+		if err != nil {
+			return err
+		}
+		return printJSON(res)
+	case "purge":
+		res, err := manager.Purge(ctx, *queue, filter, op)
+		if err != nil {
+			return err
+		}
+		return printJSON(res)
+	}
+	return nil
+}
+
+// connect abre RabbitMQ e Postgres (auditoria) com a mesma configuração do Worker.
+func connect() (*parking.Manager, func(), error) {
+	config, err := configs.LoadConfig(".", "gofleet-parking")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName)
+	db, err := sql.Open(config.DBDriver, dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("db connection failed: %w", err)
+	}
+
+	conn, err := amqp.Dial(fmt.Sprintf("amqp://guest:guest@%s:%s/", config.RabbitMQHost, config.AMQPort))
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("rabbitmq connection failed: %w", err)
+	}
+
+	l := logger.NewZapLogger(config.OtelServiceName, false)
+	manager := parking.NewManager(conn, database.NewAuditRepository(database.New(db)), l)
+	return manager, func() {
+		_ = conn.Close()
+		_ = db.Close()
+	}, nil
+}
+
+func defaultActor() string {
+	if u, err := user.Current(); err == nil {
+		return "cli:" + u.Username
+	}
+	return "cli"
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printTable(msgs []parking.Message) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tREASON\tEVENT TYPE\tTENANT\tRETRIES\tPARKED AT")
+	for _, m := range msgs {
+		parkedAt := "-"
+		if m.ParkedAt != nil {
+			parkedAt = m.ParkedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", m.ID, m.Reason, m.EventType, m.TenantID, m.Retries, parkedAt)
+	}
+	_ = w.Flush()
+}
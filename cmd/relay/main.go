@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/DioGolang/GoFleet/configs"
+	"github.com/DioGolang/GoFleet/internal/infra/database"
+	infraEvent "github.com/DioGolang/GoFleet/internal/infra/event"
+	"github.com/DioGolang/GoFleet/internal/infra/outbox"
+	"github.com/DioGolang/GoFleet/internal/infra/ratelimit"
+	"github.com/DioGolang/GoFleet/internal/infra/security"
+	"github.com/DioGolang/GoFleet/internal/infra/web"
+	"github.com/DioGolang/GoFleet/internal/infra/web/handler"
+	middlewareMetrics "github.com/DioGolang/GoFleet/internal/infra/web/middleware"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/DioGolang/GoFleet/pkg/otel"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	config, err := configs.LoadRelayConfig(".")
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownOtel, err := otel.InitProvider(ctx, config.OtelServiceName, config.OtelExporterOTLPEndpoint)
+	if err != nil {
+		log.Fatalf("failed to init OTel: %v", err)
+	}
+	defer shutdownOtel()
+
+	zapLogger := logger.NewZapLogger(config.OtelServiceName, false)
+	fail := func(msg string, err error) {
+		zapLogger.Error(ctx, msg, logger.WithError(err))
+		os.Exit(1)
+	}
+	zapLogger.Info(ctx, "Relay starting")
+
+	reg := prometheus.NewRegistry()
+	promMetrics := metrics.NewPrometheusMetrics(reg, config.OtelServiceName)
+
+	// =========================================================================
+	// DATABASE (PostgreSQL)
+	// =========================================================================
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName)
+	db, err := sql.Open(config.DBDriver, dsn)
+	if err != nil {
+		fail("db driver error", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		fail("db connection unreachable", err)
+	}
+	defer func(db *sql.DB) {
+		zapLogger.Info(ctx, "Closing Database...")
+		if err := db.Close(); err != nil {
+			zapLogger.Error(ctx, "Error closing database", logger.WithError(err))
+		}
+	}(db)
+
+	// =========================================================================
+	// MESSAGING (RabbitMQ)
+	// =========================================================================
+	routingTable, err := infraEvent.LoadRoutingTable(config.RoutingConfigFile)
+	if err != nil {
+		fail("failed to load routing config", err)
+	}
+
+	// A topologia é redeclarada a cada reconexão; o publisher troca de canal sozinho.
+	rabbitURL := fmt.Sprintf("amqp://guest:guest@%s:%s/", config.RabbitMQHost, config.AMQPort)
+	conn := infraEvent.NewConnectionManager(rabbitURL, zapLogger,
+		infraEvent.WithTopology(routingTable.DeclareTopology),
+		infraEvent.WithConnectionMetrics(promMetrics),
+	)
+	connectCtx, connectCancel := context.WithTimeout(ctx, 30*time.Second)
+	if err := conn.Connect(connectCtx); err != nil {
+		fail("rabbitmq connection failed", err)
+	}
+	connectCancel()
+	go conn.Run(ctx)
+	defer func(conn *infraEvent.ConnectionManager) {
+		zapLogger.Info(ctx, "Closing RabbitMQ...")
+		if err := conn.Close(); err != nil {
+			zapLogger.Error(ctx, "Error closing RabbitMQ", logger.WithError(err))
+		}
+	}(conn)
+
+	// Cada lane pega um canal do pool só durante o publish; o confirm chega depois.
+	publisherPool := infraEvent.NewChannelPool(conn, zapLogger, infraEvent.WithPoolSize(config.OutboxPublishChannels))
+	go publisherPool.Run(ctx)
+	defer func() {
+		if err := publisherPool.Close(); err != nil {
+			zapLogger.Error(ctx, "Error closing publisher channels", logger.WithError(err))
+		}
+	}()
+	dispatcher := infraEvent.NewDispatcher(publisherPool, routingTable, zapLogger)
+
+	// =========================================================================
+	// RELAY
+	// =========================================================================
+	leader := database.NewAdvisoryLeader(db, outbox.LeaderLockKey, zapLogger)
+	relayOpts := []outbox.Option{
+		outbox.WithBatchSize(config.OutboxBatchSize),
+		outbox.WithLanes(config.OutboxLanes),
+		outbox.WithRetryPolicy(outbox.RetryPolicy{
+			MaxAttempts: config.OutboxMaxAttempts,
+			BaseBackoff: config.OutboxBaseBackoff,
+			MaxBackoff:  config.OutboxMaxBackoff,
+		}),
+		outbox.WithLease(config.OutboxLease),
+		// Todas as réplicas sobem, mas só a que segura o advisory lock publica.
+		outbox.WithLeader(leader),
+		outbox.WithMetrics(promMetrics),
+	}
+
+	// LISTEN/NOTIFY acorda o relay; sem ele, volta ao polling curto.
+	outboxListener, err := database.NewOutboxListener(dsn, zapLogger)
+	if err != nil {
+		zapLogger.Warn(ctx, "Outbox listener unavailable, relay falls back to polling", logger.WithError(err))
+	} else {
+		defer func() {
+			if err := outboxListener.Close(); err != nil {
+				zapLogger.Error(ctx, "Error closing outbox listener", logger.WithError(err))
+			}
+		}()
+		go outboxListener.Run(ctx)
+		relayOpts = append(relayOpts,
+			outbox.WithWakeup(outboxListener.Wakeup()),
+			outbox.WithPollInterval(config.OutboxFallbackPoll),
+		)
+	}
+	relay := outbox.NewRelay(database.New(db), db, dispatcher, zapLogger, relayOpts...)
+
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Start(ctx)
+	}()
+
+	// =========================================================================
+	// CHECKS & METRICS
+	// =========================================================================
+	healthHandler, err := handler.NewHealthHandler(
+		handler.WithName(config.OtelServiceName, "1.0.0"),
+		handler.WithPostgres(func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		}),
+		handler.WithRabbitMQ(conn.Check),
+	)
+	if err != nil {
+		fail("health check init failed", err)
+	}
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/health", healthHandler)
+		mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+		if err := http.ListenAndServe(":2112", mux); err != nil {
+			zapLogger.Error(ctx, "Monitoring server failed", logger.WithError(err))
+		}
+	}()
+
+	// =========================================================================
+	// ADMIN API
+	// =========================================================================
+	jwtVerifier, err := security.NewJWTVerifier(security.VerifierConfig{
+		HS256Secret: config.JWTHS256Secret,
+		JWKSFile:    config.JWTJWKSFile,
+		Issuer:      config.JWTIssuer,
+		Audience:    config.JWTAudience,
+	})
+	if err != nil {
+		fail("jwt verifier init failed", err)
+	}
+
+	rateLimitPolicy, err := ratelimit.LoadPolicy(config.RateLimitConfigFile)
+	if err != nil {
+		fail("rate limit config load failed", err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(middlewareMetrics.RequestLogger(zapLogger))
+	r.Use(middleware.Recoverer)
+	// Admin tem pouco tráfego: o limite por IP em memória, por réplica, basta.
+	r.Use(middlewareMetrics.RateLimitByIP(
+		ratelimit.NewMemoryLimiter(ctx, 1*time.Minute, 3*time.Minute),
+		rateLimitPolicy,
+		zapLogger,
+	))
+	web.RegisterAdminRoutes(r, web.AdminHandlers{
+		Outbox:       handler.NewOutboxAdminHandler(relay, zapLogger),
+		Authenticate: middlewareMetrics.Authenticate(jwtVerifier, zapLogger),
+	})
+
+	srv := &http.Server{
+		Addr:    ":" + config.RelayAdminPort,
+		Handler: r,
+	}
+	go func() {
+		zapLogger.Info(ctx, "Relay admin API running", logger.String("port", config.RelayAdminPort))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fail("admin listen failed", err)
+		}
+	}()
+
+	<-ctx.Done()
+	zapLogger.Info(ctx, "Relay stopping gracefully...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		zapLogger.Error(ctx, "Admin server forced to shutdown", logger.WithError(err))
+	}
+
+	// Espera o lote em voo terminar antes de fechar canal e banco.
+	select {
+	case <-relayDone:
+	case <-shutdownCtx.Done():
+		zapLogger.Warn(ctx, "Relay did not stop before shutdown deadline")
+	}
+	zapLogger.Info(ctx, "Relay exited")
+}
@@ -3,12 +3,20 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/DioGolang/GoFleet/internal/application/usecase/order"
-	"github.com/DioGolang/GoFleet/internal/infra/storage"
+	domainevent "github.com/DioGolang/GoFleet/internal/domain/event"
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+	"github.com/DioGolang/GoFleet/internal/infra/inbox"
+	"github.com/DioGolang/GoFleet/internal/infra/parking"
+	"github.com/DioGolang/GoFleet/internal/infra/ratelimit"
+	"github.com/DioGolang/GoFleet/internal/infra/security"
+	"github.com/DioGolang/GoFleet/internal/infra/web"
 	"github.com/DioGolang/GoFleet/internal/infra/web/handler"
+	middlewareMetrics "github.com/DioGolang/GoFleet/internal/infra/web/middleware"
 	"github.com/DioGolang/GoFleet/pkg/logger"
 	"github.com/DioGolang/GoFleet/pkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,8 +35,10 @@ import (
 	"github.com/DioGolang/GoFleet/internal/infra/event"
 	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
 	"github.com/DioGolang/GoFleet/pkg/otel"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	_ "github.com/lib/pq"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -103,6 +113,7 @@ func main() {
 	grpcConn, err := grpc.NewClient(grpcURL,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithUnaryInterceptor(tenant.UnaryClientInterceptor()),
 		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin":{}}], "methodConfig": [{"name": [{"service": ""}], "retryPolicy": {"maxAttempts": 5, "initialBackoff": "0.1s", "maxBackoff": "1s", "backoffMultiplier": 2.0, "retryableStatusCodes": ["UNAVAILABLE"]}}]}`),
 	)
 	if err != nil {
@@ -128,6 +139,10 @@ func main() {
 			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
 			return counts.Requests >= 10 && failureRatio >= 0.6
 		},
+		// Falha permanente é problema da mensagem, não do Fleet: não conta para abrir o circuito.
+		IsSuccessful: func(err error) bool {
+			return err == nil || event.Classify(err).Permanent()
+		},
 		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
 			zapLogger.Warn(context.Background(), "Circuit Breaker State Changed",
 				logger.String("name", name),
@@ -139,13 +154,16 @@ func main() {
 
 	circuitBreaker := gobreaker.NewCircuitBreaker(cbSettings)
 
-	// RabbitMQ Connection
+	// RabbitMQ Connection: o manager reconecta sozinho e o Consumer refaz as assinaturas.
 	rabbitURL := fmt.Sprintf("amqp://guest:guest@%s:%s/", config.RabbitMQHost, config.AMQPort)
-	conn, err := amqp.Dial(rabbitURL)
-	if err != nil {
+	conn := event.NewConnectionManager(rabbitURL, zapLogger, event.WithConnectionMetrics(promMetrics))
+	connectCtx, connectCancel := context.WithTimeout(ctx, 30*time.Second)
+	if err := conn.Connect(connectCtx); err != nil {
 		fail("rabbitmq connection failed", err)
 	}
-	defer func(conn *amqp.Connection) {
+	connectCancel()
+	go conn.Run(ctx)
+	defer func(conn *event.ConnectionManager) {
 		zapLogger.Info(ctx, "Closing RabbitMQ...")
 		err := conn.Close()
 		if err != nil {
@@ -153,7 +171,9 @@ func main() {
 		}
 	}(conn)
 
-	dispatchUseCase := order.NewDispatchUseCase(repository)
+	// O despacho grava o inbox na mesma transação do UpdateStatus.
+	dispatchUseCase := order.NewDispatchUseCase(database.NewUnitOfWork(db))
+	go inbox.RunCleanup(ctx, database.New(db), time.Hour, config.InboxRetention, zapLogger)
 	dispatchUseCaseWithMetrics := &order.DispatchOrderMetricsDecorator{
 		Next:    dispatchUseCase,
 		Metrics: promMetrics,
@@ -175,12 +195,7 @@ func main() {
 				return rdb.Ping(ctx).Err()
 			}),
 
-			handler.WithRabbitMQ(func(ctx context.Context) error {
-				if conn.IsClosed() {
-					return fmt.Errorf("rabbitmq connection is closed")
-				}
-				return nil
-			}),
+			handler.WithRabbitMQ(conn.Check),
 		)
 
 		if err != nil {
@@ -199,59 +214,147 @@ func main() {
 		}
 	}()
 
-	// Consumer Logic
-	consumer := event.NewConsumer(conn, grpcClient, repository, dispatchUseCaseWithMetrics, rdb, zapLogger, 10)
-
-	handlerStack := consumer.ProcessOrder
+	// Admin API: inspeção, replay e purge das filas de Parking (papel admin)
+	jwtVerifier, err := security.NewJWTVerifier(security.VerifierConfig{
+		HS256Secret: config.JWTHS256Secret,
+		JWKSFile:    config.JWTJWKSFile,
+		Issuer:      config.JWTIssuer,
+		Audience:    config.JWTAudience,
+	})
+	if err != nil {
+		fail("jwt verifier init failed", err)
+	}
 
-	handlerStack = event.WrapResilientConsumer(
-		promMetrics,
-		"WorkerProcessOrder",
-		5*time.Second,
-		circuitBreaker,
-		handlerStack,
-	)
+	parkingManager := parking.NewManager(conn, database.NewAuditRepository(database.New(db)), zapLogger)
 
-	redisStore := storage.NewRedisAdapter(rdb)
+	rateLimitPolicy, err := ratelimit.LoadPolicy(config.RateLimitConfigFile)
+	if err != nil {
+		fail("rate limit config load failed", err)
+	}
 
-	handlerStack = event.WrapIdempotency(
+	r := chi.NewRouter()
+	r.Use(middlewareMetrics.RequestLogger(zapLogger))
+	r.Use(middleware.Recoverer)
+	// Admin tem pouco tráfego: o limite por IP em memória, por réplica, basta.
+	r.Use(middlewareMetrics.RateLimitByIP(
+		ratelimit.NewMemoryLimiter(ctx, 1*time.Minute, 3*time.Minute),
+		rateLimitPolicy,
 		zapLogger,
-		redisStore,
-		"WorkerProcessOrder",
-		24*time.Hour,
-		handlerStack,
-	)
+	))
+	web.RegisterAdminRoutes(r, web.AdminHandlers{
+		Parking:      handler.NewParkingAdminHandler(parkingManager, zapLogger),
+		Authenticate: middlewareMetrics.Authenticate(jwtVerifier, zapLogger),
+	})
 
-	handlerStack = event.WrapExponentialBackoff(
-		zapLogger,
-		promMetrics,
-		"WorkerBackoff",
-		3,
-		1*time.Second,
-		handlerStack,
+	adminSrv := &http.Server{
+		Addr:    ":" + config.WorkerAdminPort,
+		Handler: r,
+	}
+	go func() {
+		zapLogger.Info(ctx, "Worker admin API running", logger.String("port", config.WorkerAdminPort))
+		if err := adminSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zapLogger.Error(ctx, "Worker admin server failed", logger.WithError(err))
+		}
+	}()
+
+	// Consumer Logic
+	retryTopology, err := event.LoadRetryTopology(config.RetryConfigFile)
+	if err != nil {
+		fail("retry config load failed", err)
+	}
+	schemas, err := domainevent.NewSchemaRegistry()
+	if err != nil {
+		fail("event schema registry load failed", err)
+	}
+	consumer := event.NewConsumer(conn, zapLogger,
+		event.WithRetryTopology(retryTopology),
+		event.WithConsumerMetrics(promMetrics),
+		event.WithDrainTimeout(config.WorkerDrainTimeout),
 	)
 
+	store, storeOpts, err := idempotencyStore(ctx, config, rdb, db, zapLogger)
+	if err != nil {
+		fail("idempotency store init failed", err)
+	}
+	guard := idempotency.NewGuard(store, append(storeOpts,
+		idempotency.WithLease(config.IdempotencyLease),
+		idempotency.WithCompletedTTL(config.IdempotencyTTL),
+	)...)
+
+	// Pilha padrão, da mais externa para a mais interna. Upcasting primeiro: versões
+	// futuras e payloads fora do contrato vão ao Parking sem retry.
+	defaultStack := func(name string) []event.Middleware {
+		return []event.Middleware{
+			event.UpcastingMiddleware(zapLogger, schemas),
+			event.BackoffMiddleware(zapLogger, promMetrics, name+"Backoff", 3, 1*time.Second),
+			event.IdempotencyMiddleware(zapLogger, guard, name),
+			event.ResilienceMiddleware(promMetrics, name, 5*time.Second, circuitBreaker),
+			event.InboxMiddleware(zapLogger, name),
+		}
+	}
+
+	orders := event.NewOrderHandler(grpcClient, repository, dispatchUseCaseWithMetrics, zapLogger)
+	if err := consumer.Subscribe(event.Subscription{
+		Queue:      "orders.created",
+		Handler:    orders.ProcessOrder,
+		Fallback:   orders.Fallback,
+		Middleware: defaultStack("WorkerProcessOrder"),
+		Workers:    10,
+		// Circuito fora de closed: o Fleet está com problema, mais workers só pioram.
+		Scaling: &event.Scaling{
+			Min:  2,
+			Max:  32,
+			Hold: func() bool { return circuitBreaker.State() != gobreaker.StateClosed },
+		},
+	}); err != nil {
+		fail("subscription failed", err)
+	}
+
 	// consumer em uma goroutine para não bloquear o shutdown
-	errChan := make(chan error, 1)
+	consumerDone := make(chan error, 1)
 	go func() {
-		zapLogger.Info(ctx, "Starting consumer loop", logger.String("queue", "orders.created"))
-		if err := consumer.Start(ctx, "orders.created", handlerStack); err != nil {
-			zapLogger.Error(ctx, "Consumer failed", logger.WithError(err))
-			errChan <- err
-		}
+		zapLogger.Info(ctx, "Starting consumer")
+		consumerDone <- consumer.Run(ctx)
 	}()
 
 	// Wait for exit signal or error
 	select {
 	case <-ctx.Done():
 		zapLogger.Info(ctx, "Worker stopping gracefully....")
-	case err := <-errChan:
+	case err := <-consumerDone:
 		fail("Worker consumer error", err)
 	}
 
-	//
-	_, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// O drain tem o próprio prazo; a folga cobre o abort dos atrasados e o fechamento do canal.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), config.WorkerDrainTimeout+5*time.Second)
 	defer shutdownCancel()
+	if err := adminSrv.Shutdown(shutdownCtx); err != nil {
+		zapLogger.Error(ctx, "Worker admin server forced to shutdown", logger.WithError(err))
+	}
+
+	// Espera os workers terminarem as mensagens em voo antes de fechar conexões.
+	select {
+	case err := <-consumerDone:
+		if err != nil {
+			zapLogger.Error(ctx, "Consumer stopped with error", logger.WithError(err))
+		}
+	case <-shutdownCtx.Done():
+		zapLogger.Warn(ctx, "Consumer did not stop before shutdown deadline")
+	}
 
 	zapLogger.Info(ctx, "Worker exited")
 }
+
+// idempotencyStore escolhe o store pelo IDEMPOTENCY_STORE. O do Postgres não
+// expira sozinho (sobe o purger junto) e é concluído na transação do despacho.
+func idempotencyStore(ctx context.Context, config *configs.Conf, rdb *redis.Client, db *sql.DB, l logger.Logger) (idempotency.Store, []idempotency.Option, error) {
+	switch config.IdempotencyStore {
+	case "redis":
+		return idempotency.NewRedisStore(rdb), nil, nil
+	case "postgres":
+		store := database.NewIdempotencyStore(db)
+		go idempotency.RunPurger(ctx, store, time.Hour, l)
+		return store, []idempotency.Option{idempotency.WithTxCompletion()}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown IDEMPOTENCY_STORE %q (want redis or postgres)", config.IdempotencyStore)
+}
@@ -1,26 +1,42 @@
 package configs
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Conf struct {
-	DBDriver                 string `mapstructure:"DB_DRIVER"`
-	DBHost                   string `mapstructure:"DB_HOST"`
-	DBPort                   string `mapstructure:"DB_PORT"`
-	DBUser                   string `mapstructure:"DB_USER"`
-	DBPassword               string `mapstructure:"DB_PASSWORD"`
-	DBName                   string `mapstructure:"DB_NAME"`
-	RedisHost                string `mapstructure:"REDIS_HOST"`
-	RedisPort                string `mapstructure:"REDIS_PORT"`
-	WebServerPort            string `mapstructure:"WEB_SERVER_PORT"`
-	GRPCPort                 string `mapstructure:"GRPC_PORT"`
-	AMQPort                  string `mapstructure:"AMQ_PORT"`
-	RabbitMQHost             string `mapstructure:"RABBITMQ_HOST"`
-	FleetHost                string `mapstructure:"FLEET_HOST"`
-	FleetPort                string `mapstructure:"FLEET_PORT"`
-	OtelServiceName          string `mapstructure:"OTEL_SERVICE_NAME"`
-	OtelExporterOTLPEndpoint string `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
-	OtelExporterOTLPInsecure string `mapstructure:"OTEL_EXPORTER_OTLP_INSECURE"`
-	OtelTracesSampler        string `mapstructure:"OTEL_TRACES_SAMPLER"`
+	DBDriver                 string        `mapstructure:"DB_DRIVER"`
+	DBHost                   string        `mapstructure:"DB_HOST"`
+	DBPort                   string        `mapstructure:"DB_PORT"`
+	DBUser                   string        `mapstructure:"DB_USER"`
+	DBPassword               string        `mapstructure:"DB_PASSWORD"`
+	DBName                   string        `mapstructure:"DB_NAME"`
+	RedisHost                string        `mapstructure:"REDIS_HOST"`
+	RedisPort                string        `mapstructure:"REDIS_PORT"`
+	WebServerPort            string        `mapstructure:"WEB_SERVER_PORT"`
+	GRPCPort                 string        `mapstructure:"GRPC_PORT"`
+	AMQPort                  string        `mapstructure:"AMQ_PORT"`
+	RabbitMQHost             string        `mapstructure:"RABBITMQ_HOST"`
+	FleetHost                string        `mapstructure:"FLEET_HOST"`
+	FleetPort                string        `mapstructure:"FLEET_PORT"`
+	OtelServiceName          string        `mapstructure:"OTEL_SERVICE_NAME"`
+	OtelExporterOTLPEndpoint string        `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OtelExporterOTLPInsecure string        `mapstructure:"OTEL_EXPORTER_OTLP_INSECURE"`
+	OtelTracesSampler        string        `mapstructure:"OTEL_TRACES_SAMPLER"`
+	JWTHS256Secret           string        `mapstructure:"JWT_HS256_SECRET"`
+	JWTJWKSFile              string        `mapstructure:"JWT_JWKS_FILE"`
+	JWTIssuer                string        `mapstructure:"JWT_ISSUER"`
+	JWTAudience              string        `mapstructure:"JWT_AUDIENCE"`
+	RateLimitConfigFile      string        `mapstructure:"RATE_LIMIT_CONFIG_FILE"`
+	RetryConfigFile          string        `mapstructure:"RETRY_CONFIG_FILE"`
+	WorkerAdminPort          string        `mapstructure:"WORKER_ADMIN_PORT"`
+	WorkerDrainTimeout       time.Duration `mapstructure:"WORKER_DRAIN_TIMEOUT"`
+	IdempotencyStore         string        `mapstructure:"IDEMPOTENCY_STORE"`
+	IdempotencyLease         time.Duration `mapstructure:"IDEMPOTENCY_LEASE"`
+	IdempotencyTTL           time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+	InboxRetention           time.Duration `mapstructure:"INBOX_RETENTION"`
 }
 
 func LoadConfig(path string, defaultServiceName string) (*Conf, error) {
@@ -32,6 +48,14 @@ func LoadConfig(path string, defaultServiceName string) (*Conf, error) {
 	viper.AutomaticEnv()
 
 	viper.SetDefault("OTEL_SERVICE_NAME", defaultServiceName)
+	viper.SetDefault("RATE_LIMIT_CONFIG_FILE", "configs/ratelimit.yaml")
+	viper.SetDefault("RETRY_CONFIG_FILE", "configs/retry.yaml")
+	viper.SetDefault("WORKER_ADMIN_PORT", "8002")
+	viper.SetDefault("WORKER_DRAIN_TIMEOUT", "20s")
+	viper.SetDefault("IDEMPOTENCY_STORE", "redis")
+	viper.SetDefault("IDEMPOTENCY_LEASE", "30s")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("INBOX_RETENTION", "168h")
 
 	err := viper.ReadInConfig()
 	if err != nil {
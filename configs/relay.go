@@ -0,0 +1,66 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// RelayConf é a configuração do cmd/relay: só o que o publicador do outbox usa.
+type RelayConf struct {
+	DBDriver                 string        `mapstructure:"DB_DRIVER"`
+	DBHost                   string        `mapstructure:"DB_HOST"`
+	DBPort                   string        `mapstructure:"DB_PORT"`
+	DBUser                   string        `mapstructure:"DB_USER"`
+	DBPassword               string        `mapstructure:"DB_PASSWORD"`
+	DBName                   string        `mapstructure:"DB_NAME"`
+	AMQPort                  string        `mapstructure:"AMQ_PORT"`
+	RabbitMQHost             string        `mapstructure:"RABBITMQ_HOST"`
+	OtelServiceName          string        `mapstructure:"OTEL_SERVICE_NAME"`
+	OtelExporterOTLPEndpoint string        `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	JWTHS256Secret           string        `mapstructure:"JWT_HS256_SECRET"`
+	JWTJWKSFile              string        `mapstructure:"JWT_JWKS_FILE"`
+	JWTIssuer                string        `mapstructure:"JWT_ISSUER"`
+	JWTAudience              string        `mapstructure:"JWT_AUDIENCE"`
+	RelayAdminPort           string        `mapstructure:"RELAY_ADMIN_PORT"`
+	RoutingConfigFile        string        `mapstructure:"ROUTING_CONFIG_FILE"`
+	RateLimitConfigFile      string        `mapstructure:"RATE_LIMIT_CONFIG_FILE"`
+	OutboxBatchSize          int32         `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxLanes              int           `mapstructure:"OUTBOX_LANES"`
+	OutboxPublishChannels    int           `mapstructure:"OUTBOX_PUBLISH_CHANNELS"`
+	OutboxMaxAttempts        int32         `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	OutboxBaseBackoff        time.Duration `mapstructure:"OUTBOX_BASE_BACKOFF"`
+	OutboxMaxBackoff         time.Duration `mapstructure:"OUTBOX_MAX_BACKOFF"`
+	OutboxFallbackPoll       time.Duration `mapstructure:"OUTBOX_FALLBACK_POLL"`
+	OutboxLease              time.Duration `mapstructure:"OUTBOX_LEASE"`
+}
+
+func LoadRelayConfig(path string) (*RelayConf, error) {
+	var cfg *RelayConf
+
+	viper.AddConfigPath(path)
+	viper.SetConfigName(".env")
+	viper.SetConfigType("env")
+	viper.AutomaticEnv()
+
+	viper.SetDefault("OTEL_SERVICE_NAME", "gofleet-relay")
+	viper.SetDefault("RELAY_ADMIN_PORT", "8001")
+	viper.SetDefault("ROUTING_CONFIG_FILE", "configs/routing.yaml")
+	viper.SetDefault("RATE_LIMIT_CONFIG_FILE", "configs/ratelimit.yaml")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_LANES", 8)
+	viper.SetDefault("OUTBOX_PUBLISH_CHANNELS", 4)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_BASE_BACKOFF", "1s")
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", "10m")
+	viper.SetDefault("OUTBOX_FALLBACK_POLL", "5s")
+	viper.SetDefault("OUTBOX_LEASE", "30s")
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,114 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifica o erro da aplicação independente do transporte (HTTP, gRPC, AMQP).
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindUnavailable
+	KindUnauthenticated
+	KindForbidden
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindValidation:
+		return "validation"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindUnavailable:
+		return "unavailable"
+	case KindUnauthenticated:
+		return "unauthenticated"
+	case KindForbidden:
+		return "forbidden"
+	default:
+		return "internal"
+	}
+}
+
+// FieldError descreve uma violação de validação em um campo específico do input.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+func NotFound(code, message string, err error) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message, Err: err}
+}
+
+func Conflict(code, message string, err error) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message, Err: err}
+}
+
+func Unavailable(code, message string, err error) *Error {
+	return &Error{Kind: KindUnavailable, Code: code, Message: message, Err: err}
+}
+
+func Unauthenticated(code, message string, err error) *Error {
+	return &Error{Kind: KindUnauthenticated, Code: code, Message: message, Err: err}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func Internal(message string, err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: message, Err: err}
+}
+
+// As extrai o *Error da cadeia. Erros não classificados viram KindInternal.
+func As(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if mapped := FromDomain(err); mapped != nil {
+		return mapped
+	}
+	return Internal("internal error", err)
+}
+
+func KindOf(err error) Kind {
+	if err == nil {
+		return KindInternal
+	}
+	return As(err).Kind
+}
@@ -0,0 +1,42 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/DioGolang/GoFleet/internal/domain/entity"
+)
+
+type domainMapping struct {
+	kind  Kind
+	code  string
+	field string
+}
+
+// domainErrors cobre TODOS os erros sentinela de internal/domain/entity.
+// Ao criar um novo erro de domínio, registre-o aqui.
+var domainErrors = map[error]domainMapping{
+	entity.ErrIDIsRequired:           {KindValidation, "id_required", "id"},
+	entity.ErrInvalidID:              {KindValidation, "id_invalid", "id"},
+	entity.ErrPriceIsRequired:        {KindValidation, "price_required", "price"},
+	entity.ErrPriceMustBePos:         {KindValidation, "price_not_positive", "price"},
+	entity.ErrTaxMustBePos:           {KindValidation, "tax_negative", "tax"},
+	entity.ErrInvalidStateTransition: {KindConflict, "invalid_state_transition", ""},
+	entity.ErrUnknownState:           {KindInternal, "unknown_state", ""},
+}
+
+// FromDomain traduz um erro de domínio para o modelo da aplicação.
+// Retorna nil se o erro não pertence ao domínio.
+func FromDomain(err error) *Error {
+	for domainErr, m := range domainErrors {
+		if !errors.Is(err, domainErr) {
+			continue
+		}
+		appErr := &Error{Kind: m.kind, Code: m.code, Message: domainErr.Error(), Err: err}
+		if m.field != "" {
+			appErr.Message = "validation failed"
+			appErr.Fields = []FieldError{{Field: m.field, Code: m.code, Message: domainErr.Error()}}
+		}
+		return appErr
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import "context"
+
+type Role string
+
+const (
+	RoleCustomer   Role = "customer"
+	RoleDriver     Role = "driver"
+	RoleDispatcher Role = "dispatcher"
+	RoleAdmin      Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleCustomer, RoleDriver, RoleDispatcher, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Principal é a identidade autenticada que executa a requisição.
+// Subject é o "sub" do token: customer_id para clientes, driver_id para motoristas.
+// TenantID vem da claim "tenant_id"; vazio apenas para tokens da própria plataforma.
+type Principal struct {
+	Subject  string
+	Role     Role
+	TenantID string
+}
+
+// HasRole retorna true se o principal possui um dos papéis informados.
+// Admin sempre passa.
+func (p Principal) HasRole(roles ...Role) bool {
+	if p.Role == RoleAdmin {
+		return true
+	}
+	for _, r := range roles {
+		if p.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
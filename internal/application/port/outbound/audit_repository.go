@@ -0,0 +1,18 @@
+package outbound
+
+import "context"
+
+type AuditEntry struct {
+	ActorID      string
+	ActorRole    string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Metadata     map[string]any
+}
+
+// AuditRepository grava a trilha de auditoria. Deve ser usado dentro da
+// mesma UnitOfWork da mutação auditada.
+type AuditRepository interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
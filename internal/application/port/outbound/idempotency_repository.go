@@ -0,0 +1,10 @@
+package outbound
+
+import "context"
+
+// IdempotencyRepository conclui a chave de idempotência da mensagem em
+// processamento. Deve ser usado dentro da mesma UnitOfWork do efeito: a chave
+// só vira COMPLETED se o efeito commitar. Sem chave no ctx, não faz nada.
+type IdempotencyRepository interface {
+	Complete(ctx context.Context, result []byte) error
+}
@@ -0,0 +1,18 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDuplicateMessage indica que o consumer já processou esta mensagem. A
+// transação deve ser desfeita e a mensagem confirmada sem efeito.
+var ErrDuplicateMessage = errors.New("message already processed")
+
+// InboxRepository registra a mensagem em processamento no inbox. Deve ser
+// chamado dentro da mesma UnitOfWork do efeito, antes dele: se a mensagem já
+// estiver lá, devolve ErrDuplicateMessage. Fora de um consumer (sem mensagem no
+// ctx), não faz nada.
+type InboxRepository interface {
+	Accept(ctx context.Context) error
+}
@@ -2,10 +2,16 @@ package outbound
 
 import (
 	"context"
+	"errors"
 
 	"github.com/DioGolang/GoFleet/internal/domain/entity"
 )
 
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrOrderAlreadyExists = errors.New("order already exists")
+)
+
 type OrderRepository interface {
 	Save(ctx context.Context, order *entity.Order) error
 	SaveOutboxEvent(ctx context.Context, eventID, aggID, eventType string, eventVersion int32, payload []byte, topic string) error
@@ -7,6 +7,9 @@ import (
 // RepositoryProvider define o contrato para acessar TODOS os repositórios
 type RepositoryProvider interface {
 	Order() OrderRepository
+	Audit() AuditRepository
+	Idempotency() IdempotencyRepository
+	Inbox() InboxRepository
 	// Futuro:
 	// Account() AccountRepository
 	// Inventory() InventoryRepository
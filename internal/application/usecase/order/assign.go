@@ -0,0 +1,71 @@
+package order
+
+import (
+	"context"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// AssignUseCaseImpl é o despacho manual: um dispatcher escolhe o motorista
+// para pedidos que caíram em MANUAL_DISPATCH (ex: Circuit Breaker aberto).
+type AssignUseCaseImpl struct {
+	UoW    outbound.UnitOfWork
+	Logger logger.Logger
+}
+
+func NewAssignOrderUseCase(uow outbound.UnitOfWork, log logger.Logger) *AssignUseCaseImpl {
+	return &AssignUseCaseImpl{UoW: uow, Logger: log}
+}
+
+func (uc *AssignUseCaseImpl) Execute(ctx context.Context, input AssignInput) (OrderOutput, error) {
+	principal, err := principalFrom(ctx)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+	if !principal.HasRole(auth.RoleDispatcher) {
+		return OrderOutput{}, apperror.Forbidden("insufficient_role", "only dispatchers can assign orders")
+	}
+	if input.DriverID == "" {
+		return OrderOutput{}, apperror.Validation("validation_failed", "validation failed", apperror.FieldError{
+			Field: "driver_id", Code: "required", Message: "is required",
+		})
+	}
+
+	var output OrderOutput
+	err = uc.UoW.Do(ctx, func(provider outbound.RepositoryProvider) error {
+		repo := provider.Order()
+
+		order, err := repo.FindByID(ctx, input.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.StatusName() != "MANUAL_DISPATCH" {
+			return apperror.Conflict("order_not_in_manual_dispatch", "only MANUAL_DISPATCH orders can be assigned", nil)
+		}
+
+		if err := order.Dispatch(input.DriverID); err != nil {
+			return apperror.As(err)
+		}
+		if err := repo.UpdateStatus(ctx, order.ID(), order.StatusName(), order.DriverID()); err != nil {
+			return err
+		}
+
+		output = toOutput(order)
+		return audit(ctx, provider.Audit(), principal, "order.assign", order.ID(), map[string]any{
+			"driver_id": input.DriverID,
+		})
+	})
+	if err != nil {
+		uc.Logger.Warn(ctx, "manual assignment failed", logger.String("order_id", input.OrderID), logger.WithError(err))
+		return OrderOutput{}, persistenceError(err)
+	}
+
+	uc.Logger.Info(ctx, "Order assigned manually",
+		logger.String("order_id", input.OrderID),
+		logger.String("driver_id", input.DriverID),
+	)
+	return output, nil
+}
@@ -0,0 +1,76 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/domain/event"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type CancelUseCaseImpl struct {
+	UoW            outbound.UnitOfWork
+	OrderCancelled events.Event
+	Logger         logger.Logger
+}
+
+func NewCancelOrderUseCase(uow outbound.UnitOfWork, cancelled events.Event, log logger.Logger) *CancelUseCaseImpl {
+	return &CancelUseCaseImpl{
+		UoW:            uow,
+		OrderCancelled: cancelled,
+		Logger:         log,
+	}
+}
+
+func (uc *CancelUseCaseImpl) Execute(ctx context.Context, input CancelInput) (OrderOutput, error) {
+	principal, err := principalFrom(ctx)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	var output OrderOutput
+	err = uc.UoW.Do(ctx, func(provider outbound.RepositoryProvider) error {
+		repo := provider.Order()
+
+		order, err := repo.FindByID(ctx, input.OrderID)
+		if err != nil {
+			return err
+		}
+		if !canCancel(principal, order) {
+			return apperror.Forbidden("order_access_denied", "you cannot cancel this order")
+		}
+
+		previous := order.StatusName()
+		if err := order.Cancel(); err != nil {
+			return apperror.As(err)
+		}
+		if err := repo.UpdateStatus(ctx, order.ID(), order.StatusName(), order.DriverID()); err != nil {
+			return err
+		}
+
+		output = toOutput(order)
+		payload, err := json.Marshal(output)
+		if err != nil {
+			return fmt.Errorf("failed to marshal order for outbox: %w", err)
+		}
+		if err := repo.SaveOutboxEvent(ctx, uuid.New().String(), order.ID(), uc.OrderCancelled.GetName(), event.OrderCancelledVersion, payload, "orders.cancelled"); err != nil {
+			return err
+		}
+
+		return audit(ctx, provider.Audit(), principal, "order.cancel", order.ID(), map[string]any{
+			"from_status": previous,
+		})
+	})
+	if err != nil {
+		uc.Logger.Warn(ctx, "order cancellation failed", logger.String("order_id", input.OrderID), logger.WithError(err))
+		return OrderOutput{}, persistenceError(err)
+	}
+
+	uc.Logger.Info(ctx, "Order cancelled", logger.String("order_id", input.OrderID))
+	return output, nil
+}
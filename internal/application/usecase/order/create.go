@@ -3,12 +3,17 @@ package order
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/auth"
 	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
 	"github.com/DioGolang/GoFleet/internal/domain/entity"
+	"github.com/DioGolang/GoFleet/internal/domain/event"
 	"github.com/DioGolang/GoFleet/pkg/events"
 	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
 	"github.com/google/uuid"
 )
 
@@ -33,9 +38,19 @@ func NewCreateOrderUseCase(
 func (uc *CreateUseCaseImpl) Execute(ctx context.Context, input CreateInput) (CreateOutput, error) {
 	uc.Logger.Info(ctx, "Starting order creation", logger.String("order_id", input.ID))
 
-	order, err := entity.NewOrder(input.ID, input.Price, input.Tax)
+	// Pedidos criados por clientes pertencem a eles (usado nas regras de posse).
+	principal, authenticated := auth.PrincipalFrom(ctx)
+	var opts []entity.OrderOption
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		opts = append(opts, entity.WithTenant(tenantID))
+	}
+	if authenticated && principal.Role == auth.RoleCustomer {
+		opts = append(opts, entity.WithCustomer(principal.Subject))
+	}
+
+	order, err := entity.NewOrder(input.ID, input.Price, input.Tax, opts...)
 	if err != nil {
-		return CreateOutput{}, err
+		return CreateOutput{}, apperror.As(err)
 	}
 
 	output := CreateOutput{
@@ -51,7 +66,12 @@ func (uc *CreateUseCaseImpl) Execute(ctx context.Context, input CreateInput) (Cr
 			return err
 		}
 
-		payloadBytes, err := json.Marshal(output)
+		payloadBytes, err := json.Marshal(event.OrderCreatedPayload{
+			ID:         order.ID(),
+			Price:      order.Price(),
+			Tax:        order.Tax(),
+			FinalPrice: order.FinalPrice(),
+		})
 		if err != nil {
 			return fmt.Errorf("failed to marshal order for outbox: %w", err)
 		}
@@ -61,15 +81,24 @@ func (uc *CreateUseCaseImpl) Execute(ctx context.Context, input CreateInput) (Cr
 			uuid.New().String(),
 			order.ID(),
 			uc.OrderCreated.GetName(),
-			1,
+			event.OrderCreatedVersion,
 			payloadBytes,
 			"orders.created",
 		)
-		return err
+		if err != nil || !authenticated {
+			return err
+		}
+		return audit(ctx, provider.Audit(), principal, "order.create", order.ID(), nil)
 	})
 	if err != nil {
 		uc.Logger.Error(ctx, "failed to execute transactional creation", logger.WithError(err))
-		return CreateOutput{}, err
+		if errors.Is(err, outbound.ErrOrderAlreadyExists) {
+			return CreateOutput{}, apperror.Conflict("order_already_exists", "order already exists", err)
+		}
+		if errors.Is(err, tenant.ErrTenantRequired) || errors.Is(err, events.ErrSchemaViolation) {
+			return CreateOutput{}, persistenceError(err)
+		}
+		return CreateOutput{}, apperror.Unavailable("order_storage_unavailable", "could not persist order", err)
 	}
 	uc.Logger.Info(ctx, "Order created successfully (Atomic Transaction)")
 	return CreateOutput{ID: order.ID(), FinalPrice: order.FinalPrice()}, nil
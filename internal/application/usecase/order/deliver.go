@@ -0,0 +1,55 @@
+package order
+
+import (
+	"context"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+type DeliverUseCaseImpl struct {
+	UoW    outbound.UnitOfWork
+	Logger logger.Logger
+}
+
+func NewDeliverOrderUseCase(uow outbound.UnitOfWork, log logger.Logger) *DeliverUseCaseImpl {
+	return &DeliverUseCaseImpl{UoW: uow, Logger: log}
+}
+
+func (uc *DeliverUseCaseImpl) Execute(ctx context.Context, input DeliverInput) (OrderOutput, error) {
+	principal, err := principalFrom(ctx)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	var output OrderOutput
+	err = uc.UoW.Do(ctx, func(provider outbound.RepositoryProvider) error {
+		repo := provider.Order()
+
+		order, err := repo.FindByID(ctx, input.OrderID)
+		if err != nil {
+			return err
+		}
+		if !canDeliver(principal, order) {
+			return apperror.Forbidden("order_not_assigned", "this order is not assigned to you")
+		}
+
+		if err := order.Deliver(); err != nil {
+			return apperror.As(err)
+		}
+		if err := repo.UpdateStatus(ctx, order.ID(), order.StatusName(), order.DriverID()); err != nil {
+			return err
+		}
+
+		output = toOutput(order)
+		return audit(ctx, provider.Audit(), principal, "order.deliver", order.ID(), nil)
+	})
+	if err != nil {
+		uc.Logger.Warn(ctx, "order delivery failed", logger.String("order_id", input.OrderID), logger.WithError(err))
+		return OrderOutput{}, persistenceError(err)
+	}
+
+	uc.Logger.Info(ctx, "Order delivered", logger.String("order_id", input.OrderID))
+	return output, nil
+}
@@ -2,33 +2,51 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
 	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
 )
 
 type DispatchUseCaseImpl struct {
-	Repo outbound.OrderRepository
+	UoW outbound.UnitOfWork
 }
 
-func NewDispatchUseCase(repo outbound.OrderRepository) *DispatchUseCaseImpl {
-	return &DispatchUseCaseImpl{Repo: repo}
+func NewDispatchUseCase(uow outbound.UnitOfWork) *DispatchUseCaseImpl {
+	return &DispatchUseCaseImpl{UoW: uow}
 }
 
+// Execute roda no worker. A mensagem entra no inbox e a chave de idempotência é
+// concluída na mesma transação do UpdateStatus: ou tudo commita, ou nada, e a
+// reentrega de um evento já aplicado devolve outbound.ErrDuplicateMessage.
 func (uc *DispatchUseCaseImpl) Execute(ctx context.Context, input DispatchInput) error {
-
-	order, err := uc.Repo.FindByID(ctx, input.OrderID)
-	if err != nil {
-		return fmt.Errorf("order not found: %w", err)
-	}
-
-	if err := order.Dispatch(input.DriverID); err != nil {
-		return fmt.Errorf("domain rule violation: %w", err)
-	}
-
-	if err := uc.Repo.UpdateStatus(ctx, order.ID(), order.StatusName(), order.DriverID()); err != nil {
-		return fmt.Errorf("failed to save order: %w", err)
-	}
-
-	return nil
+	return uc.UoW.Do(ctx, func(provider outbound.RepositoryProvider) error {
+		if err := provider.Inbox().Accept(ctx); err != nil {
+			return err
+		}
+
+		repo := provider.Order()
+		order, err := repo.FindByID(ctx, input.OrderID)
+		if err != nil {
+			if errors.Is(err, outbound.ErrOrderNotFound) {
+				return apperror.NotFound("order_not_found", "order not found", err)
+			}
+			return fmt.Errorf("failed to load order: %w", err)
+		}
+
+		if err := order.Dispatch(input.DriverID); err != nil {
+			return fmt.Errorf("domain rule violation: %w", apperror.As(err))
+		}
+
+		if err := repo.UpdateStatus(ctx, order.ID(), order.StatusName(), order.DriverID()); err != nil {
+			return fmt.Errorf("failed to save order: %w", err)
+		}
+
+		if err := provider.Idempotency().Complete(ctx, nil); err != nil {
+			return fmt.Errorf("failed to complete idempotency key: %w", err)
+		}
+
+		return nil
+	})
 }
@@ -13,9 +13,37 @@ type DispatchInput struct {
 	DriverID string
 }
 
+type GetInput struct {
+	OrderID string
+}
+
+type CancelInput struct {
+	OrderID string
+}
+
+type DeliverInput struct {
+	OrderID string
+}
+
+type AssignInput struct {
+	OrderID  string
+	DriverID string `json:"driver_id"`
+}
+
 // Output
 
 type CreateOutput struct {
 	ID         string  `json:"id"`
 	FinalPrice float64 `json:"final_price"`
 }
+
+type OrderOutput struct {
+	ID         string  `json:"id"`
+	TenantID   string  `json:"tenant_id"`
+	CustomerID string  `json:"customer_id,omitempty"`
+	DriverID   string  `json:"driver_id,omitempty"`
+	Price      float64 `json:"price"`
+	Tax        float64 `json:"tax"`
+	FinalPrice float64 `json:"final_price"`
+	Status     string  `json:"status"`
+}
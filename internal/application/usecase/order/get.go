@@ -0,0 +1,47 @@
+package order
+
+import (
+	"context"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/domain/entity"
+)
+
+type GetUseCaseImpl struct {
+	Repo outbound.OrderRepository
+}
+
+func NewGetOrderUseCase(repo outbound.OrderRepository) *GetUseCaseImpl {
+	return &GetUseCaseImpl{Repo: repo}
+}
+
+func (uc *GetUseCaseImpl) Execute(ctx context.Context, input GetInput) (OrderOutput, error) {
+	principal, err := principalFrom(ctx)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	order, err := uc.Repo.FindByID(ctx, input.OrderID)
+	if err != nil {
+		return OrderOutput{}, persistenceError(err)
+	}
+
+	if !canRead(principal, order) {
+		return OrderOutput{}, apperror.Forbidden("order_access_denied", "you cannot access this order")
+	}
+	return toOutput(order), nil
+}
+
+func toOutput(o *entity.Order) OrderOutput {
+	return OrderOutput{
+		ID:         o.ID(),
+		TenantID:   o.TenantID(),
+		CustomerID: o.CustomerID(),
+		DriverID:   o.DriverID(),
+		Price:      o.Price(),
+		Tax:        o.Tax(),
+		FinalPrice: o.FinalPrice(),
+		Status:     o.StatusName(),
+	}
+}
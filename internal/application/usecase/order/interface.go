@@ -11,3 +11,19 @@ type CreateUseCase interface {
 type DispatchUseCase interface {
 	Execute(ctx context.Context, input DispatchInput) error
 }
+
+type GetUseCase interface {
+	Execute(ctx context.Context, input GetInput) (OrderOutput, error)
+}
+
+type CancelUseCase interface {
+	Execute(ctx context.Context, input CancelInput) (OrderOutput, error)
+}
+
+type DeliverUseCase interface {
+	Execute(ctx context.Context, input DeliverInput) (OrderOutput, error)
+}
+
+type AssignUseCase interface {
+	Execute(ctx context.Context, input AssignInput) (OrderOutput, error)
+}
@@ -0,0 +1,102 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/domain/entity"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+)
+
+// Regras de autorização por posse do pedido.
+// O papel já foi checado na borda (middleware); aqui validamos o "de quem é".
+
+func principalFrom(ctx context.Context) (auth.Principal, error) {
+	p, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return auth.Principal{}, apperror.Unauthenticated("missing_principal", "authentication required", nil)
+	}
+	return p, nil
+}
+
+// sameTenant: token de um tenant só alcança pedidos dele. O repositório já
+// filtra pelo tenant do ctx; esta é a segunda barreira. Token da plataforma
+// (sem tenant) passa.
+func sameTenant(p auth.Principal, o *entity.Order) bool {
+	return p.TenantID == "" || p.TenantID == o.TenantID()
+}
+
+func canRead(p auth.Principal, o *entity.Order) bool {
+	if !sameTenant(p, o) {
+		return false
+	}
+	switch p.Role {
+	case auth.RoleAdmin, auth.RoleDispatcher:
+		return true
+	case auth.RoleCustomer:
+		return o.CustomerID() == p.Subject
+	case auth.RoleDriver:
+		return o.DriverID() == p.Subject
+	}
+	return false
+}
+
+func canCancel(p auth.Principal, o *entity.Order) bool {
+	if !sameTenant(p, o) {
+		return false
+	}
+	switch p.Role {
+	case auth.RoleAdmin, auth.RoleDispatcher:
+		return true
+	case auth.RoleCustomer:
+		return o.CustomerID() == p.Subject
+	}
+	return false
+}
+
+func canDeliver(p auth.Principal, o *entity.Order) bool {
+	if !sameTenant(p, o) {
+		return false
+	}
+	switch p.Role {
+	case auth.RoleAdmin:
+		return true
+	case auth.RoleDriver:
+		return o.DriverID() == p.Subject
+	}
+	return false
+}
+
+func audit(ctx context.Context, repo outbound.AuditRepository, p auth.Principal, action, orderID string, metadata map[string]any) error {
+	return repo.Record(ctx, outbound.AuditEntry{
+		ActorID:      p.Subject,
+		ActorRole:    string(p.Role),
+		Action:       action,
+		ResourceType: "Order",
+		ResourceID:   orderID,
+		Metadata:     metadata,
+	})
+}
+
+// persistenceError preserva erros já classificados e traduz os de repositório.
+func persistenceError(err error) error {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if errors.Is(err, outbound.ErrOrderNotFound) {
+		return apperror.NotFound("order_not_found", "order not found", err)
+	}
+	if errors.Is(err, tenant.ErrTenantRequired) {
+		return apperror.Forbidden("tenant_required", "a tenant must be resolved for this operation")
+	}
+	// Payload fora do contrato é bug do produtor, não indisponibilidade.
+	if errors.Is(err, events.ErrSchemaViolation) {
+		return apperror.Internal("event payload violates its schema", err)
+	}
+	return apperror.Unavailable("order_storage_unavailable", "could not access order storage", err)
+}
@@ -0,0 +1,56 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderPolicy(t *testing.T) {
+	// Pedido do cliente cust-1, com o motorista drv-1, no tenant brand-a.
+	o, err := entity.Restore("order-1", 10, 2, 12, "DISPATCHED", "drv-1", "cust-1", "brand-a")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		principal  auth.Principal
+		canRead    bool
+		canCancel  bool
+		canDeliver bool
+	}{
+		{"platform admin", auth.Principal{Subject: "adm", Role: auth.RoleAdmin}, true, true, true},
+		{"tenant admin", auth.Principal{Subject: "adm", Role: auth.RoleAdmin, TenantID: "brand-a"}, true, true, true},
+		{"admin of another tenant", auth.Principal{Subject: "adm", Role: auth.RoleAdmin, TenantID: "brand-b"}, false, false, false},
+		{"dispatcher", auth.Principal{Subject: "dsp", Role: auth.RoleDispatcher, TenantID: "brand-a"}, true, true, false},
+		{"dispatcher of another tenant", auth.Principal{Subject: "dsp", Role: auth.RoleDispatcher, TenantID: "brand-b"}, false, false, false},
+		{"owner customer", auth.Principal{Subject: "cust-1", Role: auth.RoleCustomer, TenantID: "brand-a"}, true, true, false},
+		{"other customer", auth.Principal{Subject: "cust-2", Role: auth.RoleCustomer, TenantID: "brand-a"}, false, false, false},
+		{"owner sub in another tenant", auth.Principal{Subject: "cust-1", Role: auth.RoleCustomer, TenantID: "brand-b"}, false, false, false},
+		{"assigned driver", auth.Principal{Subject: "drv-1", Role: auth.RoleDriver, TenantID: "brand-a"}, true, false, true},
+		{"other driver", auth.Principal{Subject: "drv-2", Role: auth.RoleDriver, TenantID: "brand-a"}, false, false, false},
+		{"assigned sub in another tenant", auth.Principal{Subject: "drv-1", Role: auth.RoleDriver, TenantID: "brand-b"}, false, false, false},
+		{"driver id used as customer", auth.Principal{Subject: "drv-1", Role: auth.RoleCustomer, TenantID: "brand-a"}, false, false, false},
+		{"unknown role", auth.Principal{Subject: "cust-1", Role: auth.Role("root"), TenantID: "brand-a"}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.canRead, canRead(tt.principal, o), "canRead")
+			assert.Equal(t, tt.canCancel, canCancel(tt.principal, o), "canCancel")
+			assert.Equal(t, tt.canDeliver, canDeliver(tt.principal, o), "canDeliver")
+		})
+	}
+}
+
+func TestOrderPolicy_PendingOrderHasNoDriver(t *testing.T) {
+	o, err := entity.Restore("order-1", 10, 2, 12, "PENDING", "", "cust-1", "brand-a")
+	require.NoError(t, err)
+
+	// Pedido ainda sem motorista: nenhum motorista o alcança.
+	driver := auth.Principal{Subject: "drv-1", Role: auth.RoleDriver, TenantID: "brand-a"}
+	assert.False(t, canRead(driver, o))
+	assert.False(t, canDeliver(driver, o))
+}
@@ -15,15 +15,36 @@ type Order struct {
 	finalPrice float64
 	state      OrderState
 	driverID   string
+	customerID string
+	tenantID   string
 }
 
-func NewOrder(id string, price float64, tax float64) (*Order, error) {
+type OrderOption func(*Order)
+
+// WithCustomer define o dono do pedido (sub do token do cliente).
+func WithCustomer(customerID string) OrderOption {
+	return func(o *Order) {
+		o.customerID = customerID
+	}
+}
+
+// WithTenant define o tenant (marca/cliente da plataforma) dono do pedido.
+func WithTenant(tenantID string) OrderOption {
+	return func(o *Order) {
+		o.tenantID = tenantID
+	}
+}
+
+func NewOrder(id string, price float64, tax float64, opts ...OrderOption) (*Order, error) {
 	order := &Order{
 		id:    id,
 		price: price,
 		tax:   tax,
 		state: &PendingState{},
 	}
+	for _, opt := range opts {
+		opt(order)
+	}
 
 	err := order.Validate()
 	if err != nil {
@@ -42,7 +63,10 @@ func (o *Order) Validate() error {
 	if o.id == "" {
 		return ErrIDIsRequired
 	}
-	if o.price <= 0 {
+	if o.price == 0 {
+		return ErrPriceIsRequired
+	}
+	if o.price < 0 {
 		return ErrPriceMustBePos
 	}
 	if o.tax < 0 {
@@ -56,7 +80,7 @@ func (o *Order) CalculateFinalPrice() error {
 	return nil
 }
 
-func Restore(id string, price, tax, finalPrice float64, statusStr string, driverID string) (*Order, error) {
+func Restore(id string, price, tax, finalPrice float64, statusStr string, driverID string, customerID string, tenantID string) (*Order, error) {
 	state, err := ParseState(statusStr)
 	if err != nil {
 		return nil, err
@@ -68,6 +92,8 @@ func Restore(id string, price, tax, finalPrice float64, statusStr string, driver
 		finalPrice: finalPrice,
 		state:      state,
 		driverID:   driverID,
+		customerID: customerID,
+		tenantID:   tenantID,
 	}, nil
 }
 
@@ -95,6 +121,14 @@ func (o *Order) DriverID() string {
 	return o.driverID
 }
 
+func (o *Order) CustomerID() string {
+	return o.customerID
+}
+
+func (o *Order) TenantID() string {
+	return o.tenantID
+}
+
 func (o *Order) Dispatch(driverID string) error {
 	return o.state.Dispatch(o, driverID)
 }
@@ -126,6 +160,6 @@ func ParseState(statusName string) (OrderState, error) {
 	case "CANCELLED":
 		return &CancelledState{}, nil
 	default:
-		return nil, errors.New("unknown state")
+		return nil, ErrUnknownState
 	}
 }
@@ -2,7 +2,10 @@ package entity
 
 import "errors"
 
-var ErrInvalidStateTransition = errors.New("invalid state transition")
+var (
+	ErrInvalidStateTransition = errors.New("invalid state transition")
+	ErrUnknownState           = errors.New("unknown state")
+)
 
 type OrderState interface {
 	Name() string
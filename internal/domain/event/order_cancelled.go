@@ -0,0 +1,33 @@
+package event
+
+import "time"
+
+// OrderCancelledVersion é a versão atual do contrato de OrderCancelled.
+const OrderCancelledVersion int32 = 1
+
+type OrderCancelled struct {
+	Name    string
+	Payload interface{}
+}
+
+func NewOrderCancelled() *OrderCancelled {
+	return &OrderCancelled{
+		Name: "OrderCancelled",
+	}
+}
+
+func (e *OrderCancelled) GetName() string {
+	return e.Name
+}
+
+func (e *OrderCancelled) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrderCancelled) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+func (e *OrderCancelled) GetDateTime() time.Time {
+	return time.Now()
+}
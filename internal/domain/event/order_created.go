@@ -2,6 +2,18 @@ package event
 
 import "time"
 
+// OrderCreatedVersion é a versão atual do contrato de OrderCreated (schemas/OrderCreated.v2.json).
+const OrderCreatedVersion int32 = 2
+
+// OrderCreatedPayload é o payload atual de OrderCreated. Versões antigas chegam
+// aos consumidores já migradas para ele.
+type OrderCreatedPayload struct {
+	ID         string  `json:"id"`
+	Price      float64 `json:"price"`
+	Tax        float64 `json:"tax"`
+	FinalPrice float64 `json:"final_price"`
+}
+
 type OrderCreated struct {
 	Name    string
 	Payload interface{}
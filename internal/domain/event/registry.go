@@ -0,0 +1,40 @@
+package event
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/DioGolang/GoFleet/pkg/events"
+)
+
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// NewSchemaRegistry carrega os contratos de schemas/ e os upcasters entre versões.
+func NewSchemaRegistry() (*events.SchemaRegistry, error) {
+	reg := events.NewSchemaRegistry()
+	if err := reg.LoadFS(schemaFS, "schemas"); err != nil {
+		return nil, fmt.Errorf("load event schemas: %w", err)
+	}
+	reg.RegisterUpcaster("OrderCreated", 1, upcastOrderCreatedV1)
+	return reg, nil
+}
+
+// upcastOrderCreatedV1: a v1 só tinha final_price. Sem o detalhamento, o valor
+// inteiro vira price e tax fica zero, mantendo final_price = price + tax.
+func upcastOrderCreatedV1(payload []byte) ([]byte, error) {
+	var v1 struct {
+		ID         string  `json:"id"`
+		FinalPrice float64 `json:"final_price"`
+	}
+	if err := json.Unmarshal(payload, &v1); err != nil {
+		return nil, err
+	}
+	return json.Marshal(OrderCreatedPayload{
+		ID:         v1.ID,
+		Price:      v1.FinalPrice,
+		Tax:        0,
+		FinalPrice: v1.FinalPrice,
+	})
+}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchemaRegistry_CurrentVersions(t *testing.T) {
+	reg, err := NewSchemaRegistry()
+	require.NoError(t, err)
+
+	v, err := reg.CurrentVersion(NewOrderCreated().GetName())
+	require.NoError(t, err)
+	assert.Equal(t, OrderCreatedVersion, v)
+
+	v, err = reg.CurrentVersion(NewOrderCancelled().GetName())
+	require.NoError(t, err)
+	assert.Equal(t, OrderCancelledVersion, v)
+}
+
+func TestNewSchemaRegistry_OrderCreatedPayloadMatchesSchema(t *testing.T) {
+	reg, err := NewSchemaRegistry()
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(OrderCreatedPayload{ID: "order-1", Price: 90, Tax: 10, FinalPrice: 100})
+	require.NoError(t, err)
+	assert.NoError(t, reg.Validate("OrderCreated", OrderCreatedVersion, payload))
+}
+
+func TestNewSchemaRegistry_UpcastsOrderCreatedV1(t *testing.T) {
+	reg, err := NewSchemaRegistry()
+	require.NoError(t, err)
+
+	out, version, err := reg.Upcast("OrderCreated", 1, []byte(`{"id":"order-1","final_price":110}`))
+	require.NoError(t, err)
+	assert.Equal(t, OrderCreatedVersion, version)
+
+	var got OrderCreatedPayload
+	require.NoError(t, json.Unmarshal(out, &got))
+	assert.Equal(t, OrderCreatedPayload{ID: "order-1", Price: 110, Tax: 0, FinalPrice: 110}, got)
+
+	_, _, err = reg.Upcast("OrderCreated", OrderCreatedVersion+1, []byte(`{}`))
+	assert.ErrorIs(t, err, events.ErrUnknownVersion)
+}
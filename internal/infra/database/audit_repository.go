@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type AuditRepositoryImpl struct {
+	*Queries
+}
+
+func NewAuditRepository(q *Queries) *AuditRepositoryImpl {
+	return &AuditRepositoryImpl{Queries: q}
+}
+
+func (r *AuditRepositoryImpl) Record(ctx context.Context, entry outbound.AuditEntry) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	metadata := []byte("{}")
+	if len(entry.Metadata) > 0 {
+		b, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit metadata: %w", err)
+		}
+		metadata = b
+	}
+
+	var traceID sql.NullString
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		traceID = sql.NullString{String: sc.TraceID().String(), Valid: true}
+	}
+
+	return r.CreateAuditLog(ctx, CreateAuditLogParams{
+		TenantID:     tenantID,
+		ActorID:      entry.ActorID,
+		ActorRole:    entry.ActorRole,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		Metadata:     metadata,
+		TraceID:      traceID,
+	})
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+)
+
+// IdempotencyStore guarda as chaves de idempotência no Postgres. O lease roda
+// fora da transação; a conclusão pode ir na transação do efeito, via
+// RepositoryProvider.Idempotency, para o efeito e a chave valerem juntos.
+type IdempotencyStore struct {
+	q *Queries
+}
+
+func NewIdempotencyStore(db DBTX) *IdempotencyStore {
+	return &IdempotencyStore{q: New(db)}
+}
+
+func (s *IdempotencyStore) Acquire(ctx context.Context, key, owner string, lease time.Duration) (idempotency.Record, error) {
+	row, err := s.q.AcquireIdempotencyKey(ctx, AcquireIdempotencyKeyParams{
+		Key: key, Owner: owner, LeaseSecs: lease.Seconds(),
+	})
+	if err == nil {
+		return idempotency.Record{State: idempotency.State(row.State), Owner: row.Owner, Result: row.Result}, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return idempotency.Record{}, err
+	}
+
+	current, err := s.q.GetIdempotencyKey(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Venceu entre as duas queries: trata como em andamento, o retry a retoma.
+		return idempotency.Record{State: idempotency.StateProcessing}, nil
+	}
+	if err != nil {
+		return idempotency.Record{}, err
+	}
+	return idempotency.Record{State: idempotency.State(current.State), Owner: current.Owner, Result: current.Result}, nil
+}
+
+func (s *IdempotencyStore) Renew(ctx context.Context, key, owner string, lease time.Duration) (bool, error) {
+	n, err := s.q.RenewIdempotencyKey(ctx, RenewIdempotencyKeyParams{
+		LeaseSecs: lease.Seconds(), Key: key, Owner: owner,
+	})
+	return n == 1, err
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key, owner string, result []byte, ttl time.Duration) error {
+	return completeKey(ctx, s.q, key, owner, result, ttl)
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key, owner string) error {
+	return s.q.ReleaseIdempotencyKey(ctx, ReleaseIdempotencyKeyParams{Key: key, Owner: owner})
+}
+
+// PurgeExpired apaga chaves vencidas. Acquire já retoma as vencidas; isto só
+// impede a tabela de crescer com chaves que nunca mais chegam.
+func (s *IdempotencyStore) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.q.DeleteExpiredIdempotencyKeys(ctx)
+}
+
+// IdempotencyRepositoryImpl conclui, na transação do UnitOfWork, a chave da
+// execução que está no ctx (idempotency.Guard com WithTxCompletion). Se a
+// transação não commitar, a chave continua em PROCESSING e o efeito não
+// aconteceu. Sem Claim no ctx (store no Redis) não faz nada.
+type IdempotencyRepositoryImpl struct {
+	q *Queries
+}
+
+func NewIdempotencyRepository(q *Queries) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{q: q}
+}
+
+func (r *IdempotencyRepositoryImpl) Complete(ctx context.Context, result []byte) error {
+	claim, ok := idempotency.ClaimFrom(ctx)
+	if !ok {
+		return nil
+	}
+	return completeKey(ctx, r.q, claim.Key, claim.Owner, result, claim.TTL)
+}
+
+func completeKey(ctx context.Context, q *Queries, key, owner string, result []byte, ttl time.Duration) error {
+	n, err := q.CompleteIdempotencyKey(ctx, CompleteIdempotencyKeyParams{
+		Key: key, Owner: owner, Result: result, TtlSecs: ttl.Seconds(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	if n == 0 {
+		return idempotency.ErrLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore_ReclaimsExpiredLease(t *testing.T) {
+	db := openTestDB(t)
+	store := NewIdempotencyStore(db)
+	ctx := context.Background()
+
+	rec, err := store.Acquire(ctx, "orders:evt-1", "a", 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "a", rec.Owner)
+
+	rec, err = store.Acquire(ctx, "orders:evt-1", "b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "a", rec.Owner)
+
+	time.Sleep(50 * time.Millisecond)
+	rec, err = store.Acquire(ctx, "orders:evt-1", "b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "b", rec.Owner)
+	assert.ErrorIs(t, store.Complete(ctx, "orders:evt-1", "a", nil, time.Hour), idempotency.ErrLeaseLost)
+
+	require.NoError(t, store.Complete(ctx, "orders:evt-1", "b", []byte("done"), time.Hour))
+	rec, err = store.Acquire(ctx, "orders:evt-1", "c", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, idempotency.Record{State: idempotency.StateCompleted, Owner: "b", Result: []byte("done")}, rec)
+}
+
+func TestIdempotencyRepository_CompletesInsideUnitOfWork(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+	store := NewIdempotencyStore(db)
+	guard := idempotency.NewGuard(store, idempotency.WithTxCompletion())
+	ctx := context.Background()
+
+	// Transação desfeita: a chave é liberada e o retry roda de novo.
+	boom := errors.New("boom")
+	_, err := guard.Do(ctx, "orders:evt-1", func(ctx context.Context) ([]byte, error) {
+		return nil, uow.Do(ctx, func(p outbound.RepositoryProvider) error {
+			require.NoError(t, p.Idempotency().Complete(ctx, []byte("partial")))
+			return boom
+		})
+	})
+	assert.ErrorIs(t, err, boom)
+
+	out, err := guard.Do(ctx, "orders:evt-1", func(ctx context.Context) ([]byte, error) {
+		return []byte("done"), uow.Do(ctx, func(p outbound.RepositoryProvider) error {
+			return p.Idempotency().Complete(ctx, []byte("done"))
+		})
+	})
+	require.NoError(t, err)
+	require.NoError(t, out.CompleteErr)
+
+	out, err = guard.Do(ctx, "orders:evt-1", func(context.Context) ([]byte, error) {
+		t.Fatal("completed key must not run again")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Replayed)
+	assert.Equal(t, "done", string(out.Result))
+}
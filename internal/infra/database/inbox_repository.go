@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/infra/inbox"
+	"github.com/lib/pq"
+)
+
+type InboxRepositoryImpl struct {
+	q *Queries
+}
+
+func NewInboxRepository(q *Queries) *InboxRepositoryImpl {
+	return &InboxRepositoryImpl{q: q}
+}
+
+// Accept grava (consumer, event_id) da mensagem do ctx. Uma entrega concorrente
+// do mesmo evento espera o commit da primeira e então esbarra na PK.
+func (r *InboxRepositoryImpl) Accept(ctx context.Context) error {
+	msg, ok := inbox.MessageFrom(ctx)
+	if !ok {
+		return nil
+	}
+	err := r.q.InsertInboxMessage(ctx, InsertInboxMessageParams{Consumer: msg.Consumer, EventID: msg.EventID})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+			return fmt.Errorf("%w: %s/%s", outbound.ErrDuplicateMessage, msg.Consumer, msg.EventID)
+		}
+		return fmt.Errorf("failed to record inbox message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/infra/inbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboxRepository_DuplicateConflictsInsideUnitOfWork(t *testing.T) {
+	db := openTestDB(t)
+	uow := NewUnitOfWork(db)
+	ctx := inbox.WithMessage(context.Background(), inbox.Message{Consumer: "orders", EventID: "evt-1"})
+	accept := func(p outbound.RepositoryProvider) error { return p.Inbox().Accept(ctx) }
+
+	// Efeito falhou: a linha do inbox sai junto no rollback e o retry passa.
+	boom := errors.New("boom")
+	err := uow.Do(ctx, func(p outbound.RepositoryProvider) error {
+		require.NoError(t, accept(p))
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	require.NoError(t, uow.Do(ctx, accept))
+	assert.ErrorIs(t, uow.Do(ctx, accept), outbound.ErrDuplicateMessage)
+
+	// O mesmo evento em outro consumer é outra linha.
+	other := inbox.WithMessage(context.Background(), inbox.Message{Consumer: "billing", EventID: "evt-1"})
+	assert.NoError(t, uow.Do(other, func(p outbound.RepositoryProvider) error { return p.Inbox().Accept(other) }))
+
+	// Fora de um consumer não há mensagem: não grava nada.
+	assert.NoError(t, uow.Do(context.Background(), func(p outbound.RepositoryProvider) error {
+		return p.Inbox().Accept(context.Background())
+	}))
+
+	n, err := New(db).DeleteOldInboxMessages(context.Background(), 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// AdvisoryLeader elege um líder entre réplicas com um advisory lock de sessão.
+// O lock vive na conexão: se ela cair, o Postgres solta o lock e outra réplica assume.
+type AdvisoryLeader struct {
+	db       *sql.DB
+	key      int64
+	interval time.Duration
+	logger   logger.Logger
+	leader   atomic.Bool
+}
+
+func NewAdvisoryLeader(db *sql.DB, key int64, log logger.Logger) *AdvisoryLeader {
+	return &AdvisoryLeader{db: db, key: key, interval: 5 * time.Second, logger: log}
+}
+
+// IsLeader informa se esta réplica detém o lock agora.
+func (l *AdvisoryLeader) IsLeader() bool {
+	return l.leader.Load()
+}
+
+// Lead concorre pelo lock até ctx terminar. Como líder, roda fn com um contexto
+// cancelado quando a sessão que segura o lock deixa de responder.
+func (l *AdvisoryLeader) Lead(ctx context.Context, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		conn, err := l.tryAcquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.logger.Warn(ctx, "Leader election attempt failed", logger.WithError(err))
+		}
+		if conn != nil {
+			l.hold(ctx, conn, fn)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tryAcquire devolve a conexão que segura o lock, ou nil se outra réplica é líder.
+func (l *AdvisoryLeader) tryAcquire(ctx context.Context) (*sql.Conn, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return nil, nil
+	}
+	return conn, nil
+}
+
+func (l *AdvisoryLeader) hold(ctx context.Context, conn *sql.Conn, fn func(ctx context.Context)) {
+	l.leader.Store(true)
+	l.logger.Info(ctx, "Acquired leadership", logger.Any("lock_key", l.key))
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(leaderCtx)
+	}()
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case <-done:
+			break loop
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			if _, err := conn.ExecContext(ctx, "SELECT 1"); err != nil {
+				l.logger.Error(ctx, "Lost leadership session", logger.WithError(err))
+				break loop
+			}
+		}
+	}
+
+	cancel()
+	<-done
+	l.leader.Store(false)
+
+	unlockCtx, unlockCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer unlockCancel()
+	_, _ = conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock($1)", l.key)
+	_ = conn.Close()
+	l.logger.Info(ctx, "Released leadership")
+}
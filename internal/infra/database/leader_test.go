@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvisoryLeader_SingleLeader(t *testing.T) {
+	dsn := os.Getenv("GOFLEET_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("GOFLEET_TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	key := time.Now().UnixNano()
+	log := logger.NewZapLogger("test", false)
+	a := NewAdvisoryLeader(db, key, log)
+	b := NewAdvisoryLeader(db, key, log)
+	a.interval, b.interval = 50*time.Millisecond, 50*time.Millisecond
+
+	var running atomic.Int32
+	var overlap atomic.Bool
+	work := func(ctx context.Context) {
+		if running.Add(1) > 1 {
+			overlap.Store(true)
+		}
+		<-ctx.Done()
+		running.Add(-1)
+	}
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	ctxB, cancelB := context.WithCancel(context.Background())
+	t.Cleanup(cancelA)
+	t.Cleanup(cancelB)
+	go a.Lead(ctxA, work)
+	go b.Lead(ctxB, work)
+
+	require.Eventually(t, func() bool { return a.IsLeader() || b.IsLeader() }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, overlap.Load())
+
+	// Se o líder sai, a outra réplica assume.
+	follower, cancelLeader := b, cancelA
+	if b.IsLeader() {
+		follower, cancelLeader = a, cancelB
+	}
+	cancelLeader()
+	require.Eventually(t, follower.IsLeader, 2*time.Second, 10*time.Millisecond)
+}
@@ -12,6 +12,35 @@ import (
 	"github.com/google/uuid"
 )
 
+type AuditLog struct {
+	ID           uuid.UUID       `json:"id"`
+	ActorID      string          `json:"actor_id"`
+	ActorRole    string          `json:"actor_role"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Metadata     json.RawMessage `json:"metadata"`
+	TraceID      sql.NullString  `json:"trace_id"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	TenantID     string          `json:"tenant_id"`
+}
+
+type IdempotencyKey struct {
+	Key       string    `json:"key"`
+	State     string    `json:"state"`
+	Owner     string    `json:"owner"`
+	Result    []byte    `json:"result"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Inbox struct {
+	Consumer   string    `json:"consumer"`
+	EventID    string    `json:"event_id"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
 type Order struct {
 	ID         string         `json:"id"`
 	Price      string         `json:"price"`
@@ -19,6 +48,8 @@ type Order struct {
 	FinalPrice string         `json:"final_price"`
 	Status     string         `json:"status"`
 	DriverID   sql.NullString `json:"driver_id"`
+	CustomerID sql.NullString `json:"customer_id"`
+	TenantID   string         `json:"tenant_id"`
 }
 
 type Outbox struct {
@@ -36,4 +67,23 @@ type Outbox struct {
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
 	PublishedAt    sql.NullTime    `json:"published_at"`
+	TenantID       string          `json:"tenant_id"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	AggregateSeq   int64           `json:"aggregate_seq"`
+	ClaimedBy      sql.NullString  `json:"claimed_by"`
+	LeaseExpiresAt sql.NullTime    `json:"lease_expires_at"`
+}
+
+type OutboxAggregateSequence struct {
+	TenantID      string `json:"tenant_id"`
+	AggregateType string `json:"aggregate_type"`
+	AggregateID   string `json:"aggregate_id"`
+	LastSeq       int64  `json:"last_seq"`
+}
+
+type OutboxControl struct {
+	ID        bool      `json:"id"`
+	Paused    bool      `json:"paused"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
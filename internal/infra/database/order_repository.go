@@ -3,53 +3,94 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
 	"github.com/DioGolang/GoFleet/internal/domain/entity"
+	"github.com/DioGolang/GoFleet/pkg/events"
 	"github.com/DioGolang/GoFleet/pkg/otel"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// pgUniqueViolation é o SQLSTATE de violação de UNIQUE/PRIMARY KEY.
+const pgUniqueViolation = "23505"
+
+// ErrTenantMismatch indica uma tentativa de gravar entidade de outro tenant.
+var ErrTenantMismatch = errors.New("order belongs to another tenant")
+
 type OrderRepositoryImpl struct {
 	Db *sql.DB
 	*Queries
+	// Schemas, quando presente, valida todo payload antes de gravar no outbox.
+	Schemas *events.SchemaRegistry
 }
 
 func NewOrderRepository(db *sql.DB) *OrderRepositoryImpl {
 	return &OrderRepositoryImpl{Db: db, Queries: New(db)}
 }
 
+// Todas as operações exigem o tenant no contexto: ele é sempre argumento da query,
+// nunca um filtro opcional.
+
 func (r *OrderRepositoryImpl) Save(ctx context.Context, order *entity.Order) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	if order.TenantID() != "" && order.TenantID() != tenantID {
+		return fmt.Errorf("%w: %s", ErrTenantMismatch, order.ID())
+	}
 
 	priceStr := fmt.Sprintf("%.2f", order.Price())
 	taxStr := fmt.Sprintf("%.2f", order.Tax())
 	finalPriceStr := fmt.Sprintf("%.2f", order.FinalPrice())
 
-	err := r.CreateOrder(ctx, CreateOrderParams{
+	err = r.CreateOrder(ctx, CreateOrderParams{
+		TenantID:   tenantID,
 		ID:         order.ID(),
 		Price:      priceStr,
 		Tax:        taxStr,
 		FinalPrice: finalPriceStr,
 		Status:     order.StatusName(),
 		DriverID:   sql.NullString{String: order.DriverID(), Valid: order.DriverID() != ""},
+		CustomerID: sql.NullString{String: order.CustomerID(), Valid: order.CustomerID() != ""},
 	})
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+			return fmt.Errorf("%w: %s", outbound.ErrOrderAlreadyExists, order.ID())
+		}
 		return err
 	}
 	return nil
 }
 
 func (r *OrderRepositoryImpl) SaveOutboxEvent(ctx context.Context, eventID, aggID, eventType string, eventVersion int32, payload []byte, topic string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
 	uid, err := uuid.Parse(eventID)
 	if err != nil {
 		return fmt.Errorf("invalid uuid format for outbox event: %w", err)
 	}
 
+	if r.Schemas != nil {
+		if err := r.Schemas.Validate(eventType, eventVersion, payload); err != nil {
+			return fmt.Errorf("outbox event %s: %w", eventType, err)
+		}
+	}
+
 	traceJSON := otel.ExtractContextToJSON(ctx)
 
 	return r.CreateOutboxEvent(ctx, CreateOutboxEventParams{
 		ID:             uid,
+		TenantID:       tenantID,
 		AggregateType:  "Order",
 		AggregateID:    aggID,
 		EventType:      eventType,
@@ -61,19 +102,41 @@ func (r *OrderRepositoryImpl) SaveOutboxEvent(ctx context.Context, eventID, aggI
 }
 
 func (r *OrderRepositoryImpl) UpdateStatus(ctx context.Context, id string, status string, driverID string) error {
-	return r.UpdateOrderStatus(ctx, UpdateOrderStatusParams{
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	affected, err := r.UpdateOrderStatus(ctx, UpdateOrderStatusParams{
 		Status:   status,
 		DriverID: sql.NullString{String: driverID, Valid: driverID != ""},
+		TenantID: tenantID,
 		ID:       id,
 	})
+	if err != nil {
+		return err
+	}
+	// Pedido inexistente ou de outro tenant: para o chamador é a mesma coisa.
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", outbound.ErrOrderNotFound, id)
+	}
+	return nil
 }
 
 func (r *OrderRepositoryImpl) FindByID(ctx context.Context, id string) (*entity.Order, error) {
-	model, err := r.GetOrder(ctx, id)
+	tenantID, err := tenant.Require(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	model, err := r.GetOrder(ctx, GetOrderParams{TenantID: tenantID, ID: id})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", outbound.ErrOrderNotFound, id)
+		}
+		return nil, err
+	}
+
 	price, err := strconv.ParseFloat(model.Price, 64)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse price for order %s: %w", id, err)
@@ -89,17 +152,14 @@ func (r *OrderRepositoryImpl) FindByID(ctx context.Context, id string) (*entity.
 		return nil, fmt.Errorf("failed to parse final_price for order %s: %w", id, err)
 	}
 
-	driverID := ""
-	if model.DriverID.Valid {
-		driverID = model.DriverID.String
-	}
-
 	return entity.Restore(
 		model.ID,
 		price,
 		tax,
 		finalPrice,
 		model.Status,
-		driverID,
+		model.DriverID.String,
+		model.CustomerID.String,
+		model.TenantID,
 	)
 }
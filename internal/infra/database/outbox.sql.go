@@ -9,14 +9,23 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 const createOutboxEvent = `-- name: CreateOutboxEvent :exec
+WITH seq AS (
+    INSERT INTO outbox_aggregate_sequences (tenant_id, aggregate_type, aggregate_id, last_seq)
+    VALUES ($2, $3, $4, 1)
+    ON CONFLICT (tenant_id, aggregate_type, aggregate_id)
+        DO UPDATE SET last_seq = outbox_aggregate_sequences.last_seq + 1
+    RETURNING last_seq
+)
 INSERT INTO outbox (
     id,
+    tenant_id,
     aggregate_type,
     aggregate_id,
     event_type,
@@ -24,14 +33,16 @@ INSERT INTO outbox (
     payload,
     topic,
     tracing_context,
+    aggregate_seq,
     status
 ) VALUES (
-             $1, $2, $3, $4, $5, $6, $7, $8, 'PENDING'
+             $1, $2, $3, $4, $5, $6, $7, $8, $9, (SELECT last_seq FROM seq), 'PENDING'
          )
 `
 
 type CreateOutboxEventParams struct {
 	ID             uuid.UUID       `json:"id"`
+	TenantID       string          `json:"tenant_id"`
 	AggregateType  string          `json:"aggregate_type"`
 	AggregateID    string          `json:"aggregate_id"`
 	EventType      string          `json:"event_type"`
@@ -41,9 +52,11 @@ type CreateOutboxEventParams struct {
 	TracingContext json.RawMessage `json:"tracing_context"`
 }
 
+// aggregate_seq vem do contador por agregado, incrementado na mesma transação.
 func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error {
 	_, err := q.db.ExecContext(ctx, createOutboxEvent,
 		arg.ID,
+		arg.TenantID,
 		arg.AggregateType,
 		arg.AggregateID,
 		arg.EventType,
@@ -57,35 +70,59 @@ func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventPa
 
 const deleteOldOutboxEvents = `-- name: DeleteOldOutboxEvents :exec
 DELETE FROM outbox
-WHERE status IN ('PUBLISHED', 'FAILED')
+WHERE status = 'PUBLISHED'
   -- O cast ::text força o SQLC a gerar o argumento como string no Go
   AND created_at < NOW() - ($1::text)::interval
 `
 
+// Só PUBLISHED: falhas (DEAD) ficam até alguém decidir (requeue).
 func (q *Queries) DeleteOldOutboxEvents(ctx context.Context, interval string) error {
 	_, err := q.db.ExecContext(ctx, deleteOldOutboxEvents, interval)
 	return err
 }
 
 const fetchPendingOutboxEvents = `-- name: FetchPendingOutboxEvents :many
-SELECT id, event_type, aggregate_id, event_version, payload, topic, tracing_context
-FROM outbox
-WHERE status = 'PENDING'
-ORDER BY created_at ASC
+SELECT o.id, o.aggregate_type, o.event_type, o.aggregate_id, o.event_version, o.payload, o.topic, o.tracing_context, o.tenant_id, o.aggregate_seq, o.created_at
+FROM outbox o
+WHERE o.status = 'PENDING'
+  AND o.next_attempt_at <= NOW()
+  AND NOT EXISTS (
+      SELECT 1
+      FROM outbox prev
+      WHERE prev.tenant_id = o.tenant_id
+        AND prev.aggregate_type = o.aggregate_type
+        AND prev.aggregate_id = o.aggregate_id
+        AND prev.aggregate_seq < o.aggregate_seq
+        AND (
+            prev.status = 'PROCESSING'
+            OR (prev.status = 'PENDING' AND (prev.next_attempt_at > NOW() OR prev.created_at > o.created_at))
+        )
+  )
+  AND pg_try_advisory_xact_lock(hashtextextended(o.tenant_id || '/' || o.aggregate_type || '/' || o.aggregate_id, 0))
+ORDER BY o.created_at ASC, o.aggregate_seq ASC
 LIMIT $1
-FOR UPDATE SKIP LOCKED
+FOR UPDATE OF o SKIP LOCKED
 `
 
 type FetchPendingOutboxEventsRow struct {
 	ID             uuid.UUID       `json:"id"`
+	AggregateType  string          `json:"aggregate_type"`
 	EventType      string          `json:"event_type"`
 	AggregateID    string          `json:"aggregate_id"`
 	EventVersion   int32           `json:"event_version"`
 	Payload        json.RawMessage `json:"payload"`
 	Topic          string          `json:"topic"`
 	TracingContext json.RawMessage `json:"tracing_context"`
+	TenantID       string          `json:"tenant_id"`
+	AggregateSeq   int64           `json:"aggregate_seq"`
+	CreatedAt      time.Time       `json:"created_at"`
 }
 
+// O relay é um processo de sistema: varre todos os tenants e repassa
+// tenant_id como header da mensagem.
+// Um evento só é elegível se nenhum evento anterior do mesmo agregado está em voo
+// (PROCESSING), em backoff, ou foi criado depois dele (commit fora de ordem). O
+// advisory lock impede que dois relays reivindiquem o mesmo agregado ao mesmo tempo.
 func (q *Queries) FetchPendingOutboxEvents(ctx context.Context, limit int32) ([]FetchPendingOutboxEventsRow, error) {
 	rows, err := q.db.QueryContext(ctx, fetchPendingOutboxEvents, limit)
 	if err != nil {
@@ -97,12 +134,16 @@ func (q *Queries) FetchPendingOutboxEvents(ctx context.Context, limit int32) ([]
 		var i FetchPendingOutboxEventsRow
 		if err := rows.Scan(
 			&i.ID,
+			&i.AggregateType,
 			&i.EventType,
 			&i.AggregateID,
 			&i.EventVersion,
 			&i.Payload,
 			&i.Topic,
 			&i.TracingContext,
+			&i.TenantID,
+			&i.AggregateSeq,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -117,55 +158,343 @@ func (q *Queries) FetchPendingOutboxEvents(ctx context.Context, limit int32) ([]
 	return items, nil
 }
 
-const markOutboxAsFailed = `-- name: MarkOutboxAsFailed :exec
-UPDATE outbox
-SET status = 'FAILED',
-error_msg = $2,
-retry_count = retry_count + 1,
-updated_at = NOW()
+const getOutboxEvent = `-- name: GetOutboxEvent :one
+SELECT id, aggregate_type, aggregate_id, event_type, event_version, payload, topic, status, retry_count, tracing_context, error_msg, created_at, updated_at, published_at, tenant_id, next_attempt_at, aggregate_seq, claimed_by, lease_expires_at FROM outbox
 WHERE id = $1
 `
 
-type MarkOutboxAsFailedParams struct {
-	ID       uuid.UUID      `json:"id"`
-	ErrorMsg sql.NullString `json:"error_msg"`
+func (q *Queries) GetOutboxEvent(ctx context.Context, id uuid.UUID) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, getOutboxEvent, id)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.EventVersion,
+		&i.Payload,
+		&i.Topic,
+		&i.Status,
+		&i.RetryCount,
+		&i.TracingContext,
+		&i.ErrorMsg,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishedAt,
+		&i.TenantID,
+		&i.NextAttemptAt,
+		&i.AggregateSeq,
+		&i.ClaimedBy,
+		&i.LeaseExpiresAt,
+	)
+	return i, err
 }
 
-func (q *Queries) MarkOutboxAsFailed(ctx context.Context, arg MarkOutboxAsFailedParams) error {
-	_, err := q.db.ExecContext(ctx, markOutboxAsFailed, arg.ID, arg.ErrorMsg)
-	return err
+const getOutboxPaused = `-- name: GetOutboxPaused :one
+SELECT paused FROM outbox_control WHERE id = TRUE
+`
+
+func (q *Queries) GetOutboxPaused(ctx context.Context) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getOutboxPaused)
+	var paused bool
+	err := row.Scan(&paused)
+	return paused, err
+}
+
+const listOutboxEventsByStatus = `-- name: ListOutboxEventsByStatus :many
+SELECT id, tenant_id, aggregate_type, aggregate_id, aggregate_seq, event_type, topic,
+       status, retry_count, error_msg, next_attempt_at, claimed_by, created_at, updated_at
+FROM outbox
+WHERE status = $1
+ORDER BY created_at ASC
+LIMIT $2
+`
+
+type ListOutboxEventsByStatusParams struct {
+	Status string `json:"status"`
+	Lim    int32  `json:"lim"`
+}
+
+type ListOutboxEventsByStatusRow struct {
+	ID            uuid.UUID      `json:"id"`
+	TenantID      string         `json:"tenant_id"`
+	AggregateType string         `json:"aggregate_type"`
+	AggregateID   string         `json:"aggregate_id"`
+	AggregateSeq  int64          `json:"aggregate_seq"`
+	EventType     string         `json:"event_type"`
+	Topic         string         `json:"topic"`
+	Status        string         `json:"status"`
+	RetryCount    int32          `json:"retry_count"`
+	ErrorMsg      sql.NullString `json:"error_msg"`
+	NextAttemptAt time.Time      `json:"next_attempt_at"`
+	ClaimedBy     sql.NullString `json:"claimed_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// Listagem do admin: sem payload, do mais antigo para o mais novo.
+func (q *Queries) ListOutboxEventsByStatus(ctx context.Context, arg ListOutboxEventsByStatusParams) ([]ListOutboxEventsByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOutboxEventsByStatus, arg.Status, arg.Lim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOutboxEventsByStatusRow
+	for rows.Next() {
+		var i ListOutboxEventsByStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.AggregateSeq,
+			&i.EventType,
+			&i.Topic,
+			&i.Status,
+			&i.RetryCount,
+			&i.ErrorMsg,
+			&i.NextAttemptAt,
+			&i.ClaimedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const markOutboxAsProcessing = `-- name: MarkOutboxAsProcessing :exec
 UPDATE outbox
-SET status = 'PROCESSING', updated_at = NOW()
-WHERE id = ANY($1::uuid[])
+SET status = 'PROCESSING',
+    claimed_by = $1::text,
+    lease_expires_at = NOW() + make_interval(secs => $2::float8),
+    updated_at = NOW()
+WHERE id = ANY($3::uuid[])
 `
 
-func (q *Queries) MarkOutboxAsProcessing(ctx context.Context, ids []uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, markOutboxAsProcessing, pq.Array(ids))
+type MarkOutboxAsProcessingParams struct {
+	Owner     string      `json:"owner"`
+	LeaseSecs float64     `json:"lease_secs"`
+	Ids       []uuid.UUID `json:"ids"`
+}
+
+// Reivindica as linhas para um relay (owner) por lease_secs segundos.
+func (q *Queries) MarkOutboxAsProcessing(ctx context.Context, arg MarkOutboxAsProcessingParams) error {
+	_, err := q.db.ExecContext(ctx, markOutboxAsProcessing, arg.Owner, arg.LeaseSecs, pq.Array(arg.Ids))
 	return err
 }
 
 const markOutboxAsPublished = `-- name: MarkOutboxAsPublished :exec
 UPDATE outbox
-SET status = 'PUBLISHED', published_at = NOW(), updated_at = NOW()
-WHERE id = $1
+SET status = 'PUBLISHED',
+    claimed_by = NULL,
+    lease_expires_at = NULL,
+    published_at = NOW(),
+    updated_at = NOW()
+WHERE id = ANY($1::uuid[])
+`
+
+// Não exige o lease: se o broker confirmou, o evento saiu.
+func (q *Queries) MarkOutboxAsPublished(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markOutboxAsPublished, pq.Array(ids))
+	return err
+}
+
+const outboxStats = `-- name: OutboxStats :many
+SELECT status,
+       COUNT(*) AS depth,
+       EXTRACT(EPOCH FROM NOW() - MIN(created_at))::float8 AS oldest_age_secs
+FROM outbox
+WHERE status <> 'PUBLISHED'
+GROUP BY status
+`
+
+type OutboxStatsRow struct {
+	Status        string  `json:"status"`
+	Depth         int64   `json:"depth"`
+	OldestAgeSecs float64 `json:"oldest_age_secs"`
+}
+
+// Profundidade e idade da linha mais antiga de cada status ainda não publicado.
+func (q *Queries) OutboxStats(ctx context.Context) ([]OutboxStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, outboxStats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxStatsRow
+	for rows.Next() {
+		var i OutboxStatsRow
+		if err := rows.Scan(&i.Status, &i.Depth, &i.OldestAgeSecs); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reclaimExpiredOutboxEvents = `-- name: ReclaimExpiredOutboxEvents :execrows
+UPDATE outbox
+SET status = 'PENDING',
+    claimed_by = NULL,
+    lease_expires_at = NULL,
+    error_msg = 'lease_expired',
+    updated_at = NOW()
+WHERE status = 'PROCESSING'
+  AND lease_expires_at < NOW()
+`
+
+// Só devolve linhas cujo dono parou de renovar o lease (relay morto ou travado).
+func (q *Queries) ReclaimExpiredOutboxEvents(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reclaimExpiredOutboxEvents)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const releaseOutboxEvents = `-- name: ReleaseOutboxEvents :exec
+UPDATE outbox
+SET status = 'PENDING', claimed_by = NULL, lease_expires_at = NULL, updated_at = NOW()
+WHERE id = ANY($1::uuid[])
+  AND status = 'PROCESSING'
+  AND claimed_by = $2::text
 `
 
-func (q *Queries) MarkOutboxAsPublished(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, markOutboxAsPublished, id)
+type ReleaseOutboxEventsParams struct {
+	Ids   []uuid.UUID `json:"ids"`
+	Owner string      `json:"owner"`
+}
+
+// Devolve eventos reivindicados sem contar tentativa (ex: um evento anterior do
+// mesmo agregado falhou e eles não podem sair antes dele).
+func (q *Queries) ReleaseOutboxEvents(ctx context.Context, arg ReleaseOutboxEventsParams) error {
+	_, err := q.db.ExecContext(ctx, releaseOutboxEvents, pq.Array(arg.Ids), arg.Owner)
 	return err
 }
 
-const resetStuckEvents = `-- name: ResetStuckEvents :exec
+const renewOutboxLeases = `-- name: RenewOutboxLeases :execrows
 UPDATE outbox
-SET status = 'PENDING', error_msg = 'stuck_recovery'
+SET lease_expires_at = NOW() + make_interval(secs => $1::float8)
 WHERE status = 'PROCESSING'
-  AND updated_at < NOW() - ($1::text)::interval
+  AND claimed_by = $2::text
+`
+
+type RenewOutboxLeasesParams struct {
+	LeaseSecs float64 `json:"lease_secs"`
+	Owner     string  `json:"owner"`
+}
+
+// Heartbeat do relay: estende o lease de tudo que ele ainda tem em voo.
+func (q *Queries) RenewOutboxLeases(ctx context.Context, arg RenewOutboxLeasesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renewOutboxLeases, arg.LeaseSecs, arg.Owner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requeueFailedOutboxEvents = `-- name: RequeueFailedOutboxEvents :execrows
+UPDATE outbox
+SET status = 'PENDING',
+    retry_count = 0,
+    error_msg = NULL,
+    next_attempt_at = NOW(),
+    updated_at = NOW()
+WHERE status IN ('FAILED', 'DEAD')
+  AND ($1::bool OR id = ANY($2::uuid[]))
 `
 
-func (q *Queries) ResetStuckEvents(ctx context.Context, interval string) error {
-	_, err := q.db.ExecContext(ctx, resetStuckEvents, interval)
+type RequeueFailedOutboxEventsParams struct {
+	AllEvents bool        `json:"all_events"`
+	Ids       []uuid.UUID `json:"ids"`
+}
+
+// Só devolve todas as linhas DEAD (e FAILED legadas) com all explícito; ids vazio não seleciona nada.
+func (q *Queries) RequeueFailedOutboxEvents(ctx context.Context, arg RequeueFailedOutboxEventsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requeueFailedOutboxEvents, arg.AllEvents, pq.Array(arg.Ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const scheduleOutboxRetry = `-- name: ScheduleOutboxRetry :one
+UPDATE outbox
+SET status = CASE WHEN retry_count + 1 >= $1::int THEN 'DEAD' ELSE 'PENDING' END,
+    error_msg = $2,
+    retry_count = retry_count + 1,
+    next_attempt_at = NOW() + make_interval(secs => LEAST(
+        $3::float8,
+        $4::float8 * power(2, retry_count)
+    ) * (0.5 + random() / 2)),
+    claimed_by = NULL,
+    lease_expires_at = NULL,
+    updated_at = NOW()
+WHERE id = $5
+  AND status = 'PROCESSING'
+  AND claimed_by = $6::text
+RETURNING status, retry_count
+`
+
+type ScheduleOutboxRetryParams struct {
+	MaxAttempts     int32          `json:"max_attempts"`
+	ErrorMsg        sql.NullString `json:"error_msg"`
+	MaxBackoffSecs  float64        `json:"max_backoff_secs"`
+	BaseBackoffSecs float64        `json:"base_backoff_secs"`
+	ID              uuid.UUID      `json:"id"`
+	Owner           string         `json:"owner"`
+}
+
+type ScheduleOutboxRetryRow struct {
+	Status     string `json:"status"`
+	RetryCount int32  `json:"retry_count"`
+}
+
+// Backoff exponencial com jitter (50%-100% do atraso), limitado a max_backoff_secs.
+// Na última tentativa a linha vira DEAD e só volta por requeue manual.
+// Sem linha de retorno, o relay perdeu o lease e outro dono assumiu o evento.
+func (q *Queries) ScheduleOutboxRetry(ctx context.Context, arg ScheduleOutboxRetryParams) (ScheduleOutboxRetryRow, error) {
+	row := q.db.QueryRowContext(ctx, scheduleOutboxRetry,
+		arg.MaxAttempts,
+		arg.ErrorMsg,
+		arg.MaxBackoffSecs,
+		arg.BaseBackoffSecs,
+		arg.ID,
+		arg.Owner,
+	)
+	var i ScheduleOutboxRetryRow
+	err := row.Scan(&i.Status, &i.RetryCount)
+	return i, err
+}
+
+const setOutboxPaused = `-- name: SetOutboxPaused :exec
+UPDATE outbox_control
+SET paused = $1, updated_by = $2, updated_at = NOW()
+WHERE id = TRUE
+`
+
+type SetOutboxPausedParams struct {
+	Paused    bool   `json:"paused"`
+	UpdatedBy string `json:"updated_by"`
+}
+
+// Vale para todas as réplicas: o líder relê a flag antes de cada lote.
+func (q *Queries) SetOutboxPaused(ctx context.Context, arg SetOutboxPausedParams) error {
+	_, err := q.db.ExecContext(ctx, setOutboxPaused, arg.Paused, arg.UpdatedBy)
 	return err
 }
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxControl_PauseIsShared(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	// Duas réplicas: uma pausa pelo admin, a outra (líder) lê antes do lote.
+	admin, leader := New(db), New(db)
+
+	paused, err := leader.GetOutboxPaused(ctx)
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	require.NoError(t, admin.SetOutboxPaused(ctx, SetOutboxPausedParams{Paused: true, UpdatedBy: "relay-b"}))
+	paused, err = leader.GetOutboxPaused(ctx)
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	var updatedBy string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT updated_by FROM outbox_control").Scan(&updatedBy))
+	assert.Equal(t, "relay-b", updatedBy)
+
+	require.NoError(t, admin.SetOutboxPaused(ctx, SetOutboxPausedParams{Paused: false, UpdatedBy: "relay-b"}))
+	paused, err = leader.GetOutboxPaused(ctx)
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	// Linha única: o CHECK impede uma segunda linha de controle.
+	_, err = db.ExecContext(ctx, "INSERT INTO outbox_control (id) VALUES (FALSE)")
+	assert.Error(t, err)
+}
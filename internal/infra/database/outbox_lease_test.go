@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReclaimExpiredOutboxEvents_OnlyExpiredLeases(t *testing.T) {
+	db := openTestDB(t)
+	q := New(db)
+	ctx := context.Background()
+
+	create := func() uuid.UUID {
+		id := uuid.New()
+		require.NoError(t, q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+			ID: id, TenantID: "brand-a", AggregateType: "Order", AggregateID: id.String(),
+			EventType: "OrderCreated", EventVersion: 1, Payload: []byte(`{}`),
+			Topic: "orders.created", TracingContext: []byte(`{}`),
+		}))
+		return id
+	}
+	alive, dead := create(), create()
+
+	require.NoError(t, q.MarkOutboxAsProcessing(ctx, MarkOutboxAsProcessingParams{
+		Owner: "relay-a", LeaseSecs: 30, Ids: []uuid.UUID{alive},
+	}))
+	require.NoError(t, q.MarkOutboxAsProcessing(ctx, MarkOutboxAsProcessingParams{
+		Owner: "relay-b", LeaseSecs: 0.01, Ids: []uuid.UUID{dead},
+	}))
+	time.Sleep(50 * time.Millisecond)
+
+	n, err := q.ReclaimExpiredOutboxEvents(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	pending, err := q.FetchPendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, dead, pending[0].ID)
+
+	renewed, err := q.RenewOutboxLeases(ctx, RenewOutboxLeasesParams{LeaseSecs: 30, Owner: "relay-a"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, renewed)
+
+	// Outro dono não consegue devolver nem reagendar a linha de relay-a.
+	require.NoError(t, q.ReleaseOutboxEvents(ctx, ReleaseOutboxEventsParams{Ids: []uuid.UUID{alive}, Owner: "relay-b"}))
+	_, err = q.ScheduleOutboxRetry(ctx, ScheduleOutboxRetryParams{
+		MaxAttempts: 3, MaxBackoffSecs: 60, BaseBackoffSecs: 1, ID: alive, Owner: "relay-b",
+	})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/lib/pq"
+)
+
+// OutboxChannel é o canal LISTEN/NOTIFY disparado pelo trigger de INSERT no outbox.
+const OutboxChannel = "outbox_events"
+
+// OutboxListener converte notificações do Postgres em sinais de wakeup para o relay.
+// Os sinais são fundidos: o relay só precisa saber que há trabalho, não quanto.
+type OutboxListener struct {
+	listener *pq.Listener
+	wake     chan struct{}
+	logger   logger.Logger
+}
+
+func NewOutboxListener(dsn string, log logger.Logger) (*OutboxListener, error) {
+	l := &OutboxListener{
+		wake:   make(chan struct{}, 1),
+		logger: log,
+	}
+	l.listener = pq.NewListener(dsn, 100*time.Millisecond, 10*time.Second, l.onEvent)
+	if err := l.listener.Listen(OutboxChannel); err != nil {
+		_ = l.listener.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Wakeup recebe um sinal por rajada de notificações.
+func (l *OutboxListener) Wakeup() <-chan struct{} {
+	return l.wake
+}
+
+// Run bombeia as notificações até ctx ser cancelado. Um Ping periódico detecta
+// conexões mortas que o TCP não avisou.
+func (l *OutboxListener) Run(ctx context.Context) {
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.listener.Notify:
+			// Uma notificação nil indica reconexão: avisos podem ter se perdido.
+			signal(l.wake)
+		case <-ping.C:
+			if err := l.listener.Ping(); err != nil {
+				l.logger.Warn(ctx, "Outbox listener ping failed", logger.WithError(err))
+			}
+		}
+	}
+}
+
+func (l *OutboxListener) Close() error {
+	return l.listener.Close()
+}
+
+func (l *OutboxListener) onEvent(ev pq.ListenerEventType, err error) {
+	if err != nil {
+		l.logger.Warn(context.Background(), "Outbox listener connection event",
+			logger.Int("event", int(ev)), logger.WithError(err))
+	}
+}
+
+// signal faz um envio não bloqueante: se já há um sinal pendente, este é descartado.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignal_Coalesces(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	signal(ch)
+	signal(ch)
+
+	<-ch
+	select {
+	case <-ch:
+		t.Fatal("expected a single pending wakeup")
+	default:
+	}
+}
+
+func TestOutboxListener_WakesOnInsert(t *testing.T) {
+	db := openTestDB(t)
+	q := New(db)
+
+	l, err := NewOutboxListener(os.Getenv("GOFLEET_TEST_DATABASE_URL"), logger.NewZapLogger("test", false))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go l.Run(ctx)
+
+	require.NoError(t, q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		ID: uuid.New(), TenantID: "brand-a", AggregateType: "Order", AggregateID: "o-1",
+		EventType: "OrderCreated", EventVersion: 1, Payload: []byte(`{}`),
+		Topic: "orders.created", TracingContext: []byte(`{}`),
+	}))
+
+	select {
+	case <-l.Wakeup():
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "no wakeup after insert")
+	}
+}
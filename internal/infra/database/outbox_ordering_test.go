@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchPendingOutboxEvents_RespectsAggregateOrder(t *testing.T) {
+	db := openTestDB(t)
+	q := New(db)
+	ctx := context.Background()
+
+	create := func(aggregateID string) uuid.UUID {
+		id := uuid.New()
+		require.NoError(t, q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+			ID: id, TenantID: "brand-a", AggregateType: "Order", AggregateID: aggregateID,
+			EventType: "OrderCreated", EventVersion: 1, Payload: []byte(`{}`),
+			Topic: "orders.created", TracingContext: []byte(`{}`),
+		}))
+		return id
+	}
+
+	first := create("o-1")
+	second := create("o-1")
+	other := create("o-2")
+
+	pending, err := q.FetchPendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	seqs := map[uuid.UUID]int64{}
+	for _, p := range pending {
+		seqs[p.ID] = p.AggregateSeq
+	}
+	assert.EqualValues(t, 1, seqs[first])
+	assert.EqualValues(t, 2, seqs[second])
+	assert.EqualValues(t, 1, seqs[other])
+
+	// Com o primeiro evento em voo, o segundo do mesmo agregado fica retido.
+	require.NoError(t, q.MarkOutboxAsProcessing(ctx, MarkOutboxAsProcessingParams{
+		Owner: "relay-a", LeaseSecs: 30, Ids: []uuid.UUID{first},
+	}))
+	pending, err = q.FetchPendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, other, pending[0].ID)
+
+	require.NoError(t, q.MarkOutboxAsPublished(ctx, []uuid.UUID{first}))
+	pending, err = q.FetchPendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+}
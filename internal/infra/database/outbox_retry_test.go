@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleOutboxRetry_BacksOffThenDies(t *testing.T) {
+	db := openTestDB(t)
+	q := New(db)
+	ctx := context.Background()
+
+	id := uuid.New()
+	require.NoError(t, q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		ID: id, TenantID: "brand-a", AggregateType: "Order", AggregateID: "o-1",
+		EventType: "OrderCreated", EventVersion: 1, Payload: []byte(`{}`),
+		Topic: "orders.created", TracingContext: []byte(`{}`),
+	}))
+
+	retry := func() ScheduleOutboxRetryRow {
+		require.NoError(t, q.MarkOutboxAsProcessing(ctx, MarkOutboxAsProcessingParams{
+			Owner: "relay-a", LeaseSecs: 30, Ids: []uuid.UUID{id},
+		}))
+		res, err := q.ScheduleOutboxRetry(ctx, ScheduleOutboxRetryParams{
+			MaxAttempts:     3,
+			ErrorMsg:        sql.NullString{String: "nack", Valid: true},
+			MaxBackoffSecs:  600,
+			BaseBackoffSecs: 60,
+			ID:              id,
+			Owner:           "relay-a",
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	assert.Equal(t, "PENDING", retry().Status)
+
+	// Com backoff de no mínimo 30s, o evento não pode ser buscado de imediato.
+	pending, err := q.FetchPendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	assert.Equal(t, "PENDING", retry().Status)
+	last := retry()
+	assert.Equal(t, "DEAD", last.Status)
+	assert.EqualValues(t, 3, last.RetryCount)
+
+	// Sem ids e sem all, nada volta para a fila.
+	n, err := q.RequeueFailedOutboxEvents(ctx, RequeueFailedOutboxEventsParams{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+
+	n, err = q.RequeueFailedOutboxEvents(ctx, RequeueFailedOutboxEventsParams{Ids: []uuid.UUID{id}})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	pending, err = q.FetchPendingOutboxEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, id, pending[0].ID)
+}
+
+func TestDeleteOldOutboxEvents_OnlyPublished(t *testing.T) {
+	db := openTestDB(t)
+	q := New(db)
+	ctx := context.Background()
+
+	create := func(status string) uuid.UUID {
+		id := uuid.New()
+		require.NoError(t, q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+			ID: id, TenantID: "brand-a", AggregateType: "Order", AggregateID: id.String(),
+			EventType: "OrderCreated", EventVersion: 1, Payload: []byte(`{}`),
+			Topic: "orders.created", TracingContext: []byte(`{}`),
+		}))
+		_, err := db.ExecContext(ctx, "UPDATE outbox SET status = $1, created_at = NOW() - INTERVAL '30 days' WHERE id = $2", status, id)
+		require.NoError(t, err)
+		return id
+	}
+	published, dead, failed := create("PUBLISHED"), create("DEAD"), create("FAILED")
+
+	require.NoError(t, q.DeleteOldOutboxEvents(ctx, "7 days"))
+
+	_, err := q.GetOutboxEvent(ctx, published)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	for _, id := range []uuid.UUID{dead, failed} {
+		_, err := q.GetOutboxEvent(ctx, id)
+		assert.NoError(t, err, "failures must wait for a requeue, not be purged")
+	}
+}
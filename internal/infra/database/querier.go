@@ -11,17 +11,34 @@ import (
 )
 
 type Querier interface {
+	AcquireIdempotencyKey(ctx context.Context, arg AcquireIdempotencyKeyParams) (AcquireIdempotencyKeyRow, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (int64, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
 	CreateOrder(ctx context.Context, arg CreateOrderParams) error
 	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	DeleteOldInboxMessages(ctx context.Context, retentionSecs float64) (int64, error)
 	DeleteOldOutboxEvents(ctx context.Context, interval string) error
 	FetchPendingOutboxEvents(ctx context.Context, limit int32) ([]FetchPendingOutboxEventsRow, error)
-	GetOrder(ctx context.Context, id string) (Order, error)
-	ListOrders(ctx context.Context) ([]ListOrdersRow, error)
-	MarkOutboxAsFailed(ctx context.Context, arg MarkOutboxAsFailedParams) error
-	MarkOutboxAsProcessing(ctx context.Context, ids []uuid.UUID) error
-	MarkOutboxAsPublished(ctx context.Context, id uuid.UUID) error
-	ResetStuckEvents(ctx context.Context, interval string) error
-	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) error
+	GetIdempotencyKey(ctx context.Context, key string) (GetIdempotencyKeyRow, error)
+	GetOrder(ctx context.Context, arg GetOrderParams) (Order, error)
+	GetOutboxEvent(ctx context.Context, id uuid.UUID) (Outbox, error)
+	GetOutboxPaused(ctx context.Context) (bool, error)
+	InsertInboxMessage(ctx context.Context, arg InsertInboxMessageParams) error
+	ListOrders(ctx context.Context, tenantID string) ([]ListOrdersRow, error)
+	ListOutboxEventsByStatus(ctx context.Context, arg ListOutboxEventsByStatusParams) ([]ListOutboxEventsByStatusRow, error)
+	MarkOutboxAsProcessing(ctx context.Context, arg MarkOutboxAsProcessingParams) error
+	MarkOutboxAsPublished(ctx context.Context, ids []uuid.UUID) error
+	OutboxStats(ctx context.Context) ([]OutboxStatsRow, error)
+	ReclaimExpiredOutboxEvents(ctx context.Context) (int64, error)
+	ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error
+	ReleaseOutboxEvents(ctx context.Context, arg ReleaseOutboxEventsParams) error
+	RenewIdempotencyKey(ctx context.Context, arg RenewIdempotencyKeyParams) (int64, error)
+	RenewOutboxLeases(ctx context.Context, arg RenewOutboxLeasesParams) (int64, error)
+	RequeueFailedOutboxEvents(ctx context.Context, arg RequeueFailedOutboxEventsParams) (int64, error)
+	ScheduleOutboxRetry(ctx context.Context, arg ScheduleOutboxRetryParams) (ScheduleOutboxRetryRow, error)
+	SetOutboxPaused(ctx context.Context, arg SetOutboxPausedParams) error
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
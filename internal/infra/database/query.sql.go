@@ -11,38 +11,47 @@ import (
 )
 
 const createOrder = `-- name: CreateOrder :exec
-INSERT INTO orders (id, price, tax, final_price, status, driver_id)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO orders (tenant_id, id, price, tax, final_price, status, driver_id, customer_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 `
 
 type CreateOrderParams struct {
+	TenantID   string         `json:"tenant_id"`
 	ID         string         `json:"id"`
 	Price      string         `json:"price"`
 	Tax        string         `json:"tax"`
 	FinalPrice string         `json:"final_price"`
 	Status     string         `json:"status"`
 	DriverID   sql.NullString `json:"driver_id"`
+	CustomerID sql.NullString `json:"customer_id"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) error {
 	_, err := q.db.ExecContext(ctx, createOrder,
+		arg.TenantID,
 		arg.ID,
 		arg.Price,
 		arg.Tax,
 		arg.FinalPrice,
 		arg.Status,
 		arg.DriverID,
+		arg.CustomerID,
 	)
 	return err
 }
 
 const getOrder = `-- name: GetOrder :one
-SELECT id, price, tax, final_price, status, driver_id FROM orders
-WHERE id = $1
+SELECT id, price, tax, final_price, status, driver_id, customer_id, tenant_id FROM orders
+WHERE tenant_id = $1 AND id = $2
 `
 
-func (q *Queries) GetOrder(ctx context.Context, id string) (Order, error) {
-	row := q.db.QueryRowContext(ctx, getOrder, id)
+type GetOrderParams struct {
+	TenantID string `json:"tenant_id"`
+	ID       string `json:"id"`
+}
+
+func (q *Queries) GetOrder(ctx context.Context, arg GetOrderParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, getOrder, arg.TenantID, arg.ID)
 	var i Order
 	err := row.Scan(
 		&i.ID,
@@ -51,12 +60,15 @@ func (q *Queries) GetOrder(ctx context.Context, id string) (Order, error) {
 		&i.FinalPrice,
 		&i.Status,
 		&i.DriverID,
+		&i.CustomerID,
+		&i.TenantID,
 	)
 	return i, err
 }
 
 const listOrders = `-- name: ListOrders :many
 SELECT id, price, tax, final_price FROM orders
+WHERE tenant_id = $1
 `
 
 type ListOrdersRow struct {
@@ -66,8 +78,8 @@ type ListOrdersRow struct {
 	FinalPrice string `json:"final_price"`
 }
 
-func (q *Queries) ListOrders(ctx context.Context) ([]ListOrdersRow, error) {
-	rows, err := q.db.QueryContext(ctx, listOrders)
+func (q *Queries) ListOrders(ctx context.Context, tenantID string) ([]ListOrdersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrders, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -94,19 +106,28 @@ func (q *Queries) ListOrders(ctx context.Context) ([]ListOrdersRow, error) {
 	return items, nil
 }
 
-const updateOrderStatus = `-- name: UpdateOrderStatus :exec
+const updateOrderStatus = `-- name: UpdateOrderStatus :execrows
 UPDATE orders
 SET status = $1, driver_id = $2
-WHERE id = $3
+WHERE tenant_id = $3 AND id = $4
 `
 
 type UpdateOrderStatusParams struct {
 	Status   string         `json:"status"`
 	DriverID sql.NullString `json:"driver_id"`
+	TenantID string         `json:"tenant_id"`
 	ID       string         `json:"id"`
 }
 
-func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) error {
-	_, err := q.db.ExecContext(ctx, updateOrderStatus, arg.Status, arg.DriverID, arg.ID)
-	return err
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateOrderStatus,
+		arg.Status,
+		arg.DriverID,
+		arg.TenantID,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -3,11 +3,24 @@ package database
 import (
 	"context"
 	"fmt"
+
 	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
 	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
 	"github.com/redis/go-redis/v9"
 )
 
+// Cada tenant tem seu próprio índice GEO: uma busca nunca enxerga motoristas de outro tenant.
+const locationKeyPrefix = "drivers_locations:"
+
+func locationKey(ctx context.Context) (string, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	return locationKeyPrefix + tenantID, nil
+}
+
 type RedisLocationRepository struct {
 	client *redis.Client
 	logger logger.Logger
@@ -18,12 +31,18 @@ func NewRedisLocationRepository(client *redis.Client, log logger.Logger) *RedisL
 }
 
 func (r *RedisLocationRepository) GetNearestDrivers(ctx context.Context, lat, lng, radius float64) ([]outbound.DriverLocation, error) {
+	key, err := locationKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.logger.Debug(ctx, "Redis GeoSearch query",
+		logger.String("key", key),
 		logger.Float64("lat", lat),
 		logger.Float64("lng", lng),
 		logger.Float64("radius", radius),
 	)
-	cmd := r.client.GeoSearchLocation(ctx, "drivers_locations",
+	cmd := r.client.GeoSearchLocation(ctx, key,
 		&redis.GeoSearchLocationQuery{
 			GeoSearchQuery: redis.GeoSearchQuery{
 				Latitude:  lat,
@@ -56,13 +75,18 @@ func (r *RedisLocationRepository) GetNearestDrivers(ctx context.Context, lat, ln
 }
 
 func (r *RedisLocationRepository) UpdateLocation(ctx context.Context, driverID string, lat, lng float64) error {
+	key, err := locationKey(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.logger.Debug(ctx, "Redis GeoAdd",
 		logger.String("driver_id", driverID),
 		logger.Float64("lat", lat),
 		logger.Float64("lng", lng),
 	)
 
-	err := r.client.GeoAdd(ctx, "drivers_locations", &redis.GeoLocation{
+	err = r.client.GeoAdd(ctx, key, &redis.GeoLocation{
 		Name:      driverID,
 		Longitude: lng,
 		Latitude:  lat,
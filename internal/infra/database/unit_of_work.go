@@ -6,26 +6,53 @@ import (
 	"fmt"
 
 	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/pkg/events"
 )
 
 type RepositoryProviderImpl struct {
 	db      *sql.DB
 	queries *Queries
+	schemas *events.SchemaRegistry
 }
 
 func (p *RepositoryProviderImpl) Order() outbound.OrderRepository {
 	return &OrderRepositoryImpl{
 		Db:      p.db,
 		Queries: p.queries,
+		Schemas: p.schemas,
 	}
 }
 
+func (p *RepositoryProviderImpl) Audit() outbound.AuditRepository {
+	return NewAuditRepository(p.queries)
+}
+
+func (p *RepositoryProviderImpl) Idempotency() outbound.IdempotencyRepository {
+	return NewIdempotencyRepository(p.queries)
+}
+
+func (p *RepositoryProviderImpl) Inbox() outbound.InboxRepository {
+	return NewInboxRepository(p.queries)
+}
+
 type UnitOfWorkImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	schemas *events.SchemaRegistry
 }
 
-func NewUnitOfWork(db *sql.DB) *UnitOfWorkImpl {
-	return &UnitOfWorkImpl{db: db}
+type UnitOfWorkOption func(*UnitOfWorkImpl)
+
+// WithSchemaRegistry faz os repositórios validarem eventos antes de gravar no outbox.
+func WithSchemaRegistry(reg *events.SchemaRegistry) UnitOfWorkOption {
+	return func(u *UnitOfWorkImpl) { u.schemas = reg }
+}
+
+func NewUnitOfWork(db *sql.DB, opts ...UnitOfWorkOption) *UnitOfWorkImpl {
+	u := &UnitOfWorkImpl{db: db}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
 func (u *UnitOfWorkImpl) Do(ctx context.Context, fn func(provider outbound.RepositoryProvider) error) error {
@@ -37,6 +64,7 @@ func (u *UnitOfWorkImpl) Do(ctx context.Context, fn func(provider outbound.Repos
 	provider := &RepositoryProviderImpl{
 		db:      u.db,
 		queries: New(u.db).WithTx(tx), // SQLC magic
+		schemas: u.schemas,
 	}
 
 	if err := fn(provider); err != nil {
@@ -0,0 +1,169 @@
+package event
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	defaultScaleInterval     = 5 * time.Second
+	defaultDrainWithin       = 30 * time.Second
+	defaultPrefetchPerWorker = 2
+	defaultSpikeFactor       = 3.0
+	// targetUtilization é a fração de tempo ocupado que um worker deve ter em média.
+	targetUtilization = 0.75
+)
+
+// Scaling liga a concorrência dinâmica de uma assinatura: o número de workers
+// varia entre Min e Max pela profundidade da fila e pela latência do handler, e
+// o prefetch acompanha (workers x PrefetchPerWorker).
+type Scaling struct {
+	Min int
+	Max int
+	// Interval entre decisões. Padrão: 5s.
+	Interval time.Duration
+	// DrainWithin é o tempo em que o backlog deve ser drenado. Padrão: 30s.
+	DrainWithin time.Duration
+	// PrefetchPerWorker padrão: 2.
+	PrefetchPerWorker int
+	// MaxLatency congela a escala quando a latência média da janela passa disso.
+	// Zero: só vale o spike relativo (SpikeFactor).
+	MaxLatency time.Duration
+	// SpikeFactor congela a escala quando a latência média da janela passa de
+	// SpikeFactor x a linha de base. Padrão: 3.
+	SpikeFactor float64
+	// Hold congela a escala enquanto devolver true (ex: circuit breaker aberto:
+	// as falhas rápidas derrubam a latência e fariam o pool crescer à toa).
+	Hold func() bool
+}
+
+func (s *Scaling) normalize() error {
+	if s.Min < 1 {
+		return errors.New("scaling min must be >= 1")
+	}
+	if s.Max < s.Min {
+		return errors.New("scaling max must be >= min")
+	}
+	if s.Interval <= 0 {
+		s.Interval = defaultScaleInterval
+	}
+	if s.DrainWithin <= 0 {
+		s.DrainWithin = defaultDrainWithin
+	}
+	if s.PrefetchPerWorker <= 0 {
+		s.PrefetchPerWorker = defaultPrefetchPerWorker
+	}
+	if s.SpikeFactor <= 1 {
+		s.SpikeFactor = defaultSpikeFactor
+	}
+	return nil
+}
+
+// scaleSample é o que aconteceu numa janela do autoscaler.
+type scaleSample struct {
+	Workers   int
+	Depth     int           // mensagens prontas na fila (passive declare)
+	Processed int           // mensagens tratadas na janela
+	Busy      time.Duration // soma do tempo dos handlers na janela
+	Elapsed   time.Duration
+	Held      bool
+}
+
+// Motivos das decisões, usados em log.
+const (
+	scaleHeld         = "held"
+	scaleLatencySpike = "latency-spike"
+	scaleUp           = "scale-up"
+	scaleDown         = "scale-down"
+	scaleSteady       = "steady"
+)
+
+// scaler decide o tamanho do pool. Guarda a linha de base da latência entre janelas.
+type scaler struct {
+	cfg      Scaling
+	baseline time.Duration
+}
+
+// next devolve o número de workers para a próxima janela. O alvo é o maior entre:
+//   - workers para a carga atual a 75% de utilização (tempo ocupado / janela);
+//   - workers para drenar o backlog em DrainWithin (profundidade x latência média).
+//
+// Cresce até dobrar por janela e encolhe um worker por vez, para não oscilar.
+func (s *scaler) next(in scaleSample) (int, string) {
+	cur := in.Workers
+	if in.Held {
+		return cur, scaleHeld
+	}
+
+	var avg time.Duration
+	if in.Processed > 0 {
+		avg = in.Busy / time.Duration(in.Processed)
+	}
+	if avg > 0 {
+		if s.spiking(avg) {
+			return cur, scaleLatencySpike
+		}
+		if s.baseline == 0 {
+			s.baseline = avg
+		} else {
+			s.baseline = time.Duration(0.8*float64(s.baseline) + 0.2*float64(avg))
+		}
+	}
+
+	target := s.cfg.Min
+	if in.Elapsed > 0 && in.Busy > 0 {
+		load := float64(in.Busy) / float64(in.Elapsed)
+		target = max(target, int(math.Ceil(load/targetUtilization)))
+	}
+	if in.Depth > 0 {
+		perMsg := avg
+		if perMsg == 0 {
+			perMsg = s.baseline
+		}
+		if perMsg > 0 {
+			target = max(target, int(math.Ceil(float64(in.Depth)*float64(perMsg)/float64(s.cfg.DrainWithin))))
+		} else {
+			// Backlog sem latência conhecida: cresce devagar até ter amostra.
+			target = max(target, cur+1)
+		}
+	}
+
+	switch {
+	case target > cur && cur < s.cfg.Max:
+		return min(target, cur*2, s.cfg.Max), scaleUp
+	case target < cur && cur > s.cfg.Min:
+		return cur - 1, scaleDown
+	}
+	return cur, scaleSteady
+}
+
+func (s *scaler) spiking(avg time.Duration) bool {
+	if s.cfg.MaxLatency > 0 && avg > s.cfg.MaxLatency {
+		return true
+	}
+	return s.baseline > 0 && float64(avg) > s.cfg.SpikeFactor*float64(s.baseline)
+}
+
+// latencyWindow acumula a latência dos handlers entre decisões do autoscaler.
+type latencyWindow struct {
+	mu    sync.Mutex
+	count int
+	busy  time.Duration
+}
+
+func (w *latencyWindow) observe(d time.Duration) {
+	w.mu.Lock()
+	w.count++
+	w.busy += d
+	w.mu.Unlock()
+}
+
+func (w *latencyWindow) reset() (int, time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	count, busy := w.count, w.busy
+	w.count, w.busy = 0, 0
+	return count, busy
+}
@@ -0,0 +1,149 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaler_Next(t *testing.T) {
+	cfg := Scaling{Min: 2, Max: 20}
+	require.NoError(t, cfg.normalize())
+
+	tests := []struct {
+		name       string
+		baseline   time.Duration
+		in         scaleSample
+		want       int
+		wantReason string
+	}{
+		{
+			name: "backlog grows the pool up to double",
+			in:   scaleSample{Workers: 4, Depth: 3000, Processed: 100, Busy: 10 * time.Second, Elapsed: 5 * time.Second},
+			want: 8, wantReason: scaleUp,
+		},
+		{
+			name: "growth is capped at max",
+			in:   scaleSample{Workers: 16, Depth: 100000, Processed: 100, Busy: 10 * time.Second, Elapsed: 5 * time.Second},
+			want: 20, wantReason: scaleUp,
+		},
+		{
+			name: "busy workers without backlog still grow",
+			// 4 workers 100% ocupados: a 75% precisa de 6.
+			in:   scaleSample{Workers: 4, Processed: 200, Busy: 20 * time.Second, Elapsed: 5 * time.Second},
+			want: 6, wantReason: scaleUp,
+		},
+		{
+			name: "small backlog drained in time keeps the pool",
+			in:   scaleSample{Workers: 4, Depth: 10, Processed: 100, Busy: 12 * time.Second, Elapsed: 5 * time.Second},
+			want: 4, wantReason: scaleSteady,
+		},
+		{
+			name: "idle pool shrinks one worker at a time",
+			in:   scaleSample{Workers: 10, Elapsed: 5 * time.Second},
+			want: 9, wantReason: scaleDown,
+		},
+		{
+			name: "never below min",
+			in:   scaleSample{Workers: 2, Elapsed: 5 * time.Second},
+			want: 2, wantReason: scaleSteady,
+		},
+		{
+			name: "backlog without latency sample grows by one",
+			in:   scaleSample{Workers: 2, Depth: 50, Elapsed: 5 * time.Second},
+			want: 3, wantReason: scaleUp,
+		},
+		{
+			name: "open breaker freezes",
+			in:   scaleSample{Workers: 4, Depth: 3000, Processed: 100, Busy: time.Second, Elapsed: 5 * time.Second, Held: true},
+			want: 4, wantReason: scaleHeld,
+		},
+		{
+			name:     "latency spike freezes",
+			baseline: 20 * time.Millisecond,
+			in:       scaleSample{Workers: 4, Depth: 3000, Processed: 50, Busy: 5 * time.Second, Elapsed: 5 * time.Second},
+			want:     4, wantReason: scaleLatencySpike,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scaler{cfg: cfg, baseline: tt.baseline}
+			got, reason := s.next(tt.in)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestScaler_MaxLatencyFreezes(t *testing.T) {
+	cfg := Scaling{Min: 1, Max: 10, MaxLatency: 500 * time.Millisecond}
+	require.NoError(t, cfg.normalize())
+	s := &scaler{cfg: cfg}
+
+	_, reason := s.next(scaleSample{Workers: 2, Depth: 100, Processed: 4, Busy: 4 * time.Second, Elapsed: 5 * time.Second})
+	assert.Equal(t, scaleLatencySpike, reason)
+	assert.Zero(t, s.baseline, "a spike must not move the baseline")
+}
+
+func TestConsumer_SubscribeWithScaling(t *testing.T) {
+	tests := []struct {
+		name         string
+		sub          Subscription
+		wantErr      string
+		wantWorkers  int
+		wantPrefetch int
+	}{
+		{name: "starts at min", sub: Subscription{Scaling: &Scaling{Min: 2, Max: 20}}, wantWorkers: 2, wantPrefetch: 4},
+		{name: "initial workers clamped to max", sub: Subscription{Workers: 50, Scaling: &Scaling{Min: 2, Max: 20, PrefetchPerWorker: 5}}, wantWorkers: 20, wantPrefetch: 100},
+		{name: "min is required", sub: Subscription{Scaling: &Scaling{Max: 5}}, wantErr: "min must be >= 1"},
+		{name: "max below min", sub: Subscription{Scaling: &Scaling{Min: 5, Max: 2}}, wantErr: "max must be >= min"},
+		{name: "explicit prefetch", sub: Subscription{Prefetch: 10, Scaling: &Scaling{Min: 1, Max: 5}}, wantErr: "prefetch is managed by scaling"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsumer(nil, logger.NewZapLogger("test", false))
+			tt.sub.Queue, tt.sub.Handler = "orders.created", noopHandler
+
+			err := c.Subscribe(tt.sub)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWorkers, c.subs[0].Workers)
+			assert.Equal(t, tt.wantPrefetch, c.subs[0].Prefetch)
+			assert.Equal(t, defaultScaleInterval, c.subs[0].Scaling.Interval)
+		})
+	}
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewConsumer(nil, logger.NewZapLogger("test", false), WithConsumerMetrics(metrics.NewPrometheusMetrics(reg, "test")))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: noopHandler, Scaling: &Scaling{Min: 1, Max: 8}}))
+	pool := newWorkerPool(context.Background(), c, c.subs[0], nil)
+
+	pool.resize(6)
+	pool.resize(2)
+	pool.resize(5) // reaproveita pedidos de saída ainda não atendidos
+
+	workers, _ := pool.current()
+	assert.Equal(t, 5, workers)
+	assert.Equal(t, 5.0, gaugeValue(t, reg, "app_consumer_workers"))
+
+	// Sem consumers, wait fecha `work` e encerra todos os workers.
+	assert.Eventually(t, func() bool { return len(pool.retire) == 0 }, time.Second, time.Millisecond)
+	select {
+	case <-pool.wait():
+	case <-time.After(time.Second):
+		t.Fatal("workers did not stop")
+	}
+}
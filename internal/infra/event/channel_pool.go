@@ -0,0 +1,244 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var ErrPoolClosed = errors.New("publisher channel pool is closed")
+
+const (
+	defaultPoolSize        = 4
+	defaultPoolHealthCheck = 10 * time.Second
+)
+
+// Publisher é o que o Dispatcher precisa para publicar com confirmação.
+// *ConfirmPublisher e *ChannelPool implementam.
+type Publisher interface {
+	Publish(ctx context.Context, dest Destination, msg amqp.Publishing) (*Confirmation, error)
+}
+
+// channelPublisher é um canal em confirm mode. *ConfirmPublisher implementa.
+type channelPublisher interface {
+	Publisher
+	Closed() bool
+	Close() error
+}
+
+type PoolOption func(*ChannelPool)
+
+// WithPoolSize define quantos canais publicam em paralelo.
+func WithPoolSize(n int) PoolOption {
+	return func(p *ChannelPool) {
+		if n > 0 {
+			p.size = n
+		}
+	}
+}
+
+// WithPoolHealthCheck define o intervalo da verificação dos canais ociosos em Run.
+func WithPoolHealthCheck(d time.Duration) PoolOption {
+	return func(p *ChannelPool) {
+		if d > 0 {
+			p.healthEvery = d
+		}
+	}
+}
+
+// PoolStats é uma foto do pool.
+type PoolStats struct {
+	Size     int   `json:"size"`
+	Open     int64 `json:"open"`
+	Replaced int64 `json:"replaced"`
+}
+
+// ChannelPool publica por até `size` canais, cada um em confirm mode e usado
+// por uma publicação de cada vez (canais AMQP não são para publish concorrente).
+//
+// O canal só fica preso durante o basic.publish: a confirmação é resolvida
+// depois pelo listener do próprio canal, então o chamador espera o ack sem
+// segurar o canal. Canais fechados pelo broker (erro de canal, reconexão) são
+// trocados na próxima vez em que saem do pool, ou antes disso pelo Run.
+type ChannelPool struct {
+	open        func() (channelPublisher, error)
+	logger      logger.Logger
+	size        int
+	healthEvery time.Duration
+
+	slots     chan *poolSlot
+	done      chan struct{}
+	closeOnce sync.Once
+
+	opened   atomic.Int64
+	replaced atomic.Int64
+}
+
+// poolSlot é a posse exclusiva de um canal; pub nil é um canal ainda não aberto.
+type poolSlot struct {
+	id  int
+	pub channelPublisher
+}
+
+func NewChannelPool(conn Connector, l logger.Logger, opts ...PoolOption) *ChannelPool {
+	return newChannelPool(func() (channelPublisher, error) {
+		ch, err := conn.Channel()
+		if err != nil {
+			return nil, err
+		}
+		pub, err := NewConfirmPublisher(ch)
+		if err != nil {
+			_ = ch.Close()
+			return nil, err
+		}
+		return pub, nil
+	}, l, opts...)
+}
+
+func newChannelPool(open func() (channelPublisher, error), l logger.Logger, opts ...PoolOption) *ChannelPool {
+	p := &ChannelPool{
+		open:        open,
+		logger:      l,
+		size:        defaultPoolSize,
+		healthEvery: defaultPoolHealthCheck,
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.slots = make(chan *poolSlot, p.size)
+	for i := range p.size {
+		p.slots <- &poolSlot{id: i}
+	}
+	return p
+}
+
+func (p *ChannelPool) Publish(ctx context.Context, dest Destination, msg amqp.Publishing) (*Confirmation, error) {
+	slot, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer p.release(slot)
+
+	if err := p.ensure(ctx, slot); err != nil {
+		return nil, err
+	}
+	conf, err := slot.pub.Publish(ctx, dest, msg)
+	if err != nil && slot.pub.Closed() {
+		// A publicação que pegou o canal morto falha; a próxima sai por um canal novo.
+		p.discard(ctx, slot)
+	}
+	return conf, err
+}
+
+// Run verifica os canais ociosos a cada intervalo: reabre os que caíram e abre
+// os que faltam, para a próxima publicação não pagar o custo do canal novo.
+func (p *ChannelPool) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.healthEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.checkIdle(ctx)
+		}
+	}
+}
+
+func (p *ChannelPool) checkIdle(ctx context.Context) {
+	for range p.size {
+		var slot *poolSlot
+		select {
+		case slot = <-p.slots:
+		default:
+			return // os demais estão publicando
+		}
+		if err := p.ensure(ctx, slot); err != nil {
+			p.logger.Warn(ctx, "Publisher channel health check failed",
+				logger.Int("channel", slot.id),
+				logger.WithError(err),
+			)
+		}
+		p.release(slot)
+	}
+}
+
+func (p *ChannelPool) acquire(ctx context.Context) (*poolSlot, error) {
+	select {
+	case <-p.done:
+		return nil, ErrPoolClosed
+	default:
+	}
+	select {
+	case slot := <-p.slots:
+		return slot, nil
+	case <-p.done:
+		return nil, ErrPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *ChannelPool) release(slot *poolSlot) {
+	p.slots <- slot
+}
+
+// ensure garante um canal aberto no slot, trocando o que o broker fechou.
+func (p *ChannelPool) ensure(ctx context.Context, slot *poolSlot) error {
+	if slot.pub != nil && !slot.pub.Closed() {
+		return nil
+	}
+	if slot.pub != nil {
+		p.discard(ctx, slot)
+	}
+
+	pub, err := p.open()
+	if err != nil {
+		return err
+	}
+	slot.pub = pub
+	p.opened.Add(1)
+	return nil
+}
+
+func (p *ChannelPool) discard(ctx context.Context, slot *poolSlot) {
+	p.logger.Warn(ctx, "Publisher channel closed, replacing", logger.Int("channel", slot.id))
+	_ = slot.pub.Close()
+	slot.pub = nil
+	p.opened.Add(-1)
+	p.replaced.Add(1)
+}
+
+func (p *ChannelPool) Stats() PoolStats {
+	return PoolStats{Size: p.size, Open: p.opened.Load(), Replaced: p.replaced.Load()}
+}
+
+// Close espera as publicações em andamento e fecha todos os canais.
+func (p *ChannelPool) Close() error {
+	var errs []error
+	p.closeOnce.Do(func() {
+		close(p.done)
+		for range p.size {
+			slot := <-p.slots
+			if slot.pub == nil {
+				continue
+			}
+			if err := slot.pub.Close(); err != nil {
+				errs = append(errs, err)
+			}
+			slot.pub = nil
+			p.opened.Add(-1)
+		}
+	})
+	return errors.Join(errs...)
+}
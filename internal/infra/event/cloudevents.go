@@ -0,0 +1,174 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Binding AMQP do CloudEvents em binary content mode: atributos viram headers
+// com prefixo "cloudEvents_" (também aceitamos o legado "cloudEvents:") e o
+// datacontenttype vai na propriedade content-type.
+const (
+	ceHeaderPrefix       = "cloudEvents_"
+	ceLegacyHeaderPrefix = "cloudEvents:"
+)
+
+// DefaultEventSource identifica o GoFleet como produtor dos eventos.
+const DefaultEventSource = "/gofleet/api"
+
+// toPublishing serializa o envelope em binary content mode.
+func toPublishing(evt events.CloudEvent, headers amqp.Table) amqp.Publishing {
+	if headers == nil {
+		headers = make(amqp.Table)
+	}
+	headers[ceHeaderPrefix+"id"] = evt.ID
+	headers[ceHeaderPrefix+"source"] = evt.Source
+	headers[ceHeaderPrefix+"type"] = evt.Type
+	headers[ceHeaderPrefix+"specversion"] = evt.SpecVersion
+	if !evt.Time.IsZero() {
+		headers[ceHeaderPrefix+"time"] = evt.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if evt.Subject != "" {
+		headers[ceHeaderPrefix+"subject"] = evt.Subject
+	}
+	for k, v := range evt.Extensions {
+		headers[ceHeaderPrefix+k] = v
+	}
+
+	return amqp.Publishing{
+		Headers:      headers,
+		ContentType:  evt.DataContentType,
+		Timestamp:    evt.Time,
+		MessageId:    evt.ID,
+		Type:         evt.Type,
+		DeliveryMode: amqp.Persistent,
+		Body:         evt.Data,
+	}
+}
+
+// ParseDelivery lê uma mensagem em binary ou structured content mode. Mensagens
+// sem atributos CloudEvents (publicadas antes do envelope) caem no formato legado.
+func ParseDelivery(d amqp.Delivery) (events.CloudEvent, error) {
+	var evt events.CloudEvent
+
+	switch {
+	case isStructured(d.ContentType):
+		if err := evt.UnmarshalJSON(d.Body); err != nil {
+			return events.CloudEvent{}, err
+		}
+	case hasCEHeaders(d.Headers):
+		evt.DataContentType = d.ContentType
+		evt.Data = d.Body
+		for k, v := range d.Headers {
+			name, ok := ceAttribute(k)
+			if !ok {
+				continue
+			}
+			if err := evt.SetAttribute(name, headerString(v)); err != nil {
+				return events.CloudEvent{}, err
+			}
+		}
+	default:
+		evt = legacyEnvelope(d)
+	}
+
+	if err := evt.Validate(); err != nil {
+		return events.CloudEvent{}, err
+	}
+	return evt, nil
+}
+
+// legacyEventTypes traduz a routing key (topic do outbox) das mensagens
+// publicadas antes do envelope, que saíam sem o tipo do evento.
+var legacyEventTypes = map[string]string{
+	"orders.created":   events.TypeFor("Order", "OrderCreated"),
+	"orders.cancelled": events.TypeFor("Order", "OrderCancelled"),
+}
+
+// legacyEnvelope adapta mensagens com os headers antigos (x-event-id, x-event-version).
+func legacyEnvelope(d amqp.Delivery) events.CloudEvent {
+	evt := events.CloudEvent{
+		ID:              headerString(d.Headers["x-event-id"]),
+		Source:          DefaultEventSource,
+		Type:            legacyType(d),
+		SpecVersion:     events.SpecVersion,
+		Time:            d.Timestamp,
+		Subject:         headerString(d.Headers["x-aggregate-id"]),
+		DataContentType: d.ContentType,
+		Data:            d.Body,
+	}
+	if evt.ID == "" {
+		evt.ID = d.MessageId
+	}
+	ext := map[string]string{
+		events.ExtDataVersion: headerString(d.Headers["x-event-version"]),
+		events.ExtTenantID:    headerString(d.Headers[tenant.Header]),
+	}
+	for k, v := range ext {
+		if v != "" {
+			if evt.Extensions == nil {
+				evt.Extensions = make(map[string]string)
+			}
+			evt.Extensions[k] = v
+		}
+	}
+	return evt
+}
+
+// legacyType: propriedade type ou header x-event-type, quando o produtor os
+// mandou; senão o tipo do topic. Routing key desconhecida segue como type e o
+// evento vai ao Parking como unknown-event-type.
+func legacyType(d amqp.Delivery) string {
+	if d.Type != "" {
+		return d.Type
+	}
+	if t := headerString(d.Headers["x-event-type"]); t != "" {
+		return t
+	}
+	if t, ok := legacyEventTypes[d.RoutingKey]; ok {
+		return t
+	}
+	return d.RoutingKey
+}
+
+func isStructured(contentType string) bool {
+	return mediaType(contentType) == events.StructuredContentType
+}
+
+func hasCEHeaders(h amqp.Table) bool {
+	for k := range h {
+		if _, ok := ceAttribute(k); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func ceAttribute(header string) (string, bool) {
+	for _, prefix := range []string{ceHeaderPrefix, ceLegacyHeaderPrefix} {
+		if strings.HasPrefix(header, prefix) {
+			return strings.TrimPrefix(header, prefix), true
+		}
+	}
+	return "", false
+}
+
+func headerString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(t)
+	}
+}
@@ -0,0 +1,110 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEvents_BinaryRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	evt := events.CloudEvent{
+		ID:              "evt-1",
+		Source:          DefaultEventSource,
+		Type:            "io.gofleet.order.OrderCreated",
+		SpecVersion:     events.SpecVersion,
+		Time:            at,
+		Subject:         "order-1",
+		DataContentType: "application/json",
+		Data:            []byte(`{"id":"order-1"}`),
+		Extensions:      map[string]string{events.ExtTenantID: "acme", events.ExtSequence: "7"},
+	}
+
+	pub := toPublishing(evt, amqp.Table{"traceparent": "00-abc"})
+	assert.Equal(t, "evt-1", pub.MessageId)
+	assert.Equal(t, "application/json", pub.ContentType)
+	assert.Equal(t, "order-1", pub.Headers["cloudEvents_subject"])
+	assert.Equal(t, "00-abc", pub.Headers["traceparent"])
+
+	got, err := ParseDelivery(amqp.Delivery{Headers: pub.Headers, ContentType: pub.ContentType, Body: pub.Body})
+	require.NoError(t, err)
+	assert.True(t, at.Equal(got.Time))
+	got.Time = at
+	assert.Equal(t, evt, got)
+}
+
+func TestCloudEvents_ParseStructured(t *testing.T) {
+	body := []byte(`{"specversion":"1.0","id":"evt-2","source":"/other","type":"io.gofleet.order.OrderPaid",` +
+		`"subject":"order-9","tenantid":"acme","datacontenttype":"application/json","data":{"id":"order-9"}}`)
+
+	got, err := ParseDelivery(amqp.Delivery{ContentType: "application/cloudevents+json; charset=utf-8", Body: body})
+	require.NoError(t, err)
+	assert.Equal(t, "evt-2", got.ID)
+	assert.Equal(t, "order-9", got.Subject)
+	assert.Equal(t, "acme", got.Extension(events.ExtTenantID))
+	assert.JSONEq(t, `{"id":"order-9"}`, string(got.Data))
+}
+
+func TestCloudEvents_ParseLegacyHeaders(t *testing.T) {
+	got, err := ParseDelivery(amqp.Delivery{
+		Headers:     amqp.Table{"x-event-id": "evt-3", "x-event-version": "2", tenant.Header: "acme"},
+		ContentType: "application/json",
+		Type:        "OrderCreated",
+		Body:        []byte(`{}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "evt-3", got.ID)
+	assert.Equal(t, "OrderCreated", got.Type)
+	assert.Equal(t, "2", got.Extension(events.ExtDataVersion))
+	assert.Equal(t, "acme", got.Extension(events.ExtTenantID))
+}
+
+// baselineDelivery é a mensagem exatamente como o relay anterior ao envelope a
+// publicava (DispatchRaw): sem type, só os headers x-* e a routing key do topic.
+func baselineDelivery(routingKey string, body []byte) amqp.Delivery {
+	return amqp.Delivery{
+		Headers: amqp.Table{
+			"x-event-version": "1",
+			"x-event-id":      "evt-legacy",
+			"x-aggregate-id":  "order-1",
+			"traceparent":     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		ContentType:  "application/json",
+		Timestamp:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		MessageId:    "evt-legacy",
+		DeliveryMode: amqp.Persistent,
+		Exchange:     "orders_exchange",
+		RoutingKey:   routingKey,
+		Body:         body,
+	}
+}
+
+func TestCloudEvents_ParseBaselineRelayMessage(t *testing.T) {
+	got, err := ParseDelivery(baselineDelivery("orders.created", []byte(`{"id":"order-1","final_price":110}`)))
+	require.NoError(t, err)
+	assert.Equal(t, "evt-legacy", got.ID)
+	assert.Equal(t, "order-1", got.Subject)
+	assert.Equal(t, "OrderCreated", got.EventType())
+
+	withHeader := baselineDelivery("orders.unknown", []byte(`{}`))
+	withHeader.Headers["x-event-type"] = "OrderCancelled"
+	got, err = ParseDelivery(withHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "OrderCancelled", got.EventType())
+}
+
+func TestCloudEvents_ParseRejectsIncompleteEnvelope(t *testing.T) {
+	_, err := ParseDelivery(amqp.Delivery{
+		Headers: amqp.Table{"cloudEvents_id": "evt-4", "cloudEvents_specversion": "1.0"},
+		Body:    []byte(`{}`),
+	})
+	assert.ErrorIs(t, err, events.ErrInvalidCloudEvent)
+
+	_, err = ParseDelivery(amqp.Delivery{Body: []byte(`{}`)})
+	assert.ErrorIs(t, err, events.ErrInvalidCloudEvent)
+}
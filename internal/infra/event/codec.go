@@ -0,0 +1,96 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	domainevent "github.com/DioGolang/GoFleet/internal/domain/event"
+	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Encodings de payload aceitos em routing.yaml.
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// ErrUndecodable indica um payload que não pode ser convertido de volta para JSON
+// (content type desconhecido, mensagem protobuf não registrada ou corrompida).
+var ErrUndecodable = errors.New("undecodable event payload")
+
+type codecKey struct {
+	eventType string
+	version   int32
+}
+
+// ProtoCodec converte entre o JSON gravado no outbox e a mensagem protobuf de
+// cada (event_type, versão). Os nomes dos campos JSON são os do .proto, então a
+// conversão é feita via protojson sem mapeamento manual.
+type ProtoCodec struct {
+	messages map[codecKey]func() proto.Message
+}
+
+func NewProtoCodec() *ProtoCodec {
+	return &ProtoCodec{messages: make(map[codecKey]func() proto.Message)}
+}
+
+// NewOrderEventsCodec registra as mensagens de order_events.proto nas versões atuais.
+func NewOrderEventsCodec() *ProtoCodec {
+	c := NewProtoCodec()
+	c.Register("OrderCreated", domainevent.OrderCreatedVersion, func() proto.Message { return &pb.OrderCreatedEvent{} })
+	c.Register("OrderCancelled", domainevent.OrderCancelledVersion, func() proto.Message { return &pb.OrderCancelledEvent{} })
+	return c
+}
+
+func (c *ProtoCodec) Register(eventType string, version int32, newMsg func() proto.Message) {
+	c.messages[codecKey{eventType, version}] = newMsg
+}
+
+// Supports informa se há mensagem protobuf para (eventType, version).
+func (c *ProtoCodec) Supports(eventType string, version int32) bool {
+	_, ok := c.messages[codecKey{eventType, version}]
+	return ok
+}
+
+// Encode converte o payload JSON em protobuf.
+func (c *ProtoCodec) Encode(eventType string, version int32, payload []byte) ([]byte, error) {
+	newMsg, ok := c.messages[codecKey{eventType, version}]
+	if !ok {
+		return nil, fmt.Errorf("no protobuf message for %s v%d", eventType, version)
+	}
+	msg := newMsg()
+	if err := protojson.Unmarshal(payload, msg); err != nil {
+		return nil, fmt.Errorf("encode %s v%d: %w", eventType, version, err)
+	}
+	return proto.Marshal(msg)
+}
+
+// Decode converte protobuf de volta no JSON do contrato (nomes do .proto, campos
+// zerados presentes), para que upcasters e handlers só conheçam JSON.
+func (c *ProtoCodec) Decode(eventType string, version int32, data []byte) ([]byte, error) {
+	newMsg, ok := c.messages[codecKey{eventType, version}]
+	if !ok {
+		return nil, fmt.Errorf("%w: no protobuf message for %s v%d", ErrUndecodable, eventType, version)
+	}
+	msg := newMsg()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("%w: %s v%d: %v", ErrUndecodable, eventType, version, err)
+	}
+	out, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s v%d: %v", ErrUndecodable, eventType, version, err)
+	}
+	return out, nil
+}
+
+func mediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+}
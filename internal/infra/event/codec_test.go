@@ -0,0 +1,82 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtoCodec_RoundTrip(t *testing.T) {
+	codec := NewOrderEventsCodec()
+	payload := []byte(`{"id":"order-1","price":90.5,"tax":0,"final_price":90.5}`)
+
+	data, err := codec.Encode("OrderCreated", 2, payload)
+	require.NoError(t, err)
+	assert.Less(t, len(data), len(payload))
+
+	var msg pb.OrderCreatedEvent
+	require.NoError(t, proto.Unmarshal(data, &msg))
+	assert.Equal(t, "order-1", msg.GetId())
+	assert.Equal(t, 90.5, msg.GetFinalPrice())
+
+	back, err := codec.Decode("OrderCreated", 2, data)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(payload), string(back), "campos zerados também voltam no JSON")
+
+	_, err = codec.Decode("OrderCreated", 1, data)
+	assert.ErrorIs(t, err, ErrUndecodable)
+	_, err = codec.Decode("OrderCreated", 2, []byte{0xff, 0xff})
+	assert.ErrorIs(t, err, ErrUndecodable)
+}
+
+func TestDispatcher_EncodePerTopic(t *testing.T) {
+	ed := &Dispatcher{
+		Routes: &RoutingTable{Encodings: map[string]string{"orders.created": EncodingProtobuf}},
+		Logger: logger.NewZapLogger("test", false),
+		Codec:  NewOrderEventsCodec(),
+	}
+	msg := events.RawMessage{
+		ID: "evt-1", AggregateType: "Order", EventType: "OrderCreated",
+		Payload:    []byte(`{"id":"order-1","price":10,"tax":1,"final_price":11}`),
+		Extensions: map[string]string{events.ExtDataVersion: "2"},
+	}
+
+	ce := ed.envelope(msg)
+	require.NoError(t, ed.encode(context.Background(), &ce, msg, "orders.created"))
+	assert.Equal(t, ContentTypeProtobuf, ce.DataContentType)
+
+	// Topic sem configuração continua em JSON.
+	ce = ed.envelope(msg)
+	require.NoError(t, ed.encode(context.Background(), &ce, msg, "orders.cancelled"))
+	assert.Equal(t, ContentTypeJSON, ce.DataContentType)
+	assert.Equal(t, msg.Payload, ce.Data)
+
+	// Versão sem mensagem protobuf cai para JSON.
+	msg.Extensions = map[string]string{events.ExtDataVersion: "1"}
+	ce = ed.envelope(msg)
+	require.NoError(t, ed.encode(context.Background(), &ce, msg, "orders.created"))
+	assert.Equal(t, ContentTypeJSON, ce.DataContentType)
+}
+
+func TestConsumer_DecodeData(t *testing.T) {
+	c := &Consumer{Codec: NewOrderEventsCodec()}
+	data, err := c.Codec.Encode("OrderCreated", 2, []byte(`{"id":"order-1","price":10,"tax":1,"final_price":11}`))
+	require.NoError(t, err)
+
+	evt := events.CloudEvent{
+		Type: "io.gofleet.order.OrderCreated", DataContentType: ContentTypeProtobuf + "; proto=pb.OrderCreatedEvent",
+		Data: data, Extensions: map[string]string{events.ExtDataVersion: "2"},
+	}
+	require.NoError(t, c.decodeData(&evt))
+	assert.Equal(t, ContentTypeJSON, evt.DataContentType)
+	assert.JSONEq(t, `{"id":"order-1","price":10,"tax":1,"final_price":11}`, string(evt.Data))
+
+	evt = events.CloudEvent{DataContentType: "application/xml", Data: []byte("<a/>")}
+	assert.ErrorIs(t, c.decodeData(&evt), ErrUndecodable)
+}
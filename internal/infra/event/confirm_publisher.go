@@ -0,0 +1,165 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	ErrPublishNacked = errors.New("broker nacked the message")
+	ErrUnroutable    = errors.New("message was returned as unroutable")
+	ErrChannelClosed = errors.New("channel closed before confirmation")
+)
+
+// Confirmation é o resultado de uma publicação em confirm mode.
+// Só é resolvida quando o broker responde com ack/nack (ou o canal cai).
+type Confirmation struct {
+	messageID string
+	done      chan struct{}
+	err       error
+}
+
+func (c *Confirmation) Wait(ctx context.Context) error {
+	select {
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Confirmation) resolve(err error) {
+	c.err = err
+	close(c.done)
+}
+
+// ConfirmPublisher publica com publisher confirms e, quando a rota exige, mandatory.
+//
+// O RabbitMQ envia o basic.return de uma mensagem sem rota antes do basic.ack
+// dela. Como o leitor da conexão entrega os dois de forma síncrona e os canais de
+// notificação aqui não têm buffer, a goroutine listen sempre vê o return antes do
+// ack correspondente — é isso que permite transformar o ack em ErrUnroutable.
+type ConfirmPublisher struct {
+	ch *amqp.Channel
+
+	publishMu sync.Mutex // Serializa publish: o delivery tag é sequencial por canal
+
+	mu       sync.Mutex
+	pending  map[uint64]*Confirmation
+	returned map[string]amqp.Return // message_id -> return ainda sem ack
+}
+
+func NewConfirmPublisher(ch *amqp.Channel) (*ConfirmPublisher, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable confirm mode: %w", err)
+	}
+
+	p := &ConfirmPublisher{
+		ch:       ch,
+		pending:  make(map[uint64]*Confirmation),
+		returned: make(map[string]amqp.Return),
+	}
+
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation))
+	returns := ch.NotifyReturn(make(chan amqp.Return))
+	go p.listen(confirms, returns)
+
+	return p, nil
+}
+
+// Publish envia a mensagem e devolve a confirmação pendente sem bloquear,
+// permitindo que o chamador publique um lote inteiro e só então espere os acks.
+func (p *ConfirmPublisher) Publish(ctx context.Context, dest Destination, msg amqp.Publishing) (*Confirmation, error) {
+	// Sem MessageId não há como correlacionar um basic.return.
+	if msg.MessageId == "" {
+		msg.MessageId = uuid.NewString()
+	}
+	conf := &Confirmation{messageID: msg.MessageId, done: make(chan struct{})}
+
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+
+	tag := p.ch.GetNextPublishSeqNo()
+	p.mu.Lock()
+	p.pending[tag] = conf
+	p.mu.Unlock()
+
+	if err := p.ch.PublishWithContext(ctx, dest.Exchange, dest.RoutingKey, dest.Mandatory, false, msg); err != nil {
+		p.mu.Lock()
+		delete(p.pending, tag)
+		p.mu.Unlock()
+		return nil, err
+	}
+	return conf, nil
+}
+
+func (p *ConfirmPublisher) listen(confirms <-chan amqp.Confirmation, returns <-chan amqp.Return) {
+	for {
+		select {
+		case ret, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			p.mu.Lock()
+			p.returned[ret.MessageId] = ret
+			p.mu.Unlock()
+
+		case c, ok := <-confirms:
+			if !ok {
+				p.failPending()
+				return
+			}
+			p.confirm(c)
+		}
+	}
+}
+
+func (p *ConfirmPublisher) confirm(c amqp.Confirmation) {
+	p.mu.Lock()
+	conf, ok := p.pending[c.DeliveryTag]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.pending, c.DeliveryTag)
+	ret, wasReturned := p.returned[conf.messageID]
+	delete(p.returned, conf.messageID)
+	p.mu.Unlock()
+
+	switch {
+	case !c.Ack:
+		conf.resolve(ErrPublishNacked)
+	case wasReturned:
+		conf.resolve(fmt.Errorf("%w: %d %s (exchange=%s, routing_key=%s)",
+			ErrUnroutable, ret.ReplyCode, ret.ReplyText, ret.Exchange, ret.RoutingKey))
+	default:
+		conf.resolve(nil)
+	}
+}
+
+func (p *ConfirmPublisher) failPending() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for tag, conf := range p.pending {
+		conf.resolve(ErrChannelClosed)
+		delete(p.pending, tag)
+	}
+}
+
+// Closed diz se o canal caiu; as confirmações pendentes já foram resolvidas com ErrChannelClosed.
+func (p *ConfirmPublisher) Closed() bool {
+	return p.ch == nil || p.ch.IsClosed()
+}
+
+func (p *ConfirmPublisher) Close() error {
+	if p.Closed() {
+		return nil
+	}
+	return p.ch.Close()
+}
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPublisher monta o publisher sem canal real: os testes alimentam
+// confirms/returns na mesma ordem em que o leitor da conexão AMQP entregaria.
+func newTestPublisher(tags map[uint64]string) (*ConfirmPublisher, map[uint64]*Confirmation, chan amqp.Confirmation, chan amqp.Return) {
+	p := &ConfirmPublisher{
+		pending:  make(map[uint64]*Confirmation),
+		returned: make(map[string]amqp.Return),
+	}
+	confs := make(map[uint64]*Confirmation, len(tags))
+	for tag, msgID := range tags {
+		c := &Confirmation{messageID: msgID, done: make(chan struct{})}
+		p.pending[tag] = c
+		confs[tag] = c
+	}
+
+	confirms := make(chan amqp.Confirmation)
+	returns := make(chan amqp.Return)
+	go p.listen(confirms, returns)
+	return p, confs, confirms, returns
+}
+
+func wait(t *testing.T, c *Confirmation) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return c.Wait(ctx)
+}
+
+func TestConfirmPublisher_ResolvesPerMessage(t *testing.T) {
+	_, confs, confirms, returns := newTestPublisher(map[uint64]string{1: "a", 2: "b", 3: "c"})
+
+	confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+	returns <- amqp.Return{MessageId: "b", ReplyCode: 312, ReplyText: "NO_ROUTE"}
+	confirms <- amqp.Confirmation{DeliveryTag: 2, Ack: true}
+	confirms <- amqp.Confirmation{DeliveryTag: 3, Ack: false}
+
+	assert.NoError(t, wait(t, confs[1]))
+	assert.ErrorIs(t, wait(t, confs[2]), ErrUnroutable)
+	assert.ErrorIs(t, wait(t, confs[3]), ErrPublishNacked)
+}
+
+func TestConfirmPublisher_FailsPendingWhenChannelCloses(t *testing.T) {
+	_, confs, confirms, _ := newTestPublisher(map[uint64]string{1: "a"})
+
+	close(confirms)
+
+	assert.ErrorIs(t, wait(t, confs[1]), ErrChannelClosed)
+}
+
+func TestConfirmation_WaitRespectsContext(t *testing.T) {
+	c := &Confirmation{done: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, c.Wait(ctx), context.DeadlineExceeded)
+}
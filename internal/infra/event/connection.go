@@ -0,0 +1,338 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNotConnected é devolvido por Channel enquanto a conexão está caída.
+var ErrNotConnected = errors.New("rabbitmq connection is not available")
+
+// errChannelLost marca um canal que caiu depois de aberto: a assinatura deve ser refeita.
+var errChannelLost = errors.New("channel lost")
+
+const (
+	defaultReconnectBase = 500 * time.Millisecond
+	defaultReconnectMax  = 30 * time.Second
+)
+
+// Connector abre canais. *amqp.Connection e *ConnectionManager implementam.
+type Connector interface {
+	Channel() (*amqp.Channel, error)
+}
+
+// ConnState é o estado da conexão gerenciada.
+type ConnState int32
+
+const (
+	StateConnecting ConnState = iota
+	StateConnected
+	StateReconnecting
+	StateClosed
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("ConnState(%d)", int32(s))
+}
+
+// TopologyFunc declara exchanges/filas em um canal temporário a cada (re)conexão.
+type TopologyFunc func(ch *amqp.Channel) error
+
+type ConnectionOption func(*ConnectionManager)
+
+// WithReconnectBackoff define a espera entre tentativas de dial (exponencial com jitter).
+func WithReconnectBackoff(base, max time.Duration) ConnectionOption {
+	return func(m *ConnectionManager) {
+		if base > 0 {
+			m.base = base
+		}
+		if max >= m.base {
+			m.max = max
+		}
+	}
+}
+
+// WithTopology redeclara a topologia a cada conexão. Útil para quem só publica
+// (relay); o Consumer declara a própria topologia ao resubscrever.
+func WithTopology(fn TopologyFunc) ConnectionOption {
+	return func(m *ConnectionManager) {
+		if fn != nil {
+			m.topology = append(m.topology, fn)
+		}
+	}
+}
+
+func WithConnectionMetrics(mt metrics.Metrics) ConnectionOption {
+	return func(m *ConnectionManager) {
+		m.metrics = mt
+	}
+}
+
+// ConnectionManager mantém uma conexão AMQP viva. O amqp091-go não reconecta:
+// Run observa o NotifyClose e, quando a conexão cai sem Close, refaz o dial com
+// backoff e redeclara a topologia. Quem pede canais (Consumer, publishers) recebe
+// ErrNotConnected enquanto isso e tenta de novo.
+type ConnectionManager struct {
+	url      string
+	logger   logger.Logger
+	metrics  metrics.Metrics
+	topology []TopologyFunc
+	base     time.Duration
+	max      time.Duration
+	dial     func(url string) (*amqp.Connection, error)
+
+	mu      sync.RWMutex
+	conn    *amqp.Connection
+	closed  chan *amqp.Error
+	state   ConnState
+	lastErr error
+	changed chan struct{}
+}
+
+func NewConnectionManager(url string, l logger.Logger, opts ...ConnectionOption) *ConnectionManager {
+	m := &ConnectionManager{
+		url:     url,
+		logger:  l,
+		base:    defaultReconnectBase,
+		max:     defaultReconnectMax,
+		dial:    amqp.Dial,
+		state:   StateConnecting,
+		changed: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Connect faz o primeiro dial, tentando de novo com backoff até conseguir ou o ctx acabar.
+func (m *ConnectionManager) Connect(ctx context.Context) error {
+	return m.redial(ctx)
+}
+
+// Run reconecta sempre que a conexão cai, até o ctx ser cancelado ou Close ser
+// chamado. Cancelar o ctx não fecha a conexão: no shutdown os workers ainda
+// precisam dela para o Ack, então quem fecha é o Close.
+func (m *ConnectionManager) Run(ctx context.Context) {
+	for {
+		m.mu.RLock()
+		closed := m.closed
+		m.mu.RUnlock()
+		if closed == nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case amqpErr, ok := <-closed:
+			if m.State() == StateClosed {
+				return
+			}
+			var cause error = amqp.ErrClosed
+			if ok && amqpErr != nil {
+				cause = amqpErr
+			}
+			m.logger.Warn(ctx, "RabbitMQ connection lost. Reconnecting...", logger.WithError(cause))
+			m.setState(StateReconnecting, cause)
+
+			if err := m.redial(ctx); err != nil {
+				return
+			}
+			if m.metrics != nil {
+				m.metrics.IncRabbitMQReconnects()
+			}
+			m.logger.Info(ctx, "RabbitMQ connection restored")
+		}
+	}
+}
+
+// redial tenta conectar até conseguir. Só devolve erro quando o ctx acaba.
+func (m *ConnectionManager) redial(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		if m.State() == StateClosed {
+			return ErrNotConnected
+		}
+		err := m.connect()
+		if err == nil {
+			return nil
+		}
+		m.setState(m.State(), err)
+
+		wait := reconnectDelay(m.base, m.max, attempt)
+		m.logger.Warn(ctx, "RabbitMQ dial failed",
+			logger.Int("attempt", attempt+1),
+			logger.String("wait", wait.String()),
+			logger.WithError(err),
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// connect abre a conexão, registra o NotifyClose e declara a topologia.
+func (m *ConnectionManager) connect() error {
+	conn, err := m.dial(m.url)
+	if err != nil {
+		return err
+	}
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	if err := m.declareTopology(conn); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to declare topology: %w", err)
+	}
+
+	m.mu.Lock()
+	if m.state == StateClosed {
+		m.mu.Unlock()
+		_ = conn.Close()
+		return ErrNotConnected
+	}
+	m.conn, m.closed = conn, closed
+	m.mu.Unlock()
+
+	m.setState(StateConnected, nil)
+	return nil
+}
+
+func (m *ConnectionManager) declareTopology(conn *amqp.Connection) error {
+	if len(m.topology) == 0 {
+		return nil
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ch.Close() }()
+
+	for _, fn := range m.topology {
+		if err := fn(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Channel abre um canal na conexão atual.
+func (m *ConnectionManager) Channel() (*amqp.Channel, error) {
+	m.mu.RLock()
+	conn, state := m.conn, m.state
+	m.mu.RUnlock()
+
+	if state != StateConnected || conn == nil || conn.IsClosed() {
+		return nil, ErrNotConnected
+	}
+	return conn.Channel()
+}
+
+func (m *ConnectionManager) State() ConnState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// Changed é fechado na próxima mudança de estado. Chame de novo após cada sinal.
+func (m *ConnectionManager) Changed() <-chan struct{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.changed
+}
+
+// Check é o health check: falha enquanto a conexão não está de pé.
+func (m *ConnectionManager) Check(context.Context) error {
+	m.mu.RLock()
+	state, lastErr := m.state, m.lastErr
+	m.mu.RUnlock()
+
+	if state == StateConnected {
+		return nil
+	}
+	if lastErr != nil {
+		return fmt.Errorf("rabbitmq connection is %s: %w", state, lastErr)
+	}
+	return fmt.Errorf("rabbitmq connection is %s", state)
+}
+
+// Close encerra a conexão sem reconectar.
+func (m *ConnectionManager) Close() error {
+	m.mu.Lock()
+	conn := m.conn
+	m.mu.Unlock()
+
+	m.setState(StateClosed, nil)
+	if conn == nil || conn.IsClosed() {
+		return nil
+	}
+	return conn.Close()
+}
+
+func (m *ConnectionManager) setState(s ConnState, err error) {
+	m.mu.Lock()
+	if m.state == StateClosed && s != StateClosed {
+		m.mu.Unlock()
+		return
+	}
+	m.lastErr = err
+	if m.state == s {
+		m.mu.Unlock()
+		return
+	}
+	m.state = s
+	close(m.changed)
+	m.changed = make(chan struct{})
+	m.mu.Unlock()
+
+	if m.metrics != nil {
+		m.metrics.SetRabbitMQConnected(s == StateConnected)
+	}
+}
+
+// reconnectDelay é exponencial com jitter ("equal jitter"): nunca menos que metade do teto.
+func reconnectDelay(base, max time.Duration, attempt int) time.Duration {
+	d := time.Duration(float64(base) * math.Pow(2, float64(min(attempt, 30))))
+	if d <= 0 || d > max {
+		d = max
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// isConnectionLost diz se o erro vem da queda do canal ou da conexão (vale
+// resubscrever) e não de configuração, como um PRECONDITION_FAILED na topologia.
+func isConnectionLost(err error) bool {
+	if errors.Is(err, ErrNotConnected) || errors.Is(err, amqp.ErrClosed) || errors.Is(err, errChannelLost) {
+		return true
+	}
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		// !Server: erro de I/O detectado pelo cliente (socket, heartbeat).
+		return amqpErr.Code == amqp.ConnectionForced || !amqpErr.Server
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConnector simula a conexão caída: todo Channel falha com err.
+type fakeConnector struct {
+	err   error
+	calls int
+}
+
+func (f *fakeConnector) Channel() (*amqp.Channel, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func TestReconnectDelay_StaysWithinBounds(t *testing.T) {
+	base, ceiling := 100*time.Millisecond, 2*time.Second
+	for attempt := 0; attempt < 50; attempt++ {
+		d := reconnectDelay(base, ceiling, attempt)
+		expected := min(time.Duration(float64(base)*float64(int64(1)<<min(attempt, 30))), ceiling)
+		assert.GreaterOrEqual(t, d, expected/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, d, expected, "attempt %d", attempt)
+	}
+}
+
+func TestIsConnectionLost(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "manager reconnecting", err: ErrNotConnected, want: true},
+		{name: "channel or connection closed", err: fmt.Errorf("qos: %w", amqp.ErrClosed), want: true},
+		{name: "channel lost while consuming", err: fmt.Errorf("%w: boom", errChannelLost), want: true},
+		{name: "broker forced close", err: &amqp.Error{Code: amqp.ConnectionForced, Server: true}, want: true},
+		{name: "client side io error", err: &amqp.Error{Code: amqp.FrameError, Reason: "EOF"}, want: true},
+		{name: "topology mismatch", err: &amqp.Error{Code: amqp.PreconditionFailed, Server: true}, want: false},
+		{name: "access refused", err: &amqp.Error{Code: amqp.AccessRefused, Server: true}, want: false},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isConnectionLost(tt.err))
+		})
+	}
+}
+
+func newTestManager(dial func(string) (*amqp.Connection, error), opts ...ConnectionOption) *ConnectionManager {
+	m := NewConnectionManager("amqp://test", logger.NewZapLogger("test", false),
+		append([]ConnectionOption{WithReconnectBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)...)
+	m.dial = dial
+	return m
+}
+
+func TestConnectionManager_ConnectRetriesUntilContextEnds(t *testing.T) {
+	dials := 0
+	m := newTestManager(func(string) (*amqp.Connection, error) {
+		dials++
+		return nil, errors.New("connection refused")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, m.Connect(ctx), context.DeadlineExceeded)
+	assert.Greater(t, dials, 1)
+	assert.Equal(t, StateConnecting, m.State())
+
+	err := m.Check(context.Background())
+	assert.ErrorContains(t, err, "rabbitmq connection is connecting")
+	assert.ErrorContains(t, err, "connection refused")
+
+	_, err = m.Channel()
+	assert.ErrorIs(t, err, ErrNotConnected)
+}
+
+func TestConnectionManager_CloseStopsReconnecting(t *testing.T) {
+	m := newTestManager(func(string) (*amqp.Connection, error) {
+		return nil, errors.New("connection refused")
+	})
+	changed := m.Changed()
+
+	require.NoError(t, m.Close())
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("Changed was not signalled")
+	}
+	assert.Equal(t, StateClosed, m.State())
+	assert.ErrorIs(t, m.Connect(context.Background()), ErrNotConnected)
+
+	// Depois de fechado, nenhuma transição reabre o estado.
+	m.setState(StateConnected, nil)
+	assert.Equal(t, StateClosed, m.State())
+}
+
+func TestConnectionManager_ExportsConnectedGauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := newTestManager(nil, WithConnectionMetrics(metrics.NewPrometheusMetrics(reg, "test")))
+
+	m.setState(StateConnected, nil)
+	assert.Equal(t, 1.0, gaugeValue(t, reg, "app_rabbitmq_connected"))
+
+	m.setState(StateReconnecting, amqp.ErrClosed)
+	assert.Equal(t, 0.0, gaugeValue(t, reg, "app_rabbitmq_connected"))
+}
+
+func gaugeValue(t *testing.T, reg *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() == name {
+			return f.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
+
+func TestChannelPool_FailsWhileDisconnected(t *testing.T) {
+	conn := &fakeConnector{err: ErrNotConnected}
+	p := NewChannelPool(conn, logger.NewZapLogger("test", false), WithPoolSize(1))
+
+	_, err := p.Publish(context.Background(), Destination{RoutingKey: "orders.created"}, amqp.Publishing{})
+	assert.ErrorIs(t, err, ErrNotConnected)
+
+	// Cada publicação tenta um canal novo.
+	_, _ = p.Publish(context.Background(), Destination{RoutingKey: "orders.created"}, amqp.Publishing{})
+	assert.Equal(t, 2, conn.calls)
+}
+
+func TestConsumer_ResubscribesWhileConnectionIsDown(t *testing.T) {
+	conn := &fakeConnector{err: ErrNotConnected}
+	c := NewConsumer(conn, logger.NewZapLogger("test", false))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: noopHandler}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, c.Run(ctx))
+	assert.GreaterOrEqual(t, conn.calls, 2)
+}
+
+func TestConsumer_StopsOnConfigurationError(t *testing.T) {
+	conn := &fakeConnector{err: &amqp.Error{Code: amqp.AccessRefused, Server: true}}
+	c := NewConsumer(conn, logger.NewZapLogger("test", false))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: noopHandler}))
+
+	err := c.Run(context.Background())
+	assert.ErrorContains(t, err, "subscription orders.created")
+	assert.Equal(t, 1, conn.calls)
+}
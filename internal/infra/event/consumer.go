@@ -2,19 +2,17 @@ package event
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"sync"
 	"time"
 
-	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
-	"github.com/DioGolang/GoFleet/internal/application/usecase/order"
-	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
+	"github.com/DioGolang/GoFleet/pkg/events"
 	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
 	carrier "github.com/DioGolang/GoFleet/pkg/otel"
-	"github.com/redis/go-redis/v9"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
 	"github.com/sony/gobreaker"
+	"golang.org/x/sync/errgroup"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/otel"
@@ -23,137 +21,330 @@ import (
 )
 
 const (
-	MaxRetries = 3
-	DLXName    = "dlx_exchange"
-	MainEx     = "orders_exchange"
+	DLXName = "dlx_exchange"
+	MainEx  = "orders_exchange"
 )
 
-type Consumer struct {
-	Conn            *amqp.Connection
-	GrpcClient      pb.FleetServiceClient
-	OrderRepository outbound.OrderRepository
-	DispatchUseCase order.DispatchUseCase
-	RedisClient     *redis.Client
-	Logger          logger.Logger
-	WorkerCount     int
+const (
+	defaultDrainTimeout = 10 * time.Second
+	// drainAbortGrace é quanto se espera os handlers abortados antes de fechar o canal.
+	drainAbortGrace = 2 * time.Second
+)
+
+// Subscription liga uma fila a um handler. Cada assinatura tem canal, QoS e
+// pool de workers próprios; retry e Parking seguem a política da fila.
+type Subscription struct {
+	Queue string
+	// Exchange e BindingKey ligam a fila a uma exchange topic extra (ex: gofleet.events
+	// com drivers.#). A fila sempre fica ligada ao MainEx pelo próprio nome, que é o
+	// caminho de volta das wait queues.
+	Exchange   string
+	BindingKey string
+	Handler    MessageHandler
+	// Middleware envolve o Handler; o primeiro da lista é o mais externo.
+	Middleware []Middleware
+	// Fallback é chamado quando o circuit breaker está aberto. Se tratar a
+	// mensagem, ela recebe Ack; se falhar, segue o fluxo normal de retry.
+	Fallback MessageHandler
+	Workers  int
+	// Prefetch padrão: 2x Workers. Nunca menor que Workers.
+	Prefetch int
+	// Scaling liga a concorrência dinâmica: Workers vira o tamanho inicial
+	// (padrão Scaling.Min) e o prefetch passa a ser gerido pelo autoscaler.
+	Scaling *Scaling
 }
 
-func NewConsumer(
-	conn *amqp.Connection,
-	grpcClient pb.FleetServiceClient,
-	repo outbound.OrderRepository,
-	dispatchUseCase order.DispatchUseCase,
-	redisClient *redis.Client,
-	l logger.Logger,
-	workerCount int,
-) *Consumer {
-	if workerCount <= 0 {
-		workerCount = 1
-	}
-	return &Consumer{
-		Conn:            conn,
-		GrpcClient:      grpcClient,
-		OrderRepository: repo,
-		DispatchUseCase: dispatchUseCase,
-		RedisClient:     redisClient,
-		Logger:          l,
-		WorkerCount:     workerCount,
+type ConsumerOption func(*Consumer)
+
+func WithRetryTopology(t *RetryTopology) ConsumerOption {
+	return func(c *Consumer) {
+		if t != nil {
+			c.Retry = t
+		}
 	}
 }
 
-func (c *Consumer) Start(ctx context.Context, queueName string, handler MessageHandler) error {
-	ch, err := c.Conn.Channel()
-	if err != nil {
-		return err
+// WithDrainTimeout define quanto o shutdown espera as mensagens em voo.
+func WithDrainTimeout(d time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		if d > 0 {
+			c.DrainTimeout = d
+		}
 	}
-	defer ch.Close()
+}
 
-	// O Prefetch Count DEVE ser >= WorkerCount.
-	prefetchCount := c.WorkerCount * 2
-	if err := ch.Qos(prefetchCount, 0, false); err != nil {
-		return fmt.Errorf("failed to set qos: %w", err)
+// WithConsumerMetrics exporta workers e prefetch por fila.
+func WithConsumerMetrics(m metrics.Metrics) ConsumerOption {
+	return func(c *Consumer) {
+		c.Metrics = m
 	}
+}
 
-	if err := c.setupTopology(ch, queueName); err != nil {
-		return fmt.Errorf("error configuration topology: %w", err)
+func WithCodec(codec *ProtoCodec) ConsumerOption {
+	return func(c *Consumer) {
+		if codec != nil {
+			c.Codec = codec
+		}
 	}
+}
 
-	msgs, err := ch.Consume(
-		queueName, "", false, false, false, false, nil,
-	)
-	if err != nil {
-		return err
+// Consumer é o runtime de consumo: registra assinaturas e as executa sob um
+// único ciclo de vida. Não conhece regras de negócio; elas ficam nos handlers.
+// Com um ConnectionManager como Conn, assinaturas derrubadas pela queda do
+// broker são refeitas (canal, QoS, topologia e Consume) com backoff.
+type Consumer struct {
+	Conn   Connector
+	Logger logger.Logger
+	// Codec decodifica payloads protobuf; handlers só recebem JSON.
+	Codec *ProtoCodec
+	// Retry define tiers de espera e tentativas por fila.
+	Retry *RetryTopology
+	// Metrics é opcional.
+	Metrics metrics.Metrics
+	// DrainTimeout é quanto o shutdown espera as mensagens em voo.
+	DrainTimeout time.Duration
+
+	subs []*subscription
+}
+
+// subscription é a Subscription validada, com o handler já montado.
+type subscription struct {
+	Subscription
+	handler MessageHandler
+}
+
+func NewConsumer(conn Connector, l logger.Logger, opts ...ConsumerOption) *Consumer {
+	c := &Consumer{
+		Conn:         conn,
+		Logger:       l,
+		Codec:        NewOrderEventsCodec(),
+		Retry:        DefaultRetryTopology(),
+		DrainTimeout: defaultDrainTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
 
-	c.Logger.Info(ctx, "Starting Worker Pool",
-		logger.String("queue", queueName),
-		logger.Int("workers", c.WorkerCount),
-		logger.Int("prefetch", prefetchCount),
-	)
+// Subscribe registra uma assinatura. Deve ser chamado antes de Run.
+func (c *Consumer) Subscribe(sub Subscription) error {
+	if sub.Queue == "" {
+		return errors.New("subscription queue is required")
+	}
+	if sub.Handler == nil {
+		return fmt.Errorf("subscription %s: handler is required", sub.Queue)
+	}
+	if sub.BindingKey != "" && sub.Exchange == "" {
+		return fmt.Errorf("subscription %s: binding key requires an exchange", sub.Queue)
+	}
+	for _, s := range c.subs {
+		if s.Queue == sub.Queue {
+			return fmt.Errorf("subscription %s: queue already registered", sub.Queue)
+		}
+	}
+	if sub.Scaling != nil {
+		scaling := *sub.Scaling
+		if err := scaling.normalize(); err != nil {
+			return fmt.Errorf("subscription %s: %w", sub.Queue, err)
+		}
+		if sub.Prefetch != 0 {
+			return fmt.Errorf("subscription %s: prefetch is managed by scaling", sub.Queue)
+		}
+		sub.Scaling = &scaling
+		sub.Workers = min(max(sub.Workers, scaling.Min), scaling.Max)
+		sub.Prefetch = sub.Workers * scaling.PrefetchPerWorker
+	}
+	if sub.Workers <= 0 {
+		sub.Workers = 1
+	}
+	if sub.Prefetch == 0 {
+		sub.Prefetch = sub.Workers * 2
+	}
+	// Com prefetch menor que o pool, workers ficam ociosos esperando entrega.
+	if sub.Prefetch < sub.Workers {
+		return fmt.Errorf("subscription %s: prefetch (%d) must be >= workers (%d)", sub.Queue, sub.Prefetch, sub.Workers)
+	}
 
-	var wg sync.WaitGroup
+	c.subs = append(c.subs, &subscription{
+		Subscription: sub,
+		handler:      Chain(sub.Handler, sub.Middleware...),
+	})
+	return nil
+}
 
-	for i := 0; i < c.WorkerCount; i++ {
-		wg.Add(1)
-		go c.startWorker(ctx, &wg, i, msgs, handler, queueName, ch)
+// Run consome todas as assinaturas até o ctx ser cancelado. Queda de canal ou
+// conexão é recuperada; erro de configuração (ex: topologia incompatível com a
+// fila existente) encerra as demais assinaturas e sobe.
+func (c *Consumer) Run(ctx context.Context) error {
+	if len(c.subs) == 0 {
+		return errors.New("no subscriptions registered")
 	}
 
-	<-ctx.Done()
-	c.Logger.Info(ctx, "Shutdown signal received. Closing channel and waiting for workers...")
+	g, gctx := errgroup.WithContext(ctx)
+	for _, sub := range c.subs {
+		g.Go(func() error {
+			if err := c.supervise(gctx, sub); err != nil {
+				return fmt.Errorf("subscription %s: %w", sub.Queue, err)
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
 
-	// Ao fechar o Channel do AMQP (via defer ou explicitamente),
-	// o canal 'msgs' será fechado, fazendo os loops dos workers terminarem.
-	// Mas para garantir, aguardamos o WaitGroup.
-	ch.Close()
-	wg.Wait()
+	c.Logger.Info(ctx, "All subscriptions stopped. Consumer shutdown complete.")
+	return err
+}
 
-	c.Logger.Info(ctx, "All workers stopped. Consumer shutdown complete.")
-	return nil
+// supervise refaz a assinatura enquanto a falha for perda de canal/conexão.
+func (c *Consumer) supervise(ctx context.Context, sub *subscription) error {
+	for attempt := 0; ; attempt++ {
+		err := c.consume(ctx, sub)
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		if !isConnectionLost(err) {
+			return err
+		}
+		// Caiu depois de consumir: a próxima tentativa recomeça do backoff mínimo.
+		if errors.Is(err, errChannelLost) {
+			attempt = 0
+		}
+
+		wait := reconnectDelay(defaultReconnectBase, defaultReconnectMax, attempt)
+		c.Logger.Warn(ctx, "Subscription lost. Resubscribing...",
+			logger.String("queue", sub.Queue),
+			logger.String("wait", wait.String()),
+			logger.WithError(err),
+		)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		}
+	}
 }
 
-func (c *Consumer) startWorker(
-	ctx context.Context,
-	wg *sync.WaitGroup,
-	workerID int,
-	msgs <-chan amqp.Delivery,
-	handler MessageHandler,
-	queueName string,
-	ch *amqp.Channel,
-) {
-	defer wg.Done()
-
-	// Safety: Recuperação de Panic para não derrubar a aplicação inteira
-	// se um worker encontrar um bug bizarro.
-	defer func() {
-		if r := recover(); r != nil {
-			c.Logger.Error(ctx, "Worker panicked!",
-				logger.Int("worker_id", workerID),
-				logger.Any("panic", r),
-			)
+// consume roda uma assinatura no seu próprio canal.
+func (c *Consumer) consume(ctx context.Context, sub *subscription) error {
+	ch, err := c.Conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	if err := c.setupTopology(ch, sub); err != nil {
+		return fmt.Errorf("error configuration topology: %w", err)
+	}
+
+	// Os handlers não herdam o cancelamento do shutdown: terminam no drain e só
+	// são abortados se passarem do DrainTimeout.
+	workCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+
+	pool := newWorkerPool(workCtx, c, sub, ch)
+	if err := pool.start(); err != nil {
+		return err
+	}
+
+	c.Logger.Info(ctx, "Starting Worker Pool",
+		logger.String("queue", sub.Queue),
+		logger.Int("workers", sub.Workers),
+		logger.Int("prefetch", sub.Prefetch),
+		logger.Any("autoscale", sub.Scaling != nil),
+	)
+
+	scaleCtx, stopScaling := context.WithCancel(ctx)
+	scalerDone := make(chan struct{})
+	go func() {
+		defer close(scalerDone)
+		if sub.Scaling != nil {
+			pool.autoscale(scaleCtx)
 		}
 	}()
 
-	c.Logger.Debug(ctx, "Worker started", logger.Int("worker_id", workerID))
+	var runErr error
+	select {
+	case <-ctx.Done():
+		c.Logger.Info(ctx, "Shutdown signal received. Draining in-flight messages...",
+			logger.String("queue", sub.Queue),
+			logger.String("drain_timeout", c.DrainTimeout.String()),
+		)
+		stopScaling()
+		<-scalerDone
+		if late := pool.drain(ctx, c.DrainTimeout, abort); late > 0 {
+			c.Logger.Warn(ctx, "Drain deadline exceeded. Late messages will be redelivered.",
+				logger.String("queue", sub.Queue),
+				logger.Int("late", late),
+			)
+		}
+	case amqpErr := <-closed:
+		// Canal caiu sem shutdown: os workers já estão saindo (entregas fechadas).
+		runErr = fmt.Errorf("%w: %v", errChannelLost, amqpErr)
+		stopScaling()
+		<-scalerDone
+	}
 
-	for d := range msgs {
-		c.handleMessage(ctx, d, handler, queueName, ch)
+	// Só agora o canal fecha: o que ainda não recebeu Ack volta para a fila pelo broker.
+	ch.Close()
+	select {
+	case <-pool.wait():
+	case <-time.After(drainAbortGrace):
+		c.Logger.Error(ctx, "Workers still running after channel close", logger.String("queue", sub.Queue))
 	}
 
-	c.Logger.Debug(ctx, "Worker stopped", logger.Int("worker_id", workerID))
+	c.Logger.Info(ctx, "All workers stopped", logger.String("queue", sub.Queue))
+	return runErr
 }
 
-func (c *Consumer) handleMessage(ctx context.Context, d amqp.Delivery, handler MessageHandler, queueName string, ch *amqp.Channel) {
+func (c *Consumer) handleMessage(ctx context.Context, d amqp.Delivery, sub *subscription, ch *amqp.Channel) {
+	queueName := sub.Queue
 	amqpCarrier := carrier.AMQPHeadersCarrier(d.Headers)
 	ctx = otel.GetTextMapPropagator().Extract(ctx, amqpCarrier)
+
+	evt, parseErr := ParseDelivery(d)
+	if parseErr != nil {
+		// Envelope inválido nunca vai ficar válido: direto para o Parking.
+		c.Logger.Error(ctx, "Invalid CloudEvent. Moving to Parking Queue.",
+			logger.String("msg_id", d.MessageId),
+			logger.WithError(parseErr),
+		)
+		c.park(ctx, ch, queueName, d, "invalid-cloudevent")
+		return
+	}
+	if err := c.decodeData(&evt); err != nil {
+		c.Logger.Error(ctx, "Undecodable payload. Moving to Parking Queue.",
+			logger.String("msg_id", d.MessageId),
+			logger.String("content_type", evt.DataContentType),
+			logger.WithError(err),
+		)
+		c.park(ctx, ch, queueName, d, "undecodable-payload")
+		return
+	}
+
+	// A extensão tenantid vence o Baggage: é gravada pelo relay a partir da coluna tenant_id.
+	tenantID := evt.Extension(events.ExtTenantID)
+	if tenantID == "" {
+		tenantID, _ = d.Headers[tenant.Header].(string)
+	}
+	if tenantID != "" {
+		ctx = tenant.WithTenant(ctx, tenantID)
+		ctx = logger.ContextWithFields(ctx, logger.String("tenant_id", tenantID))
+	}
 	tracer := otel.GetTracerProvider().Tracer("worker-tracer")
 
-	ctx, span := tracer.Start(ctx, "ProcessOrder", trace.WithAttributes(
+	ctx, span := tracer.Start(ctx, queueName+" process", trace.WithAttributes(
 		attribute.String("queue.name", queueName),
-		attribute.String("messaging.message_id", d.MessageId),
+		attribute.String("messaging.message_id", evt.ID),
+		attribute.String("cloudevents.event_type", evt.Type),
+		attribute.String("cloudevents.event_subject", evt.Subject),
 	))
 	defer span.End()
 
-	err := handler(ctx, d.Body, d.Headers)
+	err := sub.handler(ctx, evt)
 
 	// --- CENÁRIO: SUCESSO ---
 	if err == nil {
@@ -162,17 +353,41 @@ func (c *Consumer) handleMessage(ctx context.Context, d amqp.Delivery, handler M
 		return
 	}
 
+	// --- CENÁRIO: ABORTADO NO DRAIN ---
+	// Não é falha da mensagem: volta para a fila sem contar tentativa.
+	if ctx.Err() != nil {
+		c.Logger.Warn(ctx, "Handler aborted by shutdown. Requeueing.",
+			logger.String("msg_id", d.MessageId),
+			logger.WithError(err),
+		)
+		d.Nack(false, true)
+		return
+	}
+
 	// --- CENÁRIO: FALHA ---
-	retryCount := c.getRetryCount(d)
+	// Falhas permanentes não melhoram com retry: direto para o Parking.
+	failure := Classify(err)
+	if failure.Permanent() {
+		c.Logger.Error(ctx, "Permanent failure. Moving to Parking Queue.",
+			logger.String("msg_id", d.MessageId),
+			logger.String("reason", failure.Reason),
+			logger.WithError(err),
+		)
+		c.park(ctx, ch, queueName, d, failure.Reason)
+		return
+	}
+
+	policy := c.Retry.PolicyFor(queueName)
+	retries := retryCount(d.Headers)
 	c.Logger.Warn(ctx, "Processing failed",
 		logger.WithError(err),
-		logger.Int("retry_count", int(retryCount)),
+		logger.Int("retry_count", retries),
 	)
 
-	// A. Circuit Breaker Aberto -> Tenta Fallback
-	if errors.Is(err, gobreaker.ErrOpenState) {
+	// A. Circuit Breaker Aberto -> Tenta Fallback da assinatura
+	if errors.Is(err, gobreaker.ErrOpenState) && sub.Fallback != nil {
 		c.Logger.Warn(ctx, "Circuit Breaker Open. Attempting Fallback...")
-		if fbErr := c.executeFallback(ctx, d.Body); fbErr == nil {
+		if fbErr := sub.Fallback(ctx, evt); fbErr == nil {
 			c.Logger.Info(ctx, "Fallback success. Discarding original message.")
 			d.Ack(false) // Fallback tratou, vida que segue.
 			return
@@ -182,131 +397,60 @@ func (c *Consumer) handleMessage(ctx context.Context, d amqp.Delivery, handler M
 		}
 	}
 
-	// B. Excedeu Retries -> Manda para Parking (Cemitério)
-	if retryCount >= MaxRetries {
-		c.Logger.Error(ctx, "Max retries reached. Moving to Parking Queue.", logger.String("msg_id", d.MessageId))
-		if pubErr := c.publishToParking(ch, queueName, d); pubErr != nil {
-			c.Logger.Error(ctx, "CRITICAL: Failed to publish to parking!", logger.WithError(pubErr))
-			// Se não consegue nem mandar pro parking, Nack com Requeue para tentar salvar dnv
-			d.Nack(false, true)
-			return
-		}
-		d.Ack(false) // Remove da fila principal pois já está na parking
+	// B. Esgotou as tentativas -> Manda para Parking (Cemitério)
+	if policy.Exhausted(retries) {
+		c.Logger.Error(ctx, "Max attempts reached. Moving to Parking Queue.",
+			logger.String("msg_id", d.MessageId),
+			logger.Int("max_attempts", policy.MaxAttempts),
+		)
+		c.park(ctx, ch, queueName, d, "max-retries-exceeded")
 		return
 	}
 
-	// C. Erro Transiente ou Retry Padrão -> Manda para DLX (Wait Queue)
-	// Nack(false, false) rejeita a msg SEM requeue.
-	// A configuração da topologia enviará para a DLX -> Wait Queue -> TTL -> Main Queue
-	c.Logger.Info(ctx, "Sending message to DLQ (Wait Queue) for retry", logger.Int("next_retry", int(retryCount)+1))
-	d.Nack(false, false)
-}
-
-func (c *Consumer) ProcessOrder(ctx context.Context, msg []byte, headers map[string]interface{}) error {
-	// 1. Identidade do Evento (Idempotency Key)
-	eventID := c.extractEventID(headers, msg)
-	if eventID == "" {
-		// Se não tiver ID, é perigoso processar. Mas como fallback, geramos um hash ou logamos erro.
-		c.Logger.Warn(ctx, "Message without ID, skipping idempotency check")
-	}
-
-	// 2. Chave no Redis: "idem:orders:{event_id}"
-	// TTL de 24h é suficiente para evitar deduplicação imediata.
-	idempotencyKey := fmt.Sprintf("idem:orders:%s", eventID)
-
-	if eventID != "" {
-		// SETNX: "Set if Not Exists". Operação atômica.
-		// Se retornar true: A chave não existia, setou e ganhou o lock.
-		// Se retornar false: A chave já existe, alguém já processou.
-		acquired, err := c.RedisClient.SetNX(ctx, idempotencyKey, "processing", 24*time.Hour).Result()
-		if err != nil {
-			// Falha no Redis (Infra). Retornamos erro para dar Nack e tentar de novo.
-			return fmt.Errorf("redis failure: %w", err)
-		}
-		if !acquired {
-			// DUPLICIDADE DETECTADA!
-			c.Logger.Info(ctx, "Event already processed (Idempotency Hit). Skipping.",
-				logger.String("event_id", eventID))
-			return nil // Retorna nil para dar ACK e remover da fila.
-		}
-	}
-
-	// --- PONTO CRÍTICO: Tratamento de Erro com Idempotência ---
-	err := c.executeBusinessLogic(ctx, msg)
-
-	if err != nil {
-		c.Logger.Warn(ctx, "Processing failed, releasing idempotency key for retry",
-			logger.String("event_id", eventID),
-			logger.WithError(err))
-
-		if eventID != "" {
-			c.RedisClient.Del(ctx, idempotencyKey)
-		}
-
-		return err // Retorna o erro para o handler jogar na Wait Queue
-	}
-	return nil
+	// C. Retry -> Wait Queue do tier da tentativa (ou do RetryAfter); após o TTL volta para a fila principal.
+	c.scheduleRetry(ctx, ch, queueName, d, retries, policy, failure.Delay)
 }
 
-func (c *Consumer) executeBusinessLogic(ctx context.Context, msg []byte) error {
-	var orderDto order.CreateInput
-	if err := json.Unmarshal(msg, &orderDto); err != nil {
-		// Erro Fatal (JSON inválido). Não adianta retentar.
-		// Retornamos nil ou um erro específico que o handler saiba descartar (Poison Message).
-		// Aqui vou retornar erro encapsulado para o handler decidir (geralmente Nack False).
-		return fmt.Errorf("invalid json: %w", err)
-	}
-
-	req := &pb.SearchDriverRequest{OrderId: orderDto.ID}
-	res, err := c.GrpcClient.SearchDriver(ctx, req)
+// scheduleRetry publica no tier com x-retry incrementado e só então dá Ack.
+// minDelay (RetryAfter) escolhe um tier de pelo menos essa duração.
+func (c *Consumer) scheduleRetry(ctx context.Context, ch *amqp.Channel, queueName string, d amqp.Delivery, retries int, policy RetryPolicy, minDelay time.Duration) {
+	waitQueue, delay := policy.TierAtLeast(queueName, retries, minDelay)
+
+	headers := cloneHeaders(d.Headers)
+	headers[RetryHeader] = int32(retries + 1)
+
+	err := ch.PublishWithContext(ctx, "", waitQueue, false, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		MessageId:    d.MessageId,
+		Type:         d.Type,
+		Timestamp:    d.Timestamp,
+		DeliveryMode: amqp.Persistent,
+		Body:         d.Body,
+	})
 	if err != nil {
-		return fmt.Errorf("grpc search driver failed: %w", err)
-	}
-
-	input := order.DispatchInput{OrderID: orderDto.ID, DriverID: res.DriverId}
-
-	// AQUI MORA A CONSISTÊNCIA EVENTUAL
-	// Se o DispatchUseCase buscar o pedido no banco e não achar (porque o evento chegou antes da escrita),
-	// ele deve retornar um erro.
-	if err := c.DispatchUseCase.Execute(ctx, input); err != nil {
-		return err // Isso fará o Redis Key ser deletado e o msg ir pra Wait Queue
-	}
-
-	return nil
-}
-
-// Helper para extrair ID
-func (c *Consumer) extractEventID(headers map[string]interface{}, msg []byte) string {
-	if val, ok := headers["x-event-id"]; ok {
-		switch v := val.(type) {
-		case string:
-			return v
-		case []byte:
-			return string(v)
-		}
-	}
-
-	var payload struct {
-		ID      string `json:"id"`
-		EventID string `json:"event_id"`
-	}
-
-	if err := json.Unmarshal(msg, &payload); err == nil {
-		if payload.ID != "" {
-			return payload.ID
-		}
-		if payload.EventID != "" {
-			return payload.EventID
-		}
+		// Sem o tier, o Nack cai na DLX -> wait queue legada (10s) e a tentativa não é contada.
+		c.Logger.Error(ctx, "Failed to publish to wait queue, falling back to DLX",
+			logger.String("wait_queue", waitQueue),
+			logger.WithError(err),
+		)
+		d.Nack(false, false)
+		return
 	}
 
-	return ""
+	c.Logger.Info(ctx, "Message scheduled for retry",
+		logger.String("wait_queue", waitQueue),
+		logger.String("delay", delay.String()),
+		logger.Int("next_retry", retries+1),
+	)
+	d.Ack(false)
 }
 
-// Helper Resilience
+// setupTopology Main Queue, DLX, Wait Queues (uma por tier) e Parking Queue
+func (c *Consumer) setupTopology(ch *amqp.Channel, sub *subscription) error {
+	queueName := sub.Queue
+	policy := c.Retry.PolicyFor(queueName)
 
-// setupTopology Main Queue, DLX, Wait Queue e Parking Queue
-func (c *Consumer) setupTopology(ch *amqp.Channel, queueName string) error {
 	// 1. DLX (Onde caem os rejeitados/erros)
 	if err := ch.ExchangeDeclare(DLXName, "direct", true, false, false, false, nil); err != nil {
 		return err
@@ -317,7 +461,7 @@ func (c *Consumer) setupTopology(ch *amqp.Channel, queueName string) error {
 		return err
 	}
 
-	// 3. Wait Queue (A Fila de Espera)
+	// 3. Wait Queue legada: destino de Nack(false, false) quando a publicação no tier falha.
 	waitQueue := queueName + ".wait"
 	argsWait := amqp.Table{
 		"x-dead-letter-exchange":    MainEx,    // Depois do TTL, volta pro Main Exchange
@@ -332,7 +476,12 @@ func (c *Consumer) setupTopology(ch *amqp.Channel, queueName string) error {
 		return err
 	}
 
-	// 4. Main Queue (A Fila de Trabalho)
+	// 4. Wait Queues por tier (ex: .wait.1s, .wait.10s, .wait.60s, .wait.10m)
+	if err := declareWaitQueues(ch, queueName, policy); err != nil {
+		return err
+	}
+
+	// 5. Main Queue (A Fila de Trabalho)
 	argsMain := amqp.Table{
 		"x-dead-letter-exchange":    DLXName,   // Se der Nack(false, false), vai pra DLX
 		"x-dead-letter-routing-key": queueName, // Mantém a routing key pra cair na Wait Queue correta
@@ -343,80 +492,120 @@ func (c *Consumer) setupTopology(ch *amqp.Channel, queueName string) error {
 	if err := ch.QueueBind(queueName, queueName, MainEx, false, nil); err != nil {
 		return err
 	}
+	// Binding extra da assinatura (ex: gofleet.events, drivers.#).
+	if sub.Exchange != "" && sub.Exchange != MainEx {
+		if err := ch.ExchangeDeclare(sub.Exchange, "topic", true, false, false, false, nil); err != nil {
+			return err
+		}
+	}
+	if sub.BindingKey != "" {
+		if err := ch.QueueBind(queueName, sub.BindingKey, sub.Exchange, false, nil); err != nil {
+			return err
+		}
+	}
 
-	// 5. Parking Queue (Fim da linha)
-	parkingQueue := queueName + ".parking"
-	if _, err := ch.QueueDeclare(parkingQueue, true, false, false, false, nil); err != nil {
+	// 6. Parking Queue (Fim da linha)
+	if _, err := ch.QueueDeclare(ParkingQueueName(queueName), true, false, false, false, nil); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Consumer) getRetryCount(msg amqp.Delivery) int64 {
-	xDeath, ok := msg.Headers["x-death"].([]interface{})
-	if !ok || len(xDeath) == 0 {
-		return 0
-	}
-	for _, death := range xDeath {
-		if table, ok := death.(amqp.Table); ok {
-			// Verifica se o motivo foi 'rejected' (nosso Nack) ou 'expired' (TTL)
-			// Geralmente contamos quantas vezes ele passou pela Wait Queue ou foi rejeitado.
-			// Simplificação: Pegamos o 'count' geral.
-			if count, ok := table["count"].(int64); ok {
-				return count
-			}
-		}
+func cloneHeaders(h amqp.Table) amqp.Table {
+	out := make(amqp.Table, len(h)+1)
+	for k, v := range h {
+		out[k] = v
 	}
-	return 0
+	return out
 }
 
-func (c *Consumer) executeFallback(ctx context.Context, msg []byte) error {
-	var dto order.CreateInput
-	if err := json.Unmarshal(msg, &dto); err != nil {
-		return fmt.Errorf("fallback unmarshal error: %w", err)
-	}
-
-	orderEntity, err := c.OrderRepository.FindByID(ctx, dto.ID)
-	if err != nil {
-		return fmt.Errorf("fallback find order error: %w", err)
+// decodeData normaliza o payload para JSON pelo datacontenttype.
+func (c *Consumer) decodeData(evt *events.CloudEvent) error {
+	switch ct := mediaType(evt.DataContentType); {
+	case ct == "" || ct == ContentTypeJSON:
+		return nil
+	case ct == ContentTypeProtobuf:
+		if c.Codec == nil {
+			return fmt.Errorf("%w: protobuf codec not configured", ErrUndecodable)
+		}
+		version, err := evt.DataVersion()
+		if err != nil {
+			return err
+		}
+		data, err := c.Codec.Decode(evt.EventType(), version, evt.Data)
+		if err != nil {
+			return err
+		}
+		evt.Data = data
+		evt.DataContentType = ContentTypeJSON
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported content type %q", ErrUndecodable, evt.DataContentType)
 	}
+}
 
-	if err := orderEntity.SendToManual(); err != nil {
-		return fmt.Errorf("fallback domain transition error: %w", err)
+// park move a mensagem para o Parking e a remove da fila principal.
+func (c *Consumer) park(ctx context.Context, ch *amqp.Channel, queueName string, d amqp.Delivery, reason string) {
+	if err := c.publishToParking(ch, queueName, d, reason); err != nil {
+		c.Logger.Error(ctx, "CRITICAL: Failed to publish to parking!", logger.WithError(err))
+		// Se não consegue nem mandar pro parking, Nack com Requeue para tentar salvar dnv
+		d.Nack(false, true)
+		return
 	}
+	d.Ack(false) // Remove da fila principal pois já está na parking
+}
 
-	err = c.OrderRepository.UpdateStatus(
-		ctx,
-		orderEntity.ID(),
-		orderEntity.StatusName(), // "MANUAL_DISPATCH"
-		orderEntity.DriverID(),
-	)
-	if err != nil {
-		return fmt.Errorf("fallback save error: %w", err)
-	}
-	return nil
+// parkingReason identifica erros que nenhuma nova tentativa resolve.
+func parkingReason(err error) (string, bool) {
+	switch {
+	case errors.Is(err, events.ErrUnknownVersion):
+		return "unknown-event-version", true
+	case errors.Is(err, events.ErrUnknownEventType):
+		return "unknown-event-type", true
+	case errors.Is(err, events.ErrSchemaViolation), errors.Is(err, events.ErrMissingUpcaster):
+		return "schema-violation", true
+	case errors.Is(err, events.ErrInvalidCloudEvent):
+		return "invalid-cloudevent", true
+	case errors.Is(err, ErrUndecodable):
+		return "undecodable-payload", true
+	}
+	return "", false
 }
 
-func (c *Consumer) publishToParking(ch *amqp.Channel, originalQueue string, msg amqp.Delivery) error {
-	parkingQueue := originalQueue + ".parking"
+// Headers gravados na mensagem ao entrar no Parking.
+const (
+	OriginalQueueHeader = "x-original-queue"
+	FailReasonHeader    = "x-fail-reason"
+	ParkedAtHeader      = "x-parked-at"
+)
+
+// ParkingQueueName monta o nome da fila de Parking (ex: orders.created.parking).
+func ParkingQueueName(queue string) string {
+	return queue + ".parking"
+}
 
+func (c *Consumer) publishToParking(ch *amqp.Channel, originalQueue string, msg amqp.Delivery, reason string) error {
 	headers := msg.Headers
 	if headers == nil {
 		headers = make(amqp.Table)
 	}
-	headers["x-original-queue"] = originalQueue
-	headers["x-fail-reason"] = "max-retries-exceeded"
+	headers[OriginalQueueHeader] = originalQueue
+	headers[FailReasonHeader] = reason
+	headers[ParkedAtHeader] = time.Now().UTC()
 
 	return ch.PublishWithContext(
 		context.Background(),
 		"", // Default Exchange
-		parkingQueue,
+		ParkingQueueName(originalQueue),
 		false,
 		false,
 		amqp.Publishing{
 			Headers:      headers,
 			ContentType:  msg.ContentType,
+			MessageId:    msg.MessageId,
+			Type:         msg.Type,
+			Timestamp:    msg.Timestamp,
 			Body:         msg.Body,
 			DeliveryMode: amqp.Persistent,
 		},
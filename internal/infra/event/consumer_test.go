@@ -0,0 +1,77 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopHandler(context.Context, events.CloudEvent) error { return nil }
+
+func TestConsumer_Subscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     Subscription
+		wantErr string
+	}{
+		{name: "queue is required", sub: Subscription{Handler: noopHandler}, wantErr: "queue is required"},
+		{name: "handler is required", sub: Subscription{Queue: "orders.cancelled"}, wantErr: "handler is required"},
+		{name: "binding key needs exchange", sub: Subscription{Queue: "drivers.moved", Handler: noopHandler, BindingKey: "drivers.#"}, wantErr: "requires an exchange"},
+		{name: "prefetch below workers", sub: Subscription{Queue: "orders.cancelled", Handler: noopHandler, Workers: 4, Prefetch: 2}, wantErr: "prefetch (2) must be >= workers (4)"},
+		{name: "duplicated queue", sub: Subscription{Queue: "orders.created", Handler: noopHandler}, wantErr: "already registered"},
+		{name: "valid", sub: Subscription{Queue: "drivers.moved", Handler: noopHandler, Exchange: "gofleet.events", BindingKey: "drivers.#"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsumer(nil, logger.NewZapLogger("test", false))
+			require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: noopHandler}))
+
+			err := c.Subscribe(tt.sub)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConsumer_SubscribeDefaults(t *testing.T) {
+	c := NewConsumer(nil, logger.NewZapLogger("test", false))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: noopHandler, Workers: 5}))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.cancelled", Handler: noopHandler}))
+
+	assert.Equal(t, 10, c.subs[0].Prefetch)
+	assert.Equal(t, 1, c.subs[1].Workers)
+	assert.Equal(t, 2, c.subs[1].Prefetch)
+}
+
+func TestConsumer_RunWithoutSubscriptions(t *testing.T) {
+	c := NewConsumer(nil, logger.NewZapLogger("test", false))
+	assert.Error(t, c.Run(context.Background()))
+}
+
+func TestChain_FirstMiddlewareIsOutermost(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next MessageHandler) MessageHandler {
+			return func(ctx context.Context, evt events.CloudEvent) error {
+				order = append(order, name)
+				return next(ctx, evt)
+			}
+		}
+	}
+
+	h := Chain(func(context.Context, events.CloudEvent) error {
+		order = append(order, "handler")
+		return nil
+	}, mw("upcasting"), mw("backoff"), mw("idempotency"))
+
+	require.NoError(t, h(context.Background(), events.CloudEvent{}))
+	assert.Equal(t, []string{"upcasting", "backoff", "idempotency", "handler"}, order)
+}
@@ -0,0 +1,161 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAck registra o desfecho de uma entrega.
+type fakeAck struct {
+	mu      sync.Mutex
+	acked   bool
+	nacked  bool
+	requeue bool
+	done    chan struct{}
+}
+
+func newFakeAck() *fakeAck { return &fakeAck{done: make(chan struct{})} }
+
+func (a *fakeAck) Ack(uint64, bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.acked = true
+	close(a.done)
+	return nil
+}
+
+func (a *fakeAck) Nack(_ uint64, _ bool, requeue bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.nacked, a.requeue = true, requeue
+	close(a.done)
+	return nil
+}
+
+func (a *fakeAck) Reject(_ uint64, requeue bool) error { return a.Nack(0, false, requeue) }
+
+func legacyDelivery(ack amqp.Acknowledger, tag uint64) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		DeliveryTag:  tag,
+		MessageId:    "evt-1",
+		Headers:      amqp.Table{"x-event-id": "evt-1"},
+		ContentType:  ContentTypeJSON,
+		Type:         "OrderCreated",
+		Body:         []byte(`{}`),
+	}
+}
+
+func TestWorkerPool_DrainingRequeuesUnstartedDeliveries(t *testing.T) {
+	called := false
+	c := NewConsumer(nil, logger.NewZapLogger("test", false))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: func(context.Context, events.CloudEvent) error {
+		called = true
+		return nil
+	}}))
+	pool := newWorkerPool(context.Background(), c, c.subs[0], nil)
+	pool.draining.Store(true)
+	pool.resize(1)
+
+	ack := newFakeAck()
+	pool.work <- legacyDelivery(ack, 1)
+	<-ack.done
+	<-pool.wait()
+
+	assert.False(t, called)
+	assert.True(t, ack.nacked)
+	assert.True(t, ack.requeue)
+}
+
+func TestConsumer_RequeuesHandlerAbortedByDrain(t *testing.T) {
+	c := NewConsumer(nil, logger.NewZapLogger("test", false))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: func(ctx context.Context, _ events.CloudEvent) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}))
+
+	ctx, abort := context.WithCancel(context.Background())
+	abort()
+	ack := newFakeAck()
+	c.handleMessage(ctx, legacyDelivery(ack, 1), c.subs[0], nil)
+
+	// Sem contar tentativa e sem wait queue: direto de volta para a fila.
+	assert.True(t, ack.nacked)
+	assert.True(t, ack.requeue)
+}
+
+func TestWorkerPool_ReportsLateMessages(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewConsumer(nil, logger.NewZapLogger("test", false), WithConsumerMetrics(metrics.NewPrometheusMetrics(reg, "test")))
+	require.NoError(t, c.Subscribe(Subscription{Queue: "orders.created", Handler: noopHandler}))
+	pool := newWorkerPool(context.Background(), c, c.subs[0], nil)
+
+	pool.track(amqp.Delivery{DeliveryTag: 1, MessageId: "evt-1"}, 0, time.Now().Add(-15*time.Second))
+	pool.track(amqp.Delivery{DeliveryTag: 2, MessageId: "evt-2"}, 1, time.Now())
+	pool.untrack(amqp.Delivery{DeliveryTag: 2})
+
+	assert.Equal(t, 1, pool.reportLate(context.Background()))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var late float64
+	for _, f := range families {
+		if f.GetName() == "app_consumer_drain_late_total" {
+			late = f.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, 1.0, late)
+}
+
+// releaseStore guarda as chaves e falha o Release se receber um ctx já cancelado.
+type releaseStore struct {
+	keys map[string]string
+}
+
+func (s *releaseStore) Acquire(_ context.Context, key, owner string, _ time.Duration) (idempotency.Record, error) {
+	if cur, ok := s.keys[key]; ok {
+		return idempotency.Record{State: idempotency.StateProcessing, Owner: cur}, nil
+	}
+	s.keys[key] = owner
+	return idempotency.Record{State: idempotency.StateProcessing, Owner: owner}, nil
+}
+
+func (s *releaseStore) Renew(context.Context, string, string, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (s *releaseStore) Complete(context.Context, string, string, []byte, time.Duration) error {
+	return nil
+}
+
+func (s *releaseStore) Release(ctx context.Context, key, _ string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	delete(s.keys, key)
+	return nil
+}
+
+func TestIdempotency_ReleasesLockWhenHandlerIsAborted(t *testing.T) {
+	store := &releaseStore{keys: map[string]string{}}
+	h := WrapIdempotency(logger.NewZapLogger("test", false), idempotency.NewGuard(store), "orders",
+		func(ctx context.Context, _ events.CloudEvent) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	ctx, abort := context.WithCancel(context.Background())
+	go abort()
+	assert.ErrorIs(t, h(ctx, events.CloudEvent{ID: "evt-1"}), context.Canceled)
+	assert.Empty(t, store.keys, "lock must be released so the redelivery is processed")
+}
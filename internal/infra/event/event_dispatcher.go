@@ -2,7 +2,6 @@ package event
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/DioGolang/GoFleet/pkg/events"
@@ -12,93 +11,132 @@ import (
 	"go.opentelemetry.io/otel"
 )
 
+// Dispatcher publica em confirm mode: um evento só conta como publicado depois do
+// ack do broker, e (em rotas mandatory) mensagens sem fila de destino viram erro.
+// Toda mensagem sai como CloudEvent em binary content mode. Só publica RawMessage:
+// a rota depende de agregado e topic, que só a linha do outbox conhece.
 type Dispatcher struct {
-	RabbitMQChannel *amqp.Channel
-	Logger          logger.Logger
+	Publisher Publisher
+	Routes    *RoutingTable
+	Logger    logger.Logger
+	// Source é o atributo source dos CloudEvents publicados.
+	Source string
+	// Codec converte payloads para protobuf nos topics com encoding protobuf.
+	Codec *ProtoCodec
 }
 
-func NewDispatcher(ch *amqp.Channel, log logger.Logger) *Dispatcher {
-	return &Dispatcher{RabbitMQChannel: ch, Logger: log}
+func NewDispatcher(pub Publisher, routes *RoutingTable, log logger.Logger) *Dispatcher {
+	return &Dispatcher{Publisher: pub, Routes: routes, Logger: log, Source: DefaultEventSource, Codec: NewOrderEventsCodec()}
 }
 
-func (ed *Dispatcher) Dispatch(ctx context.Context, event events.Event) error {
-	headers := make(amqp.Table)
-	otel.GetTextMapPropagator().Inject(ctx, carrier.AMQPHeadersCarrier(headers))
-
-	ed.Logger.Debug(ctx, "Preparing to publish event",
-		logger.String("event", event.GetName()),
-	)
-
-	payload, err := json.Marshal(event.GetPayload())
+// DispatchRaw publica e bloqueia até a confirmação do broker.
+func (ed *Dispatcher) DispatchRaw(ctx context.Context, msg events.RawMessage) error {
+	conf, err := ed.DispatchAsync(ctx, msg)
 	if err != nil {
-		ed.Logger.Error(ctx, "Failed to marshal event payload",
-			logger.String("event", event.GetName()),
-			logger.WithError(err),
-		)
 		return err
 	}
+	return conf.Wait(ctx)
+}
 
-	err = ed.RabbitMQChannel.PublishWithContext(
-		ctx,
-		"orders_exchange",
-		"orders.created",
-		false,
-		false,
-		amqp.Publishing{
-			Headers:     headers,
-			ContentType: "application/json",
-			Timestamp:   time.Now(),
-			Body:        payload,
-		})
+func (ed *Dispatcher) DispatchAsync(ctx context.Context, msg events.RawMessage) (events.Confirmation, error) {
+	dest, err := ed.Routes.Resolve(msg.AggregateType, msg.EventType, msg.Topic)
 	if err != nil {
-		ed.Logger.Error(ctx, "Failed to publish message to RabbitMQ",
-			logger.String("event", event.GetName()),
+		ed.Logger.Error(ctx, "Event has no route",
+			logger.String("aggregate_type", msg.AggregateType),
+			logger.String("event", msg.EventType),
 			logger.WithError(err),
 		)
-		return err
+		return nil, err
 	}
 
-	ed.Logger.Info(ctx, "Event published to RabbitMQ",
-		logger.String("event", event.GetName()),
-		logger.String("exchange", "amq.direct"),
-		logger.String("routing_key", "orders.created"),
-	)
-	return nil
-}
-
-// DispatchRaw agora aceita 'headers map[string]string' para satisfazer a interface
-func (ed *Dispatcher) DispatchRaw(ctx context.Context, routingKey string, payload []byte, headers map[string]string) error {
 	amqpHeaders := make(amqp.Table)
-
-	for k, v := range headers {
+	for k, v := range msg.Headers {
 		amqpHeaders[k] = v
 	}
 
 	otel.GetTextMapPropagator().Inject(ctx, carrier.AMQPHeadersCarrier(amqpHeaders))
 
-	ed.Logger.Debug(ctx, "Dispatching with headers", logger.Any("headers", amqpHeaders))
+	ce := ed.envelope(msg)
+	if err := ed.encode(ctx, &ce, msg, dest.RoutingKey); err != nil {
+		return nil, err
+	}
+	if err := ce.Validate(); err != nil {
+		ed.Logger.Error(ctx, "Refusing to publish invalid CloudEvent",
+			logger.String("event", msg.EventType),
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+
+	ed.Logger.Debug(ctx, "Dispatching with headers",
+		logger.String("exchange", dest.Exchange),
+		logger.String("routing_key", dest.RoutingKey),
+		logger.String("ce_id", ce.ID),
+		logger.String("ce_type", ce.Type),
+		logger.Any("headers", amqpHeaders),
+	)
 
-	msgID := ""
-	if v, ok := headers["x-event-id"]; ok {
-		msgID = v
+	conf, err := ed.Publisher.Publish(ctx, dest, toPublishing(ce, amqpHeaders))
+	if err != nil {
+		return nil, err
 	}
+	return conf, nil
+}
 
-	err := ed.RabbitMQChannel.PublishWithContext(
-		ctx,
-		"orders_exchange",
-		routingKey,
-		false,
-		false,
-		amqp.Publishing{
-			Headers:      amqpHeaders,
-			ContentType:  "application/json",
-			Timestamp:    time.Now(),
-			MessageId:    msgID,
-			DeliveryMode: amqp.Persistent,
-			Body:         payload,
-		})
+// envelope monta o CloudEvent: subject é o agregado e time é a criação do evento.
+func (ed *Dispatcher) envelope(msg events.RawMessage) events.CloudEvent {
+	source := ed.Source
+	if source == "" {
+		source = DefaultEventSource
+	}
+	at := msg.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return events.CloudEvent{
+		ID:              msg.ID,
+		Source:          source,
+		Type:            events.TypeFor(msg.AggregateType, msg.EventType),
+		SpecVersion:     events.SpecVersion,
+		Time:            at,
+		Subject:         msg.AggregateID,
+		DataContentType: ContentTypeJSON,
+		Data:            msg.Payload,
+		Extensions:      msg.Extensions,
+	}
+}
+
+// encode troca o payload JSON por protobuf quando o topic pede. Sem mensagem
+// registrada para o evento/versão, o payload segue em JSON: o consumidor decide
+// pelo content type, então os dois formatos convivem no mesmo topic.
+func (ed *Dispatcher) encode(ctx context.Context, ce *events.CloudEvent, msg events.RawMessage, topic string) error {
+	if ed.Routes.EncodingFor(topic) != EncodingProtobuf || ed.Codec == nil {
+		return nil
+	}
+	version, err := ce.DataVersion()
+	if err != nil {
+		return err
+	}
+	if !ed.Codec.Supports(msg.EventType, version) {
+		ed.Logger.Warn(ctx, "No protobuf message for event, publishing JSON",
+			logger.String("event", msg.EventType),
+			logger.Int("version", int(version)),
+			logger.String("topic", topic),
+		)
+		return nil
+	}
 
-	return err
+	data, err := ed.Codec.Encode(msg.EventType, version, msg.Payload)
+	if err != nil {
+		ed.Logger.Error(ctx, "Failed to encode event as protobuf",
+			logger.String("event", msg.EventType),
+			logger.WithError(err),
+		)
+		return err
+	}
+	ce.Data = data
+	ce.DataContentType = ContentTypeProtobuf
+	return nil
 }
 
 func (ed *Dispatcher) Register(eventName string, handler events.EventHandler) error { return nil }
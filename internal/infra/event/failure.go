@@ -0,0 +1,130 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Classes de falha no consumo. O Consumer escolhe o destino da mensagem pela classe:
+// permanente vai ao Parking, transitória volta pelas wait queues.
+var (
+	ErrPermanent = errors.New("permanent failure")
+	ErrTransient = errors.New("transient failure")
+)
+
+// defaultPermanentReason é o x-fail-reason quando o handler não informa um.
+const defaultPermanentReason = "permanent-failure"
+
+// HandlerError é uma falha já classificada. errors.Is(err, ErrPermanent) e
+// errors.Is(err, ErrTransient) identificam a classe; o erro original continua
+// alcançável por errors.Is/As.
+type HandlerError struct {
+	class error
+	// Reason vai no x-fail-reason quando a falha é permanente.
+	Reason string
+	// Delay é a espera mínima antes da próxima tentativa (RetryAfter).
+	Delay time.Duration
+	Err   error
+}
+
+// Permanent marca uma falha que nenhuma nova tentativa resolve.
+func Permanent(reason string, err error) error {
+	if reason == "" {
+		reason = defaultPermanentReason
+	}
+	return &HandlerError{class: ErrPermanent, Reason: reason, Err: err}
+}
+
+// Transient marca uma falha que pode passar sozinha (rede, dependência fora).
+func Transient(err error) error {
+	return &HandlerError{class: ErrTransient, Err: err}
+}
+
+// RetryAfter é uma falha transitória que só deve ser tentada de novo depois de d.
+func RetryAfter(d time.Duration, err error) error {
+	return &HandlerError{class: ErrTransient, Delay: d, Err: err}
+}
+
+func (e *HandlerError) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.class.Error()
+	case e.Delay > 0:
+		return fmt.Sprintf("%v (retry after %s)", e.Err, e.Delay)
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *HandlerError) Unwrap() []error {
+	return []error{e.class, e.Err}
+}
+
+func (e *HandlerError) Permanent() bool {
+	return e.class == ErrPermanent
+}
+
+// Classify devolve a classe de um erro de handler. Erros já classificados são
+// respeitados; os demais são mapeados pela origem:
+//   - contrato do evento (schema, versão, envelope): permanente;
+//   - status gRPC (ex: SearchDriver): pelo código, com RetryInfo virando RetryAfter;
+//   - regra de domínio (validação, conflito, permissão): permanente;
+//   - todo o resto, incluindo circuit breaker aberto e timeout: transitório.
+func Classify(err error) *HandlerError {
+	var he *HandlerError
+	if errors.As(err, &he) {
+		return he
+	}
+	if reason, ok := parkingReason(err); ok {
+		return &HandlerError{class: ErrPermanent, Reason: reason, Err: err}
+	}
+	if he := classifyGRPC(err); he != nil {
+		return he
+	}
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		switch appErr.Kind {
+		case apperror.KindValidation, apperror.KindConflict, apperror.KindForbidden:
+			return &HandlerError{class: ErrPermanent, Reason: "rejected-by-domain", Err: err}
+		}
+	}
+	return &HandlerError{class: ErrTransient, Err: err}
+}
+
+// Códigos gRPC que indicam problema na requisição, não no servidor.
+var permanentGRPCCodes = map[codes.Code]string{
+	codes.InvalidArgument:    "grpc-invalid-argument",
+	codes.NotFound:           "grpc-not-found",
+	codes.AlreadyExists:      "grpc-already-exists",
+	codes.PermissionDenied:   "grpc-permission-denied",
+	codes.FailedPrecondition: "grpc-failed-precondition",
+	codes.OutOfRange:         "grpc-out-of-range",
+	codes.Unimplemented:      "grpc-unimplemented",
+	codes.Unauthenticated:    "grpc-unauthenticated",
+}
+
+func classifyGRPC(err error) *HandlerError {
+	var withStatus interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &withStatus) {
+		return nil
+	}
+	st := withStatus.GRPCStatus()
+
+	if reason, ok := permanentGRPCCodes[st.Code()]; ok {
+		return &HandlerError{class: ErrPermanent, Reason: reason, Err: err}
+	}
+	// Unavailable, ResourceExhausted, DeadlineExceeded, Aborted, Internal...: o
+	// servidor pode pedir uma espera mínima via google.rpc.RetryInfo.
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return &HandlerError{class: ErrTransient, Delay: info.GetRetryDelay().AsDuration(), Err: err}
+		}
+	}
+	return &HandlerError{class: ErrTransient, Err: err}
+}
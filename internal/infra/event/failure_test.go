@@ -0,0 +1,140 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestHandlerError_IsAs(t *testing.T) {
+	cause := errors.New("boom")
+	err := fmt.Errorf("process order: %w", Permanent("invalid-payload", cause))
+
+	assert.ErrorIs(t, err, ErrPermanent)
+	assert.NotErrorIs(t, err, ErrTransient)
+	assert.ErrorIs(t, err, cause)
+
+	var he *HandlerError
+	require.ErrorAs(t, err, &he)
+	assert.Equal(t, "invalid-payload", he.Reason)
+	assert.Equal(t, "process order: boom", err.Error())
+
+	retry := RetryAfter(5*time.Second, cause)
+	assert.ErrorIs(t, retry, ErrTransient)
+	assert.Equal(t, "boom (retry after 5s)", retry.Error())
+
+	assert.Equal(t, defaultPermanentReason, Classify(Permanent("", cause)).Reason)
+}
+
+func retryInfoStatus(t *testing.T, code codes.Code, delay time.Duration) error {
+	t.Helper()
+	st, err := status.New(code, "busy").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	require.NoError(t, err)
+	return st.Err()
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+		reason    string
+		delay     time.Duration
+	}{
+		{name: "plain error is transient", err: errors.New("db down")},
+		{name: "explicit transient", err: Transient(errors.New("x"))},
+		{name: "retry after", err: RetryAfter(time.Minute, errors.New("x")), delay: time.Minute},
+		{name: "schema violation", err: fmt.Errorf("wrap: %w", events.ErrSchemaViolation), permanent: true, reason: "schema-violation"},
+		{name: "undecodable", err: ErrUndecodable, permanent: true, reason: "undecodable-payload"},
+		{name: "open circuit is transient", err: gobreaker.ErrOpenState},
+		{name: "deadline is transient", err: context.DeadlineExceeded},
+		{
+			name:      "grpc invalid argument",
+			err:       fmt.Errorf("grpc search driver failed: %w", status.Error(codes.InvalidArgument, "bad")),
+			permanent: true, reason: "grpc-invalid-argument",
+		},
+		{name: "grpc not found", err: status.Error(codes.NotFound, "no order"), permanent: true, reason: "grpc-not-found"},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "down")},
+		{name: "grpc unknown", err: status.Error(codes.Unknown, "??")},
+		{
+			name:  "grpc retry info",
+			err:   fmt.Errorf("grpc search driver failed: %w", retryInfoStatus(t, codes.ResourceExhausted, 30*time.Second)),
+			delay: 30 * time.Second,
+		},
+		{
+			name:      "domain conflict",
+			err:       fmt.Errorf("domain rule violation: %w", apperror.Conflict("invalid_transition", "cannot dispatch", nil)),
+			permanent: true, reason: "rejected-by-domain",
+		},
+		{name: "order not found yet is transient", err: apperror.NotFound("order_not_found", "order not found", nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.err)
+			assert.Equal(t, tt.permanent, got.Permanent())
+			assert.Equal(t, tt.reason, got.Reason)
+			assert.Equal(t, tt.delay, got.Delay)
+			assert.ErrorIs(t, got, tt.err)
+		})
+	}
+}
+
+func TestWrapExponentialBackoff_Classification(t *testing.T) {
+	m := metrics.NewPrometheusMetrics(prometheus.NewRegistry(), "test")
+	log := logger.NewZapLogger("test", false)
+
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{name: "permanent is not retried", err: Permanent("invalid-payload", errors.New("bad json")), calls: 1},
+		{name: "long retry after goes to the wait queue", err: RetryAfter(time.Minute, errors.New("busy")), calls: 1},
+		{name: "short retry after goes to the wait queue", err: RetryAfter(30*time.Second, errors.New("no driver")), calls: 1},
+		{name: "transient is retried", err: errors.New("flaky"), calls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			h := WrapExponentialBackoff(log, m, "test", 1, time.Millisecond, func(context.Context, events.CloudEvent) error {
+				calls++
+				return tt.err
+			})
+
+			err := h(context.Background(), events.CloudEvent{})
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestWrapExponentialBackoff_RetryAfterDoesNotSleep(t *testing.T) {
+	m := metrics.NewPrometheusMetrics(prometheus.NewRegistry(), "test")
+	h := WrapExponentialBackoff(logger.NewZapLogger("test", false), m, "test", 3, time.Second,
+		func(context.Context, events.CloudEvent) error {
+			return RetryAfter(time.Second, errors.New("no driver"))
+		})
+
+	start := time.Now()
+	err := h(context.Background(), events.CloudEvent{})
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "worker must not hold the message in-process")
+
+	failure := Classify(err)
+	assert.Equal(t, time.Second, failure.Delay)
+}
@@ -3,80 +3,72 @@ package event
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
-	"time"
 
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+	"github.com/DioGolang/GoFleet/pkg/events"
 	"github.com/DioGolang/GoFleet/pkg/logger"
 )
 
-type RedisIdempotencyStore interface {
-	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
-	Del(ctx context.Context, key string) error
-}
-
+// WrapIdempotency executa next no máximo uma vez por evento e handler. A chave
+// fica em PROCESSING com lease renovado enquanto next roda e vira COMPLETED no
+// sucesso; se o worker cair no meio, o lease vence e a reentrega a retoma.
 func WrapIdempotency(
 	log logger.Logger,
-	store RedisIdempotencyStore,
+	guard *idempotency.Guard,
 	handlerName string,
-	ttl time.Duration,
 	next MessageHandler,
 ) MessageHandler {
 
-	return func(ctx context.Context, msg []byte, headers map[string]interface{}) error {
-
-		var eventID string
-
-		if v, ok := headers["x-event-id"]; ok {
-			eventID = fmt.Sprintf("%v", v)
-		}
-
-		if eventID == "" {
-			hash := sha256.Sum256(msg)
-			eventID = fmt.Sprintf("hash:%x", hash)
-		}
+	return func(ctx context.Context, evt events.CloudEvent) error {
 
-		key := fmt.Sprintf("dedup:%s:%s", handlerName, eventID)
+		eventID := dedupID(evt)
+		key := fmt.Sprintf("%s:%s", handlerName, eventID)
 
-		saved, err := store.SetNX(ctx, key, "processing", ttl)
+		outcome, err := guard.Do(ctx, key, func(ctx context.Context) ([]byte, error) {
+			return nil, next(ctx, evt)
+		})
 
-		if err != nil {
-			// CRÍTICO: Se o Redis cair, o que fazemos?
-			// Opção A (Fail Open): Processa e corre risco de duplicação.
-			// Opção B (Fail Closed): Retorna erro e para o consumo (Segurança).
-			log.Error(ctx, "Redis unavailable for idempotency check",
-				logger.WithError(err))
+		switch {
+		case errors.Is(err, idempotency.ErrInProgress):
+			// Outra réplica está processando (ex: reentrega após timeout do canal).
+			// Volta depois do lease: ou ela concluiu, ou o lease venceu e a chave é retomada.
+			log.Info(ctx, "Event is being processed elsewhere, retrying after lease",
+				logger.String("key", key),
+			)
+			return RetryAfter(guard.Lease(), err)
 
-			return fmt.Errorf("idempotency store unavailable: %w", err)
-		}
+		case err != nil:
+			log.Warn(ctx, "Handler logic failed, idempotency key released for retry",
+				logger.String("key", key),
+				logger.WithError(err),
+			)
+			return err
 
-		if !saved {
-			// DUPLICATA DETECTADA
+		case outcome.Replayed:
 			log.Info(ctx, "Duplicate event dropped by Idempotency Guard",
 				logger.String("handler", handlerName),
 				logger.String("event_id", eventID),
 			)
-			return nil // Ack silencioso (Sucesso falso)
-		}
-
-		// 3. Execução do Handler Real
-		err = next(ctx, msg, headers)
+			return nil
 
-		// 4. Tratamento de Falha do Negócio
-		if err != nil {
-			log.Warn(ctx, "Handler logic failed, releasing lock for retry",
+		case outcome.CompleteErr != nil:
+			// O efeito já aconteceu: Ack mesmo assim. Até o lease vencer a chave ainda deduplica.
+			log.Error(ctx, "Failed to mark idempotency key as completed",
 				logger.String("key", key),
-				logger.WithError(err),
+				logger.WithError(outcome.CompleteErr),
 			)
-
-			// Remove a chave para que o próximo Retry (da Wait Queue)
-			if delErr := store.Del(ctx, key); delErr != nil {
-				log.Error(ctx, "Failed to release idempotency lock (Zombie Key Risk)",
-					logger.String("key", key),
-					logger.WithError(delErr),
-				)
-			}
 		}
+		return nil
+	}
+}
 
-		return err
+// dedupID é o id do CloudEvent, único por source; sem ele, deduplicamos pelo conteúdo.
+func dedupID(evt events.CloudEvent) string {
+	if evt.ID != "" {
+		return evt.ID
 	}
+	hash := sha256.Sum256(evt.Data)
+	return fmt.Sprintf("hash:%x", hash)
 }
@@ -0,0 +1,50 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency_InProgressRetriesAfterLease(t *testing.T) {
+	store := &releaseStore{keys: map[string]string{"orders:evt-1": "other-replica"}}
+	guard := idempotency.NewGuard(store, idempotency.WithLease(45*time.Second))
+	h := WrapIdempotency(logger.NewZapLogger("test", false), guard, "orders",
+		func(context.Context, events.CloudEvent) error {
+			t.Fatal("handler must not run while another replica holds the lease")
+			return nil
+		})
+
+	err := h(context.Background(), events.CloudEvent{ID: "evt-1"})
+	require.ErrorIs(t, err, idempotency.ErrInProgress)
+	he := Classify(err)
+	assert.False(t, he.Permanent())
+	assert.Equal(t, 45*time.Second, he.Delay)
+}
+
+func TestIdempotency_InProgressSkipsInProcessBackoff(t *testing.T) {
+	store := &releaseStore{keys: map[string]string{"orders:evt-1": "other-replica"}}
+	guard := idempotency.NewGuard(store, idempotency.WithLease(30*time.Second))
+	log := logger.NewZapLogger("test", false)
+
+	// Mesma ordem da pilha do worker: o backoff fica por fora do guard.
+	h := WrapExponentialBackoff(log, metrics.NewPrometheusMetrics(prometheus.NewRegistry(), "test"), "orders", 3, time.Second,
+		WrapIdempotency(log, guard, "orders", func(context.Context, events.CloudEvent) error {
+			t.Fatal("handler must not run while another replica holds the lease")
+			return nil
+		}))
+
+	start := time.Now()
+	err := h(context.Background(), events.CloudEvent{ID: "evt-1"})
+	require.ErrorIs(t, err, idempotency.ErrInProgress)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "lease wait belongs to the wait queues")
+	assert.Equal(t, 30*time.Second, Classify(err).Delay)
+}
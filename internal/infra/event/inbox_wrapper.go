@@ -0,0 +1,34 @@
+package event
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/infra/inbox"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// WrapInbox identifica a mensagem como (consumer, id do evento) no ctx. O
+// handler grava essa linha no inbox na mesma transação do efeito
+// (RepositoryProvider.Inbox); uma reentrega esbarra na PK, a transação é
+// desfeita e aqui a mensagem é confirmada sem efeito.
+//
+// Deve ficar dentro do ResilienceMiddleware: a duplicata vira sucesso antes de
+// chegar ao circuit breaker.
+func WrapInbox(log logger.Logger, consumer string, next MessageHandler) MessageHandler {
+	return func(ctx context.Context, evt events.CloudEvent) error {
+		msg := inbox.Message{Consumer: consumer, EventID: dedupID(evt)}
+
+		err := next(inbox.WithMessage(ctx, msg), evt)
+		if errors.Is(err, outbound.ErrDuplicateMessage) {
+			log.Info(ctx, "Duplicate event dropped by inbox",
+				logger.String("consumer", consumer),
+				logger.String("event_id", msg.EventID),
+			)
+			return nil
+		}
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/infra/inbox"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapInbox(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name    string
+		handler error
+		wantErr error
+	}{
+		{name: "success", handler: nil},
+		{name: "duplicate is acked", handler: fmt.Errorf("%w: orders/evt-1", outbound.ErrDuplicateMessage)},
+		{name: "other errors pass through", handler: boom, wantErr: boom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got inbox.Message
+			h := WrapInbox(logger.NewZapLogger("test", false), "orders", func(ctx context.Context, _ events.CloudEvent) error {
+				msg, ok := inbox.MessageFrom(ctx)
+				require.True(t, ok)
+				got = msg
+				return tt.handler
+			})
+
+			err := h(context.Background(), events.CloudEvent{ID: "evt-1"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, inbox.Message{Consumer: "orders", EventID: "evt-1"}, got)
+		})
+	}
+}
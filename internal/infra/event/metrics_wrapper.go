@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/DioGolang/GoFleet/pkg/events"
 	"github.com/DioGolang/GoFleet/pkg/metrics"
 	"github.com/sony/gobreaker"
 )
@@ -16,14 +17,14 @@ func WrapResilientConsumer(
 	cb *gobreaker.CircuitBreaker,
 	next MessageHandler,
 ) MessageHandler {
-	return func(ctx context.Context, msg []byte, headers map[string]interface{}) error {
+	return func(ctx context.Context, evt events.CloudEvent) error {
 		start := time.Now()
 
 		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		_, err := cb.Execute(func() (interface{}, error) {
-			return nil, next(ctx, msg, headers)
+			return nil, next(ctx, evt)
 		})
 
 		if errors.Is(err, gobreaker.ErrOpenState) {
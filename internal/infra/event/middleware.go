@@ -0,0 +1,54 @@
+package event
+
+import (
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/idempotency"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/sony/gobreaker"
+)
+
+// Middleware envolve um MessageHandler (ex: idempotência, retry, upcasting).
+type Middleware func(next MessageHandler) MessageHandler
+
+// Chain aplica os middlewares em h; o primeiro da lista fica mais externo.
+func Chain(h MessageHandler, mws ...Middleware) MessageHandler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Versões Middleware dos wrappers, para montar pilhas reutilizáveis entre assinaturas.
+
+func UpcastingMiddleware(log logger.Logger, registry *events.SchemaRegistry) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return WrapUpcasting(log, registry, next)
+	}
+}
+
+func BackoffMiddleware(log logger.Logger, m metrics.Metrics, handlerName string, maxRetries int, baseWait time.Duration) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return WrapExponentialBackoff(log, m, handlerName, maxRetries, baseWait, next)
+	}
+}
+
+func IdempotencyMiddleware(log logger.Logger, guard *idempotency.Guard, handlerName string) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return WrapIdempotency(log, guard, handlerName, next)
+	}
+}
+
+func InboxMiddleware(log logger.Logger, consumer string) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return WrapInbox(log, consumer, next)
+	}
+}
+
+func ResilienceMiddleware(m metrics.Metrics, handlerName string, timeout time.Duration, cb *gobreaker.CircuitBreaker) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return WrapResilientConsumer(m, handlerName, timeout, cb, next)
+	}
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/application/usecase/order"
+	domainevent "github.com/DioGolang/GoFleet/internal/domain/event"
+	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// OrderHandler processa OrderCreated: busca motorista no Fleet e despacha o pedido.
+// Registrado no Consumer como Handler (ProcessOrder) e Fallback de orders.created.
+type OrderHandler struct {
+	GrpcClient      pb.FleetServiceClient
+	OrderRepository outbound.OrderRepository
+	DispatchUseCase order.DispatchUseCase
+	Logger          logger.Logger
+}
+
+func NewOrderHandler(
+	grpcClient pb.FleetServiceClient,
+	repo outbound.OrderRepository,
+	dispatchUseCase order.DispatchUseCase,
+	l logger.Logger,
+) *OrderHandler {
+	return &OrderHandler{
+		GrpcClient:      grpcClient,
+		OrderRepository: repo,
+		DispatchUseCase: dispatchUseCase,
+		Logger:          l,
+	}
+}
+
+// ProcessOrder roda atrás do IdempotencyMiddleware, que garante uma execução por evento.
+func (h *OrderHandler) ProcessOrder(ctx context.Context, evt events.CloudEvent) error {
+	return h.executeBusinessLogic(ctx, evt.Data)
+}
+
+func (h *OrderHandler) executeBusinessLogic(ctx context.Context, msg []byte) error {
+	// O payload já chega na versão atual (WrapUpcasting).
+	var orderDto domainevent.OrderCreatedPayload
+	if err := json.Unmarshal(msg, &orderDto); err != nil {
+		// Poison message: JSON inválido não melhora com retry.
+		return Permanent("invalid-payload", fmt.Errorf("invalid json: %w", err))
+	}
+
+	// O status gRPC segue no erro: Classify decide entre Parking e retry pelo código.
+	req := &pb.SearchDriverRequest{OrderId: orderDto.ID}
+	res, err := h.GrpcClient.SearchDriver(ctx, req)
+	if err != nil {
+		return fmt.Errorf("grpc search driver failed: %w", err)
+	}
+
+	input := order.DispatchInput{OrderID: orderDto.ID, DriverID: res.DriverId}
+
+	// AQUI MORA A CONSISTÊNCIA EVENTUAL
+	// Se o DispatchUseCase buscar o pedido no banco e não achar (porque o evento chegou antes da escrita),
+	// ele deve retornar um erro.
+	if err := h.DispatchUseCase.Execute(ctx, input); err != nil {
+		return err // A chave de idempotência é liberada e a msg vai pra Wait Queue
+	}
+
+	return nil
+}
+
+// Fallback manda o pedido para despacho manual quando o circuito do Fleet está aberto.
+func (h *OrderHandler) Fallback(ctx context.Context, evt events.CloudEvent) error {
+	return h.executeFallback(ctx, evt.Data)
+}
+
+func (h *OrderHandler) executeFallback(ctx context.Context, msg []byte) error {
+	var dto domainevent.OrderCreatedPayload
+	if err := json.Unmarshal(msg, &dto); err != nil {
+		return fmt.Errorf("fallback unmarshal error: %w", err)
+	}
+
+	orderEntity, err := h.OrderRepository.FindByID(ctx, dto.ID)
+	if err != nil {
+		return fmt.Errorf("fallback find order error: %w", err)
+	}
+
+	if err := orderEntity.SendToManual(); err != nil {
+		return fmt.Errorf("fallback domain transition error: %w", err)
+	}
+
+	err = h.OrderRepository.UpdateStatus(
+		ctx,
+		orderEntity.ID(),
+		orderEntity.StatusName(), // "MANUAL_DISPATCH"
+		orderEntity.DriverID(),
+	)
+	if err != nil {
+		return fmt.Errorf("fallback save error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package event
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.yaml.in/yaml/v3"
+)
+
+// RetryHeader conta quantas vezes a mensagem já voltou de uma wait queue.
+// É gravado por nós a cada retry; x-death não é usado porque soma passagens
+// por filas diferentes e muda de formato entre versões do RabbitMQ.
+const RetryHeader = "x-retry"
+
+// RetryPolicy define os tiers de espera e o total de tentativas de uma fila.
+// A tentativa n (0 = primeira entrega) que falha vai para o tier min(n, len-1).
+type RetryPolicy struct {
+	Tiers       []time.Duration `yaml:"tiers"`
+	MaxAttempts int             `yaml:"max_attempts"` // Inclui a primeira entrega
+}
+
+// DefaultRetryPolicy: 1s, 10s, 60s e 10m, com 5 tentativas no total.
+var DefaultRetryPolicy = RetryPolicy{
+	Tiers:       []time.Duration{time.Second, 10 * time.Second, time.Minute, 10 * time.Minute},
+	MaxAttempts: 5,
+}
+
+// RetryTopology é carregada de configs/retry.yaml.
+type RetryTopology struct {
+	Default RetryPolicy            `yaml:"default"`
+	Queues  map[string]RetryPolicy `yaml:"queues"`
+}
+
+func DefaultRetryTopology() *RetryTopology {
+	return &RetryTopology{Default: DefaultRetryPolicy}
+}
+
+func LoadRetryTopology(path string) (*RetryTopology, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retry config: %w", err)
+	}
+	t := RetryTopology{Default: DefaultRetryPolicy}
+	if err := yaml.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("invalid retry config: %w", err)
+	}
+	// Campos omitidos numa fila herdam do default.
+	for queue, p := range t.Queues {
+		if len(p.Tiers) == 0 {
+			p.Tiers = t.Default.Tiers
+		}
+		if p.MaxAttempts == 0 {
+			p.MaxAttempts = t.Default.MaxAttempts
+		}
+		t.Queues[queue] = p
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (t *RetryTopology) validate() error {
+	if err := t.Default.validate(); err != nil {
+		return fmt.Errorf("retry default: %w", err)
+	}
+	for queue, p := range t.Queues {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("retry queue %s: %w", queue, err)
+		}
+	}
+	return nil
+}
+
+func (p RetryPolicy) validate() error {
+	if len(p.Tiers) == 0 {
+		return fmt.Errorf("at least one tier is required")
+	}
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("max_attempts must be >= 1")
+	}
+	seen := make(map[string]bool, len(p.Tiers))
+	for _, d := range p.Tiers {
+		if d < time.Millisecond {
+			return fmt.Errorf("tier %s is shorter than 1ms", d)
+		}
+		// Dois tiers com o mesmo rótulo seriam a mesma fila.
+		if seen[tierLabel(d)] {
+			return fmt.Errorf("duplicated tier %s", tierLabel(d))
+		}
+		seen[tierLabel(d)] = true
+	}
+	return nil
+}
+
+// PolicyFor devolve a política da fila, ou a padrão.
+func (t *RetryTopology) PolicyFor(queue string) RetryPolicy {
+	if t == nil {
+		return DefaultRetryPolicy
+	}
+	if p, ok := t.Queues[queue]; ok {
+		return p
+	}
+	return t.Default
+}
+
+// Tier escolhe a wait queue para a falha da tentativa `retries` (retries já feitos).
+func (p RetryPolicy) Tier(queue string, retries int) (string, time.Duration) {
+	i := min(retries, len(p.Tiers)-1)
+	return WaitQueueName(queue, p.Tiers[i]), p.Tiers[i]
+}
+
+// TierAtLeast é o Tier da tentativa, trocado pelo menor tier >= minDelay quando
+// o handler pediu uma espera maior (RetryAfter). Acima do maior tier, usa o maior.
+func (p RetryPolicy) TierAtLeast(queue string, retries int, minDelay time.Duration) (string, time.Duration) {
+	name, d := p.Tier(queue, retries)
+	if d >= minDelay {
+		return name, d
+	}
+	best := slices.Max(p.Tiers)
+	for _, t := range p.Tiers {
+		if t >= minDelay && t < best {
+			best = t
+		}
+	}
+	return WaitQueueName(queue, best), best
+}
+
+// Exhausted informa se a tentativa atual era a última permitida.
+func (p RetryPolicy) Exhausted(retries int) bool {
+	return retries+1 >= p.MaxAttempts
+}
+
+// WaitQueueName monta o nome do tier (ex: orders.created.wait.10s).
+func WaitQueueName(queue string, d time.Duration) string {
+	return queue + ".wait." + tierLabel(d)
+}
+
+// tierLabel: 500ms, 1s, 60s, 10m, 2h. Minutos/horas só acima de 1 unidade,
+// para que 60s continue 60s.
+func tierLabel(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0 && d > time.Hour:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0 && d > time.Minute:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	case d%time.Second == 0:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	default:
+		return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	}
+}
+
+// declareWaitQueues cria um tier por duração. Após o TTL, a mensagem volta para
+// a fila principal pelo MainEx com a routing key original.
+func declareWaitQueues(ch *amqp.Channel, queue string, p RetryPolicy) error {
+	for _, d := range p.Tiers {
+		args := amqp.Table{
+			"x-dead-letter-exchange":    MainEx,
+			"x-dead-letter-routing-key": queue,
+			"x-message-ttl":             d.Milliseconds(),
+		}
+		if _, err := ch.QueueDeclare(WaitQueueName(queue, d), true, false, false, false, args); err != nil {
+			return fmt.Errorf("failed to declare wait queue %s: %w", WaitQueueName(queue, d), err)
+		}
+	}
+	return nil
+}
+
+// retryCount lê o x-retry. Mensagens em voo de antes do header caem no x-death.
+func retryCount(h amqp.Table) int {
+	switch v := h[RetryHeader].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return legacyDeathCount(h)
+}
+
+func legacyDeathCount(h amqp.Table) int {
+	xDeath, ok := h["x-death"].([]interface{})
+	if !ok {
+		return 0
+	}
+	for _, death := range xDeath {
+		if table, ok := death.(amqp.Table); ok {
+			if count, ok := table["count"].(int64); ok {
+				return int(count)
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package event
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_TierByAttempt(t *testing.T) {
+	p := DefaultRetryPolicy
+
+	cases := []struct {
+		retries int
+		queue   string
+		delay   time.Duration
+	}{
+		{0, "orders.created.wait.1s", time.Second},
+		{1, "orders.created.wait.10s", 10 * time.Second},
+		{2, "orders.created.wait.60s", time.Minute},
+		{3, "orders.created.wait.10m", 10 * time.Minute},
+		{9, "orders.created.wait.10m", 10 * time.Minute},
+	}
+	for _, tc := range cases {
+		queue, delay := p.Tier("orders.created", tc.retries)
+		assert.Equal(t, tc.queue, queue)
+		assert.Equal(t, tc.delay, delay)
+	}
+
+	assert.False(t, p.Exhausted(3))
+	assert.True(t, p.Exhausted(4), "5ª tentativa é a última")
+}
+
+func TestRetryPolicy_TierAtLeast(t *testing.T) {
+	p := DefaultRetryPolicy
+
+	cases := []struct {
+		name     string
+		retries  int
+		minDelay time.Duration
+		queue    string
+	}{
+		{"no delay keeps attempt tier", 1, 0, "orders.created.wait.10s"},
+		{"shorter delay keeps attempt tier", 2, 5 * time.Second, "orders.created.wait.60s"},
+		{"rounds up to the next tier", 0, 30 * time.Second, "orders.created.wait.60s"},
+		{"exact tier", 0, 10 * time.Second, "orders.created.wait.10s"},
+		{"caps at the largest tier", 0, time.Hour, "orders.created.wait.10m"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			queue, delay := p.TierAtLeast("orders.created", tc.retries, tc.minDelay)
+			assert.Equal(t, tc.queue, queue)
+			assert.Equal(t, WaitQueueName("orders.created", delay), queue)
+		})
+	}
+}
+
+func TestTierLabel(t *testing.T) {
+	assert.Equal(t, "500ms", tierLabel(500*time.Millisecond))
+	assert.Equal(t, "90s", tierLabel(90*time.Second))
+	assert.Equal(t, "2m", tierLabel(2*time.Minute))
+	assert.Equal(t, "60m", tierLabel(time.Hour))
+	assert.Equal(t, "6h", tierLabel(6*time.Hour))
+}
+
+func TestRetryCount(t *testing.T) {
+	assert.Equal(t, 0, retryCount(nil))
+	assert.Equal(t, 2, retryCount(amqp.Table{RetryHeader: int32(2)}))
+	assert.Equal(t, 3, retryCount(amqp.Table{RetryHeader: int64(3)}))
+	assert.Equal(t, 4, retryCount(amqp.Table{RetryHeader: "4"}))
+
+	// x-retry vence o x-death; sem ele, mensagens legadas usam o x-death.
+	death := []interface{}{amqp.Table{"count": int64(7)}}
+	assert.Equal(t, 1, retryCount(amqp.Table{RetryHeader: int32(1), "x-death": death}))
+	assert.Equal(t, 7, retryCount(amqp.Table{"x-death": death}))
+}
+
+func TestLoadRetryTopology(t *testing.T) {
+	topo, err := LoadRetryTopology("../../../configs/retry.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, 5, topo.PolicyFor("orders.created").MaxAttempts)
+	assert.Equal(t, DefaultRetryPolicy, topo.PolicyFor("unknown.queue"))
+
+	path := filepath.Join(t.TempDir(), "retry.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("queues:\n  billing:\n    max_attempts: 2\n"), 0o600))
+	topo, err = LoadRetryTopology(path)
+	require.NoError(t, err)
+	assert.Equal(t, RetryPolicy{Tiers: DefaultRetryPolicy.Tiers, MaxAttempts: 2}, topo.PolicyFor("billing"))
+
+	require.NoError(t, os.WriteFile(path, []byte("default:\n  tiers: [1m, 60s]\n  max_attempts: 3\n"), 0o600))
+	_, err = LoadRetryTopology(path)
+	assert.Error(t, err, "1m e 60s seriam a mesma fila")
+}
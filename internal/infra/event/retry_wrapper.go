@@ -6,6 +6,7 @@ import (
 	"math/rand/v2"
 	"time"
 
+	"github.com/DioGolang/GoFleet/pkg/events"
 	"github.com/DioGolang/GoFleet/pkg/logger"
 	"github.com/DioGolang/GoFleet/pkg/metrics"
 )
@@ -18,17 +19,24 @@ func WrapExponentialBackoff(
 	baseWait time.Duration,
 	next MessageHandler,
 ) MessageHandler {
-	return func(ctx context.Context, msg []byte, headers map[string]interface{}) error {
+	const maxBackoff = 30 * time.Second
+
+	return func(ctx context.Context, evt events.CloudEvent) error {
 		var err error
 		for attempt := 0; attempt <= maxRetries; attempt++ {
-			err = next(ctx, msg, headers)
+			err = next(ctx, evt)
 			if err == nil {
 				return nil
 			}
 
-			if attempt < maxRetries {
-				const maxBackoff = 30 * time.Second
+			// Permanente não se resolve com retry; qualquer RetryAfter vai para as
+			// wait queues (TierAtLeast) em vez de prender o worker dormindo.
+			failure := Classify(err)
+			if failure.Permanent() || failure.Delay > 0 {
+				return err
+			}
 
+			if attempt < maxRetries {
 				floatWait := float64(baseWait) * math.Pow(2, float64(attempt))
 				calculatedWait := time.Duration(floatWait)
 
@@ -0,0 +1,155 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.yaml.in/yaml/v3"
+)
+
+// wildcard em aggregate_type/event_type casa qualquer valor.
+const wildcard = "*"
+
+var ErrNoRoute = errors.New("no route for event")
+
+type ExchangeSpec struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // direct | topic | fanout | headers
+}
+
+type ExchangeBinding struct {
+	Source      string `yaml:"source"`
+	Destination string `yaml:"destination"`
+	Pattern     string `yaml:"pattern"`
+}
+
+type Route struct {
+	AggregateType string `yaml:"aggregate_type"`
+	EventType     string `yaml:"event_type"`
+	Exchange      string `yaml:"exchange"`
+	RoutingKey    string `yaml:"routing_key"` // Vazio: usa a coluna topic do outbox
+	// AllowUnroutable desliga o mandatory: eventos de broadcast que podem não ter consumidor ainda.
+	AllowUnroutable bool `yaml:"allow_unroutable"`
+}
+
+// Destination é onde uma mensagem será publicada.
+type Destination struct {
+	Exchange   string
+	RoutingKey string
+	Mandatory  bool
+}
+
+// RoutingTable é carregada de configs/routing.yaml.
+type RoutingTable struct {
+	Exchanges        []ExchangeSpec    `yaml:"exchanges"`
+	ExchangeBindings []ExchangeBinding `yaml:"exchange_bindings"`
+	Routes           []Route           `yaml:"routes"`
+	// Encodings escolhe o formato do payload por topic (routing key final). Padrão: json.
+	Encodings map[string]string `yaml:"encodings"`
+}
+
+func LoadRoutingTable(path string) (*RoutingTable, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing config: %w", err)
+	}
+	var t RoutingTable
+	if err := yaml.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("invalid routing config: %w", err)
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (t *RoutingTable) validate() error {
+	declared := make(map[string]bool, len(t.Exchanges))
+	for _, ex := range t.Exchanges {
+		switch ex.Type {
+		case amqp.ExchangeDirect, amqp.ExchangeTopic, amqp.ExchangeFanout, amqp.ExchangeHeaders:
+		default:
+			return fmt.Errorf("exchange %q has invalid type %q", ex.Name, ex.Type)
+		}
+		declared[ex.Name] = true
+	}
+	for _, b := range t.ExchangeBindings {
+		if !declared[b.Source] || !declared[b.Destination] {
+			return fmt.Errorf("binding %s -> %s references an undeclared exchange", b.Source, b.Destination)
+		}
+	}
+	for _, r := range t.Routes {
+		if r.AggregateType == "" || r.EventType == "" {
+			return fmt.Errorf("route to %q must set aggregate_type and event_type", r.Exchange)
+		}
+		if !declared[r.Exchange] {
+			return fmt.Errorf("route %s/%s references undeclared exchange %q", r.AggregateType, r.EventType, r.Exchange)
+		}
+	}
+	for topic, enc := range t.Encodings {
+		if enc != EncodingJSON && enc != EncodingProtobuf {
+			return fmt.Errorf("topic %q has invalid encoding %q", topic, enc)
+		}
+	}
+	return nil
+}
+
+// EncodingFor devolve o encoding configurado para um topic.
+func (t *RoutingTable) EncodingFor(topic string) string {
+	if enc, ok := t.Encodings[topic]; ok {
+		return enc
+	}
+	return EncodingJSON
+}
+
+// Resolve escolhe a rota mais específica: evento exato > curinga de evento > curinga de agregado.
+// Sem routing_key na rota, o topic gravado no outbox vira a routing key.
+func (t *RoutingTable) Resolve(aggregateType, eventType, topic string) (Destination, error) {
+	route, ok := t.match(aggregateType, eventType)
+	if !ok {
+		return Destination{}, fmt.Errorf("%w: %s/%s", ErrNoRoute, aggregateType, eventType)
+	}
+
+	key := route.RoutingKey
+	if key == "" {
+		key = topic
+	}
+	if key == "" {
+		return Destination{}, fmt.Errorf("%w: %s/%s has no routing key", ErrNoRoute, aggregateType, eventType)
+	}
+	return Destination{Exchange: route.Exchange, RoutingKey: key, Mandatory: !route.AllowUnroutable}, nil
+}
+
+func (t *RoutingTable) match(aggregateType, eventType string) (Route, bool) {
+	candidates := [][2]string{
+		{aggregateType, eventType},
+		{aggregateType, wildcard},
+		{wildcard, eventType},
+		{wildcard, wildcard},
+	}
+	for _, c := range candidates {
+		for _, r := range t.Routes {
+			if r.AggregateType == c[0] && r.EventType == c[1] {
+				return r, true
+			}
+		}
+	}
+	return Route{}, false
+}
+
+// DeclareTopology declara exchanges e bindings. Idempotente: roda a cada startup.
+func (t *RoutingTable) DeclareTopology(ch *amqp.Channel) error {
+	for _, ex := range t.Exchanges {
+		if err := ch.ExchangeDeclare(ex.Name, ex.Type, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", ex.Name, err)
+		}
+	}
+	for _, b := range t.ExchangeBindings {
+		if err := ch.ExchangeBind(b.Destination, b.Pattern, b.Source, false, nil); err != nil {
+			return fmt.Errorf("failed to bind exchange %s -> %s: %w", b.Source, b.Destination, err)
+		}
+	}
+	return nil
+}
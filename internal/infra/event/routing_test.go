@@ -0,0 +1,63 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutingTable_Resolve(t *testing.T) {
+	table := &RoutingTable{
+		Exchanges: []ExchangeSpec{
+			{Name: "gofleet.events", Type: "topic"},
+			{Name: "billing", Type: "direct"},
+		},
+		Routes: []Route{
+			{AggregateType: "Order", EventType: "*", Exchange: "gofleet.events"},
+			{AggregateType: "Order", EventType: "OrderDelivered", Exchange: "billing", RoutingKey: "billing.delivered", AllowUnroutable: true},
+		},
+	}
+	require.NoError(t, table.validate())
+
+	dest, err := table.Resolve("Order", "OrderCreated", "orders.created")
+	require.NoError(t, err)
+	assert.Equal(t, Destination{Exchange: "gofleet.events", RoutingKey: "orders.created", Mandatory: true}, dest)
+
+	dest, err = table.Resolve("Order", "OrderDelivered", "orders.delivered")
+	require.NoError(t, err)
+	assert.Equal(t, Destination{Exchange: "billing", RoutingKey: "billing.delivered", Mandatory: false}, dest)
+
+	_, err = table.Resolve("Driver", "DriverMoved", "drivers.moved")
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	_, err = table.Resolve("Order", "OrderCreated", "")
+	assert.ErrorIs(t, err, ErrNoRoute)
+}
+
+func TestRoutingTable_Validate(t *testing.T) {
+	assert.Error(t, (&RoutingTable{Encodings: map[string]string{"orders.created": "avro"}}).validate())
+	assert.NoError(t, (&RoutingTable{Encodings: map[string]string{"orders.created": EncodingProtobuf}}).validate())
+	assert.Error(t, (&RoutingTable{Exchanges: []ExchangeSpec{{Name: "x", Type: "fanfare"}}}).validate())
+	assert.Error(t, (&RoutingTable{Routes: []Route{{AggregateType: "Order", EventType: "*", Exchange: "missing"}}}).validate())
+	assert.Error(t, (&RoutingTable{
+		Exchanges:        []ExchangeSpec{{Name: "a", Type: "topic"}},
+		ExchangeBindings: []ExchangeBinding{{Source: "a", Destination: "b", Pattern: "orders.*"}},
+	}).validate())
+}
+
+func TestLoadRoutingTable_RepoConfig(t *testing.T) {
+	table, err := LoadRoutingTable("../../../configs/routing.yaml")
+	require.NoError(t, err)
+
+	dest, err := table.Resolve("Order", "OrderCancelled", "orders.cancelled")
+	require.NoError(t, err)
+	assert.Equal(t, "gofleet.events", dest.Exchange)
+	assert.Equal(t, "orders.cancelled", dest.RoutingKey)
+	assert.False(t, dest.Mandatory, "OrderCancelled has no consumer yet")
+
+	// O Worker depende de orders.created: sem fila, o relay precisa saber.
+	dest, err = table.Resolve("Order", "OrderCreated", "orders.created")
+	require.NoError(t, err)
+	assert.True(t, dest.Mandatory)
+}
@@ -1,5 +1,10 @@
 package event
 
-import "context"
+import (
+	"context"
 
-type MessageHandler func(ctx context.Context, msg []byte, headers map[string]interface{}) error
+	"github.com/DioGolang/GoFleet/pkg/events"
+)
+
+// MessageHandler recebe o envelope CloudEvents já validado; o payload está em evt.Data.
+type MessageHandler func(ctx context.Context, evt events.CloudEvent) error
@@ -0,0 +1,54 @@
+package event
+
+import (
+	"context"
+	"maps"
+	"strconv"
+
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// WrapUpcasting valida o payload contra o schema da sua versão e o migra para a
+// versão atual antes do handler. Versão futura ou payload fora do contrato não
+// melhoram com retry: o erro sobe e o Consumer manda a mensagem ao Parking.
+func WrapUpcasting(
+	log logger.Logger,
+	registry *events.SchemaRegistry,
+	next MessageHandler,
+) MessageHandler {
+	return func(ctx context.Context, evt events.CloudEvent) error {
+		version, err := evt.DataVersion()
+		if err != nil {
+			return err
+		}
+
+		data, current, err := registry.Upcast(evt.EventType(), version, evt.Data)
+		if err != nil {
+			log.Warn(ctx, "Event rejected by schema registry",
+				logger.String("event_id", evt.ID),
+				logger.String("event_type", evt.Type),
+				logger.Int("version", int(version)),
+				logger.WithError(err),
+			)
+			return err
+		}
+
+		if current != version {
+			log.Debug(ctx, "Event upcasted",
+				logger.String("event_id", evt.ID),
+				logger.Int("from", int(version)),
+				logger.Int("to", int(current)),
+			)
+			evt.Data = data
+			// Cópia: o mapa original pertence à delivery.
+			evt.Extensions = maps.Clone(evt.Extensions)
+			if evt.Extensions == nil {
+				evt.Extensions = make(map[string]string, 1)
+			}
+			evt.Extensions[events.ExtDataVersion] = strconv.FormatInt(int64(current), 10)
+		}
+
+		return next(ctx, evt)
+	}
+}
@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	domainevent "github.com/DioGolang/GoFleet/internal/domain/event"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUpcastRegistry(t *testing.T) *events.SchemaRegistry {
+	t.Helper()
+	reg := events.NewSchemaRegistry()
+	require.NoError(t, reg.Register("Pinged", 1, []byte(`{"type":"object","required":["at"]}`)))
+	require.NoError(t, reg.Register("Pinged", 2, []byte(`{"type":"object","required":["at","source"]}`)))
+	reg.RegisterUpcaster("Pinged", 1, func(p []byte) ([]byte, error) {
+		return []byte(`{"at":1,"source":"legacy"}`), nil
+	})
+	return reg
+}
+
+func TestWrapUpcasting(t *testing.T) {
+	reg := newUpcastRegistry(t)
+	ext := map[string]string{events.ExtDataVersion: "1"}
+
+	var got events.CloudEvent
+	h := WrapUpcasting(logger.NewZapLogger("test", false), reg, func(_ context.Context, evt events.CloudEvent) error {
+		got = evt
+		return nil
+	})
+
+	require.NoError(t, h(context.Background(), events.CloudEvent{
+		ID: "1", Type: "io.gofleet.Pinged", Data: []byte(`{"at":1}`), Extensions: ext,
+	}))
+	assert.JSONEq(t, `{"at":1,"source":"legacy"}`, string(got.Data))
+	assert.Equal(t, "2", got.Extension(events.ExtDataVersion))
+	assert.Equal(t, "1", ext[events.ExtDataVersion], "extensões da delivery não podem ser alteradas")
+
+	err := h(context.Background(), events.CloudEvent{
+		ID: "2", Type: "io.gofleet.Pinged", Data: []byte(`{}`), Extensions: map[string]string{events.ExtDataVersion: "3"},
+	})
+	reason, ok := parkingReason(err)
+	assert.True(t, ok)
+	assert.Equal(t, "unknown-event-version", reason)
+}
+
+func TestParkingReason(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		reason string
+		park   bool
+	}{
+		"schema":    {err: events.ErrSchemaViolation, reason: "schema-violation", park: true},
+		"upcaster":  {err: events.ErrMissingUpcaster, reason: "schema-violation", park: true},
+		"type":      {err: events.ErrUnknownEventType, reason: "unknown-event-type", park: true},
+		"envelope":  {err: events.ErrInvalidCloudEvent, reason: "invalid-cloudevent", park: true},
+		"transient": {err: errors.New("grpc search driver failed"), park: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reason, ok := parkingReason(tc.err)
+			assert.Equal(t, tc.park, ok)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestWrapUpcasting_BaselineRelayMessage(t *testing.T) {
+	reg, err := domainevent.NewSchemaRegistry()
+	require.NoError(t, err)
+
+	var got events.CloudEvent
+	h := WrapUpcasting(logger.NewZapLogger("test", false), reg, func(_ context.Context, evt events.CloudEvent) error {
+		got = evt
+		return nil
+	})
+
+	// Mensagem em voo durante o deploy: precisa ser processada, não estacionada.
+	evt, err := ParseDelivery(baselineDelivery("orders.created", []byte(`{"id":"order-1","final_price":110}`)))
+	require.NoError(t, err)
+	require.NoError(t, h(context.Background(), evt))
+	assert.Equal(t, "OrderCreated", got.EventType())
+	assert.Equal(t, strconv.Itoa(int(domainevent.OrderCreatedVersion)), got.Extension(events.ExtDataVersion))
+}
@@ -0,0 +1,333 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// workerPool executa uma assinatura em um canal. Cada consumer AMQP do canal
+// tem um feeder que repassa as entregas para `work`, de onde os workers leem.
+// Trocar o prefetch exige um consumer novo (o basic.qos só vale para consumers
+// criados depois dele): o novo entra, o antigo é cancelado e o feeder dele
+// termina de repassar o que já foi entregue.
+//
+// No shutdown, drain cancela o consumer e espera as mensagens em voo antes de o
+// canal fechar, para que ainda possam receber Ack/Nack.
+type workerPool struct {
+	c   *Consumer
+	sub *subscription
+	ch  *amqp.Channel
+	// ctx dos handlers: não acompanha o shutdown, só o abort do drain.
+	ctx context.Context
+
+	work     chan amqp.Delivery
+	retire   chan struct{}
+	feeders  sync.WaitGroup
+	workers  sync.WaitGroup
+	latency  latencyWindow
+	draining atomic.Bool
+	stopOnce sync.Once
+	stopped  chan struct{}
+
+	flightMu sync.Mutex
+	inflight map[uint64]inflightMsg
+
+	mu       sync.Mutex
+	size     int
+	nextID   int
+	prefetch int
+	tag      string
+	seq      int
+}
+
+// inflightMsg é uma mensagem em processamento, reportada se passar do prazo do drain.
+type inflightMsg struct {
+	messageID string
+	workerID  int
+	started   time.Time
+}
+
+func newWorkerPool(ctx context.Context, c *Consumer, sub *subscription, ch *amqp.Channel) *workerPool {
+	maxWorkers := sub.Workers
+	if sub.Scaling != nil {
+		maxWorkers = sub.Scaling.Max
+	}
+	return &workerPool{
+		c:        c,
+		sub:      sub,
+		ch:       ch,
+		ctx:      ctx,
+		work:     make(chan amqp.Delivery),
+		retire:   make(chan struct{}, maxWorkers),
+		stopped:  make(chan struct{}),
+		inflight: make(map[uint64]inflightMsg),
+	}
+}
+
+// start abre o primeiro consumer e sobe os workers iniciais.
+func (p *workerPool) start() error {
+	if err := p.consume(p.sub.Prefetch); err != nil {
+		return err
+	}
+	p.resize(p.sub.Workers)
+	return nil
+}
+
+// consume troca o consumer do canal por um novo com o prefetch pedido.
+func (p *workerPool) consume(prefetch int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ch.Qos(prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set qos: %w", err)
+	}
+	p.seq++
+	tag := fmt.Sprintf("%s#%d", p.sub.Queue, p.seq)
+	deliveries, err := p.ch.Consume(p.sub.Queue, tag, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	p.feeders.Add(1)
+	go p.feed(deliveries)
+
+	old := p.tag
+	p.tag, p.prefetch = tag, prefetch
+	if old != "" {
+		// As entregas já feitas ao consumer antigo continuam no canal e aceitam Ack.
+		if err := p.ch.Cancel(old, false); err != nil {
+			return fmt.Errorf("failed to cancel consumer %s: %w", old, err)
+		}
+	}
+	if p.c.Metrics != nil {
+		p.c.Metrics.SetConsumerPrefetch(p.sub.Queue, prefetch)
+	}
+	return nil
+}
+
+func (p *workerPool) feed(deliveries <-chan amqp.Delivery) {
+	defer p.feeders.Done()
+	for d := range deliveries {
+		p.work <- d
+	}
+}
+
+// resize ajusta o número de workers. Quem sai termina a mensagem atual antes.
+func (p *workerPool) resize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for ; p.size < n; p.size++ {
+		// Um pedido de saída ainda não atendido vale como worker novo.
+		select {
+		case <-p.retire:
+			continue
+		default:
+		}
+		p.workers.Add(1)
+		go p.worker(p.ctx, p.nextID)
+		p.nextID++
+	}
+	for ; p.size > n; p.size-- {
+		p.retire <- struct{}{}
+	}
+
+	if p.c.Metrics != nil {
+		p.c.Metrics.SetConsumerWorkers(p.sub.Queue, n)
+	}
+}
+
+func (p *workerPool) current() (workers, prefetch int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size, p.prefetch
+}
+
+func (p *workerPool) worker(ctx context.Context, workerID int) {
+	defer p.workers.Done()
+
+	p.c.Logger.Debug(ctx, "Worker started", logger.String("queue", p.sub.Queue), logger.Int("worker_id", workerID))
+	defer p.c.Logger.Debug(ctx, "Worker stopped", logger.String("queue", p.sub.Queue), logger.Int("worker_id", workerID))
+
+	for {
+		select {
+		case <-p.retire:
+			return
+		case d, ok := <-p.work:
+			if !ok {
+				return
+			}
+			if p.draining.Load() {
+				// Entregue antes do cancel, mas ainda não iniciada: volta para a fila.
+				_ = d.Nack(false, true)
+				continue
+			}
+			start := time.Now()
+			p.track(d, workerID, start)
+			p.handle(ctx, workerID, d)
+			p.untrack(d)
+			p.latency.observe(time.Since(start))
+		}
+	}
+}
+
+func (p *workerPool) handle(ctx context.Context, workerID int, d amqp.Delivery) {
+	// Safety: Recuperação de Panic para não derrubar a aplicação inteira
+	// se um worker encontrar um bug bizarro. O worker segue vivo: sem ele, os
+	// feeders travariam com o pool vazio.
+	defer func() {
+		if r := recover(); r != nil {
+			p.c.Logger.Error(ctx, "Worker panicked!",
+				logger.String("queue", p.sub.Queue),
+				logger.Int("worker_id", workerID),
+				logger.Any("panic", r),
+			)
+		}
+	}()
+	p.c.handleMessage(ctx, d, p.sub, p.ch)
+}
+
+// autoscale reavalia o pool a cada Scaling.Interval até o ctx acabar.
+func (p *workerPool) autoscale(ctx context.Context) {
+	cfg := *p.sub.Scaling
+	s := &scaler{cfg: cfg}
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			q, err := p.ch.QueueDeclarePassive(p.sub.Queue, true, false, false, false, nil)
+			if err != nil {
+				// Canal caiu: o consume vê o NotifyClose e refaz a assinatura.
+				p.c.Logger.Warn(ctx, "Autoscaler could not read queue depth", logger.String("queue", p.sub.Queue), logger.WithError(err))
+				return
+			}
+			processed, busy := p.latency.reset()
+			workers, prefetch := p.current()
+			target, reason := s.next(scaleSample{
+				Workers:   workers,
+				Depth:     q.Messages,
+				Processed: processed,
+				Busy:      busy,
+				Elapsed:   now.Sub(last),
+				Held:      cfg.Hold != nil && cfg.Hold(),
+			})
+			last = now
+
+			if reason == scaleHeld || reason == scaleLatencySpike {
+				p.c.Logger.Debug(ctx, "Autoscaler frozen", logger.String("queue", p.sub.Queue), logger.String("reason", reason))
+			}
+			if target == workers {
+				continue
+			}
+
+			p.c.Logger.Info(ctx, "Autoscaling worker pool",
+				logger.String("queue", p.sub.Queue),
+				logger.String("reason", reason),
+				logger.Int("from", workers),
+				logger.Int("to", target),
+				logger.Int("queue_depth", q.Messages),
+			)
+			if next := target * cfg.PrefetchPerWorker; next != prefetch {
+				if err := p.consume(next); err != nil {
+					p.c.Logger.Warn(ctx, "Autoscaler failed to adjust prefetch", logger.String("queue", p.sub.Queue), logger.WithError(err))
+					return
+				}
+			}
+			p.resize(target)
+		}
+	}
+}
+
+func (p *workerPool) track(d amqp.Delivery, workerID int, start time.Time) {
+	p.flightMu.Lock()
+	p.inflight[d.DeliveryTag] = inflightMsg{messageID: d.MessageId, workerID: workerID, started: start}
+	p.flightMu.Unlock()
+}
+
+func (p *workerPool) untrack(d amqp.Delivery) {
+	p.flightMu.Lock()
+	delete(p.inflight, d.DeliveryTag)
+	p.flightMu.Unlock()
+}
+
+// drain encerra o consumo sem perder Ack: cancela o consumer (o broker para de
+// entregar), devolve à fila o que foi entregue e não começou, e espera as
+// mensagens em voo até o timeout. As que passam do prazo são reportadas e têm
+// o ctx cancelado por abort; o handleMessage as devolve com Nack(requeue) e a
+// idempotência libera o lock. Devolve quantas passaram do prazo.
+func (p *workerPool) drain(ctx context.Context, timeout time.Duration, abort context.CancelFunc) int {
+	p.draining.Store(true)
+
+	p.mu.Lock()
+	tag := p.tag
+	p.mu.Unlock()
+	if err := p.ch.Cancel(tag, false); err != nil {
+		p.c.Logger.Warn(ctx, "Failed to cancel consumer, closing channel instead",
+			logger.String("queue", p.sub.Queue),
+			logger.WithError(err),
+		)
+		return 0
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-p.wait():
+		return 0
+	case <-timer.C:
+	}
+
+	late := p.reportLate(ctx)
+	abort()
+
+	grace := time.NewTimer(drainAbortGrace)
+	defer grace.Stop()
+	select {
+	case <-p.wait():
+	case <-grace.C:
+	}
+	return late
+}
+
+func (p *workerPool) reportLate(ctx context.Context) int {
+	p.flightMu.Lock()
+	defer p.flightMu.Unlock()
+
+	for tag, m := range p.inflight {
+		p.c.Logger.Warn(ctx, "Message still in flight at drain deadline",
+			logger.String("queue", p.sub.Queue),
+			logger.String("msg_id", m.messageID),
+			logger.Int("delivery_tag", int(tag)),
+			logger.Int("worker_id", m.workerID),
+			logger.String("elapsed", time.Since(m.started).String()),
+		)
+		if p.c.Metrics != nil {
+			p.c.Metrics.IncConsumerDrainLate(p.sub.Queue)
+		}
+	}
+	return len(p.inflight)
+}
+
+// wait é fechado quando todos os workers saíram: os consumers fecham as
+// entregas (cancel ou canal fechado), os feeders terminam e `work` é fechado.
+func (p *workerPool) wait() <-chan struct{} {
+	p.stopOnce.Do(func() {
+		go func() {
+			p.feeders.Wait()
+			close(p.work)
+			p.workers.Wait()
+			close(p.stopped)
+		}()
+	})
+	return p.stopped
+}
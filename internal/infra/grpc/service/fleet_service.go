@@ -2,13 +2,20 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
 	"github.com/DioGolang/GoFleet/internal/infra/grpc/pb"
 	"github.com/DioGolang/GoFleet/pkg/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// noDriverRetryDelay é a espera sugerida ao cliente quando não há motorista na área.
+const noDriverRetryDelay = 30 * time.Second
+
 type FleetService struct {
 	pb.UnimplementedFleetServiceServer
 	Repo   outbound.LocationRepository
@@ -24,6 +31,9 @@ func NewFleetService(repo outbound.LocationRepository, log logger.Logger) *Fleet
 
 func (s *FleetService) SearchDriver(ctx context.Context, req *pb.SearchDriverRequest) (*pb.SearchDriverResponse, error) {
 	s.Logger.Debug(ctx, "Searching nearest driver", logger.String("order_id", req.OrderId))
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
 
 	// Simulação: Coordenadas do pedido
 	orderLat, orderLng := -23.5505, -46.6333
@@ -31,7 +41,7 @@ func (s *FleetService) SearchDriver(ctx context.Context, req *pb.SearchDriverReq
 	drivers, err := s.Repo.GetNearestDrivers(ctx, orderLat, orderLng, 5.0)
 	if err != nil {
 		s.Logger.Error(ctx, "Failed to query location repository", logger.WithError(err))
-		return nil, err
+		return nil, status.Errorf(codes.Unavailable, "location repository: %v", err)
 	}
 
 	if len(drivers) == 0 {
@@ -40,7 +50,7 @@ func (s *FleetService) SearchDriver(ctx context.Context, req *pb.SearchDriverReq
 			logger.Float64("lat", orderLat),
 			logger.Float64("lng", orderLng),
 		)
-		return nil, fmt.Errorf("no drivers found within 5km radius")
+		return nil, noDriversError()
 	}
 
 	driver := drivers[0]
@@ -58,6 +68,16 @@ func (s *FleetService) SearchDriver(ctx context.Context, req *pb.SearchDriverReq
 	}, nil
 }
 
+// noDriversError: falta momentânea de motoristas, com RetryInfo para o Worker
+// reagendar a mensagem em vez de martelar o serviço.
+func noDriversError() error {
+	st := status.New(codes.ResourceExhausted, "no drivers found within 5km radius")
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(noDriverRetryDelay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
 func (s *FleetService) UpdateDriverPosition(ctx context.Context, driverID string, lat, lng float64) error {
 	s.Logger.Debug(ctx, "Updating driver position", logger.String("driver_id", driverID))
 
@@ -0,0 +1,215 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// State é o estado de uma chave no store.
+type State string
+
+const (
+	// StateProcessing: um dono está executando, com lease curto renovado enquanto roda.
+	StateProcessing State = "PROCESSING"
+	// StateCompleted: o efeito já aconteceu; a chave fica pelo TTL longo com o resultado.
+	StateCompleted State = "COMPLETED"
+)
+
+var (
+	// ErrInProgress: outra execução tem o lease da chave e ele ainda não expirou.
+	ErrInProgress = errors.New("idempotency key is being processed")
+	// ErrLeaseLost: o lease expirou e a chave foi retomada por outro dono.
+	ErrLeaseLost = errors.New("idempotency lease lost")
+)
+
+const (
+	defaultLease        = 30 * time.Second
+	defaultCompletedTTL = 24 * time.Hour
+	// releaseTimeout limita o Release/Complete, que rodam mesmo com o ctx do handler cancelado.
+	releaseTimeout = 2 * time.Second
+)
+
+// Record é o conteúdo de uma chave.
+type Record struct {
+	State  State
+	Owner  string
+	Result []byte
+}
+
+// Store guarda as chaves. As operações que alteram uma chave exigem o owner de
+// quem a adquiriu: um dono cujo lease expirou não apaga nem conclui a chave do
+// dono seguinte.
+type Store interface {
+	// Acquire cria a chave em PROCESSING, ou a retoma se a chave anterior
+	// expirou (lease vencido de um worker que caiu). Devolve o registro vigente:
+	// se Owner for outro, a chave não foi adquirida.
+	Acquire(ctx context.Context, key, owner string, lease time.Duration) (Record, error)
+	// Renew estende o lease. false: a chave não é mais deste dono.
+	Renew(ctx context.Context, key, owner string, lease time.Duration) (bool, error)
+	// Complete marca a chave como COMPLETED com o resultado. ErrLeaseLost se a
+	// chave não é mais deste dono.
+	Complete(ctx context.Context, key, owner string, result []byte, ttl time.Duration) error
+	// Release apaga uma chave em PROCESSING deste dono, para o retry executar de novo.
+	Release(ctx context.Context, key, owner string) error
+}
+
+// Claim identifica a execução dona de uma chave. Com WithTxCompletion, o Guard
+// o coloca no ctx do handler para que o efeito conclua a chave na própria transação.
+type Claim struct {
+	Key   string
+	Owner string
+	TTL   time.Duration
+}
+
+type claimKey struct{}
+
+func WithClaim(ctx context.Context, c Claim) context.Context {
+	return context.WithValue(ctx, claimKey{}, c)
+}
+
+// ClaimFrom devolve o Claim da execução atual, se houver.
+func ClaimFrom(ctx context.Context) (Claim, bool) {
+	c, ok := ctx.Value(claimKey{}).(Claim)
+	return c, ok
+}
+
+// Outcome é o resultado de Guard.Do.
+type Outcome struct {
+	Result []byte
+	// Replayed: a chave já estava COMPLETED e fn não rodou.
+	Replayed bool
+	// CompleteErr: fn deu certo, mas marcar COMPLETED falhou. O efeito já
+	// aconteceu, então não é erro de Do; a chave volta a valer quando o lease vencer.
+	CompleteErr error
+}
+
+type Option func(*Guard)
+
+// WithLease define o lease de PROCESSING. É renovado a cada terço enquanto fn roda. Padrão: 30s.
+func WithLease(d time.Duration) Option {
+	return func(g *Guard) {
+		if d > 0 {
+			g.lease = d
+		}
+	}
+}
+
+// WithCompletedTTL define por quanto tempo uma chave COMPLETED deduplica. Padrão: 24h.
+func WithCompletedTTL(d time.Duration) Option {
+	return func(g *Guard) {
+		if d > 0 {
+			g.ttl = d
+		}
+	}
+}
+
+// WithTxCompletion: o store guarda a chave no mesmo banco do efeito, que a
+// conclui na própria transação (ver database.IdempotencyRepositoryImpl). Sem
+// isto o Claim não vai no ctx e aquela conclusão não faz nada.
+func WithTxCompletion() Option {
+	return func(g *Guard) {
+		g.txCompletion = true
+	}
+}
+
+// Guard executa fn no máximo uma vez por chave enquanto ela estiver no store:
+//
+//	(sem chave) --Acquire--> PROCESSING --fn ok--> COMPLETED --TTL--> (sem chave)
+//	                              |--fn erro--> Release (sem chave)
+//	                              |--lease vence--> retomada pelo próximo Acquire
+type Guard struct {
+	store    Store
+	lease    time.Duration
+	ttl      time.Duration
+	newOwner func() string
+
+	txCompletion bool
+}
+
+func NewGuard(store Store, opts ...Option) *Guard {
+	g := &Guard{
+		store:    store,
+		lease:    defaultLease,
+		ttl:      defaultCompletedTTL,
+		newOwner: uuid.NewString,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Lease devolve o lease de PROCESSING configurado.
+func (g *Guard) Lease() time.Duration {
+	return g.lease
+}
+
+// Do executa fn sob a chave. Chave COMPLETED: devolve o resultado guardado sem
+// rodar fn. Chave em PROCESSING de outro dono: ErrInProgress. Se o lease for
+// perdido durante fn, o ctx de fn é cancelado com causa ErrLeaseLost.
+func (g *Guard) Do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) (Outcome, error) {
+	owner := g.newOwner()
+	rec, err := g.store.Acquire(ctx, key, owner, g.lease)
+	if err != nil {
+		return Outcome{}, fmt.Errorf("idempotency store unavailable: %w", err)
+	}
+	if rec.Owner != owner {
+		if rec.State == StateCompleted {
+			return Outcome{Result: rec.Result, Replayed: true}, nil
+		}
+		return Outcome{}, ErrInProgress
+	}
+
+	fnCtx := ctx
+	if g.txCompletion {
+		fnCtx = WithClaim(ctx, Claim{Key: key, Owner: owner, TTL: g.ttl})
+	}
+	runCtx, cancel := context.WithCancelCause(fnCtx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		g.renew(runCtx, key, owner, cancel)
+	}()
+
+	result, err := fn(runCtx)
+	cancel(nil)
+	<-renewed
+
+	// Release e Complete rodam mesmo com o handler abortado (drain), senão a
+	// chave fica presa até o lease vencer.
+	doneCtx, doneCancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer doneCancel()
+
+	if err != nil {
+		if relErr := g.store.Release(doneCtx, key, owner); relErr != nil {
+			return Outcome{}, errors.Join(err, fmt.Errorf("failed to release idempotency key: %w", relErr))
+		}
+		return Outcome{}, err
+	}
+
+	return Outcome{Result: result, CompleteErr: g.store.Complete(doneCtx, key, owner, result, g.ttl)}, nil
+}
+
+// renew estende o lease a cada terço dele até fn terminar. Falha do store não
+// derruba fn (o lease ainda vale por 2/3); perder a chave, sim.
+func (g *Guard) renew(ctx context.Context, key, owner string, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(g.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := g.store.Renew(ctx, key, owner, g.lease)
+			if err == nil && !ok {
+				cancel(ErrLeaseLost)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore é um Store em memória com relógio real, para exercitar lease e TTL.
+type memStore struct {
+	mu     sync.Mutex
+	keys   map[string]memEntry
+	renews int
+}
+
+type memEntry struct {
+	rec     Record
+	expires time.Time
+}
+
+func newMemStore() *memStore {
+	return &memStore{keys: map[string]memEntry{}}
+}
+
+func (s *memStore) Acquire(_ context.Context, key, owner string, lease time.Duration) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.keys[key]; ok && time.Now().Before(e.expires) {
+		return e.rec, nil
+	}
+	rec := Record{State: StateProcessing, Owner: owner}
+	s.keys[key] = memEntry{rec: rec, expires: time.Now().Add(lease)}
+	return rec, nil
+}
+
+func (s *memStore) Renew(_ context.Context, key, owner string, lease time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.keys[key]
+	if !ok || e.rec.Owner != owner || !time.Now().Before(e.expires) {
+		return false, nil
+	}
+	s.renews++
+	e.expires = time.Now().Add(lease)
+	s.keys[key] = e
+	return true, nil
+}
+
+func (s *memStore) Complete(ctx context.Context, key, owner string, result []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.keys[key]; !ok || e.rec.Owner != owner {
+		return ErrLeaseLost
+	}
+	s.keys[key] = memEntry{rec: Record{State: StateCompleted, Owner: owner, Result: result}, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memStore) Release(ctx context.Context, key, owner string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.keys[key]; ok && e.rec.Owner == owner && e.rec.State == StateProcessing {
+		delete(s.keys, key)
+	}
+	return nil
+}
+
+// steal simula outra réplica retomando a chave depois do lease vencer.
+func (s *memStore) steal(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = memEntry{rec: Record{State: StateProcessing, Owner: "other"}, expires: time.Now().Add(time.Hour)}
+}
+
+func (s *memStore) state(key string) (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.keys[key]
+	return e.rec.State, ok
+}
+
+func TestGuard_CompletesAndReplaysResult(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store)
+
+	calls := 0
+	fn := func(context.Context) ([]byte, error) {
+		calls++
+		return []byte(`{"driver_id":"d-1"}`), nil
+	}
+
+	first, err := g.Do(context.Background(), "orders:evt-1", fn)
+	require.NoError(t, err)
+	assert.False(t, first.Replayed)
+	assert.NoError(t, first.CompleteErr)
+	state, _ := store.state("orders:evt-1")
+	assert.Equal(t, StateCompleted, state)
+
+	second, err := g.Do(context.Background(), "orders:evt-1", fn)
+	require.NoError(t, err)
+	assert.True(t, second.Replayed)
+	assert.Equal(t, `{"driver_id":"d-1"}`, string(second.Result))
+	assert.Equal(t, 1, calls)
+}
+
+func TestGuard_ReleasesKeyOnFailure(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store)
+	boom := errors.New("boom")
+
+	_, err := g.Do(context.Background(), "orders:evt-1", func(context.Context) ([]byte, error) { return nil, boom })
+	assert.ErrorIs(t, err, boom)
+	_, exists := store.state("orders:evt-1")
+	assert.False(t, exists, "retry must run the handler again")
+
+	_, err = g.Do(context.Background(), "orders:evt-1", func(context.Context) ([]byte, error) { return nil, nil })
+	assert.NoError(t, err)
+}
+
+func TestGuard_ReleasesKeyWhenHandlerIsAborted(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store)
+
+	ctx, abort := context.WithCancel(context.Background())
+	go abort()
+	_, err := g.Do(ctx, "orders:evt-1", func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, exists := store.state("orders:evt-1")
+	assert.False(t, exists)
+}
+
+func TestGuard_LiveLeaseIsInProgress(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store)
+	_, err := store.Acquire(context.Background(), "orders:evt-1", "other", time.Minute)
+	require.NoError(t, err)
+
+	_, err = g.Do(context.Background(), "orders:evt-1", func(context.Context) ([]byte, error) {
+		t.Fatal("handler must not run while another owner holds the lease")
+		return nil, nil
+	})
+	assert.ErrorIs(t, err, ErrInProgress)
+}
+
+func TestGuard_ReclaimsExpiredLease(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store)
+	// Worker que caiu no meio: a chave ficou em PROCESSING sem ninguém renovando.
+	_, err := store.Acquire(context.Background(), "orders:evt-1", "crashed", 10*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	ran := false
+	_, err = g.Do(context.Background(), "orders:evt-1", func(context.Context) ([]byte, error) {
+		ran = true
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, ran)
+}
+
+func TestGuard_RenewsLeaseWhileRunning(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store, WithLease(30*time.Millisecond))
+
+	_, err := g.Do(context.Background(), "orders:evt-1", func(ctx context.Context) ([]byte, error) {
+		time.Sleep(100 * time.Millisecond)
+		// Passou de 3 leases: sem renovação, outra réplica já teria retomado a chave.
+		rec, err := store.Acquire(ctx, "orders:evt-1", "other", time.Minute)
+		require.NoError(t, err)
+		assert.NotEqual(t, "other", rec.Owner)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Positive(t, store.renews)
+}
+
+func TestGuard_LostLeaseCancelsHandler(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store, WithLease(30*time.Millisecond))
+
+	_, err := g.Do(context.Background(), "orders:evt-1", func(ctx context.Context) ([]byte, error) {
+		store.steal("orders:evt-1")
+		select {
+		case <-ctx.Done():
+			assert.ErrorIs(t, context.Cause(ctx), ErrLeaseLost)
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return nil, errors.New("handler was not cancelled")
+		}
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// A chave do novo dono continua intacta.
+	state, exists := store.state("orders:evt-1")
+	assert.True(t, exists)
+	assert.Equal(t, StateProcessing, state)
+}
+
+func TestGuard_PutsClaimInContext(t *testing.T) {
+	g := NewGuard(newMemStore(), WithCompletedTTL(time.Hour), WithTxCompletion())
+
+	_, err := g.Do(context.Background(), "orders:evt-1", func(ctx context.Context) ([]byte, error) {
+		claim, ok := ClaimFrom(ctx)
+		require.True(t, ok)
+		assert.Equal(t, "orders:evt-1", claim.Key)
+		assert.NotEmpty(t, claim.Owner)
+		assert.Equal(t, time.Hour, claim.TTL)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	_, ok := ClaimFrom(context.Background())
+	assert.False(t, ok)
+
+	// Sem WithTxCompletion (ex: store no Redis) o efeito não tem o que concluir.
+	_, err = NewGuard(newMemStore()).Do(context.Background(), "orders:evt-2", func(ctx context.Context) ([]byte, error) {
+		_, ok := ClaimFrom(ctx)
+		assert.False(t, ok)
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+func TestGuard_ConcurrentDeliveriesRunOnce(t *testing.T) {
+	store := newMemStore()
+	g := NewGuard(store)
+
+	var mu sync.Mutex
+	calls := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = g.Do(context.Background(), "orders:evt-1", func(context.Context) ([]byte, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				return nil, nil
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, calls)
+}
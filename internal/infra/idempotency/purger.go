@@ -0,0 +1,34 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// Purger apaga chaves vencidas em stores que não expiram sozinhos (Postgres).
+type Purger interface {
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// RunPurger chama PurgeExpired a cada interval até o ctx acabar. O DELETE é
+// idempotente, então várias réplicas podem rodar ao mesmo tempo.
+func RunPurger(ctx context.Context, p Purger, interval time.Duration, log logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := p.PurgeExpired(ctx)
+			if err != nil {
+				log.Error(ctx, "Failed to purge expired idempotency keys", logger.WithError(err))
+			} else if n > 0 {
+				log.Debug(ctx, "Purged expired idempotency keys", logger.Int("count", int(n)))
+			}
+		}
+	}
+}
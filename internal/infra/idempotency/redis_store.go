@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "idem:"
+
+// Cada chave é um hash {state, owner, result} com PEXPIRE: o lease em
+// PROCESSING, o TTL longo em COMPLETED. Chave expirada some e o próximo
+// Acquire a cria de novo, o que já é a retomada do lease vencido.
+var (
+	acquireScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	redis.call('HSET', KEYS[1], 'state', 'PROCESSING', 'owner', ARGV[1])
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return redis.call('HMGET', KEYS[1], 'state', 'owner', 'result')
+`)
+
+	// Já concluída: continua do dono, sem trocar o TTL longo pelo lease.
+	renewScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return 0
+end
+if redis.call('HGET', KEYS[1], 'state') == 'PROCESSING' then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+	completeScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'state', 'COMPLETED', 'result', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+	releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] and redis.call('HGET', KEYS[1], 'state') == 'PROCESSING' then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+// RedisStore guarda as chaves no Redis. Rápido, mas fora da transação do
+// efeito: entre o commit no Postgres e o Complete, uma queda ainda permite
+// reprocessar depois do lease. Para exatamente-uma-vez, use o store do Postgres.
+type RedisStore struct {
+	client redis.Scripter
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Acquire(ctx context.Context, key, owner string, lease time.Duration) (Record, error) {
+	vals, err := acquireScript.Run(ctx, s.client, []string{redisKeyPrefix + key}, owner, lease.Milliseconds()).Slice()
+	if err != nil {
+		return Record{}, err
+	}
+	if len(vals) != 3 {
+		return Record{}, errors.New("unexpected idempotency acquire reply")
+	}
+	rec := Record{}
+	if v, ok := vals[0].(string); ok {
+		rec.State = State(v)
+	}
+	if v, ok := vals[1].(string); ok {
+		rec.Owner = v
+	}
+	if v, ok := vals[2].(string); ok {
+		rec.Result = []byte(v)
+	}
+	return rec, nil
+}
+
+func (s *RedisStore) Renew(ctx context.Context, key, owner string, lease time.Duration) (bool, error) {
+	n, err := renewScript.Run(ctx, s.client, []string{redisKeyPrefix + key}, owner, lease.Milliseconds()).Int()
+	return n == 1, err
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key, owner string, result []byte, ttl time.Duration) error {
+	n, err := completeScript.Run(ctx, s.client, []string{redisKeyPrefix + key}, owner, result, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key, owner string) error {
+	return releaseScript.Run(ctx, s.client, []string{redisKeyPrefix + key}, owner).Err()
+}
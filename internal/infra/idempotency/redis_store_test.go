@@ -0,0 +1,57 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Roda contra um Redis real: GOFLEET_TEST_REDIS_ADDR=localhost:6379
+func TestRedisStore_StateMachine(t *testing.T) {
+	addr := os.Getenv("GOFLEET_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("GOFLEET_TEST_REDIS_ADDR not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	store := NewRedisStore(client)
+	key := fmt.Sprintf("test:%d", time.Now().UnixNano())
+	t.Cleanup(func() { client.Del(ctx, redisKeyPrefix+key) })
+
+	rec, err := store.Acquire(ctx, key, "a", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, Record{State: StateProcessing, Owner: "a"}, rec)
+
+	rec, err = store.Acquire(ctx, key, "b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "a", rec.Owner, "live lease belongs to a")
+
+	// Lease vencido: b retoma e a deixa de ser dono.
+	time.Sleep(100 * time.Millisecond)
+	rec, err = store.Acquire(ctx, key, "b", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "b", rec.Owner)
+
+	ok, err := store.Renew(ctx, key, "a", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.ErrorIs(t, store.Complete(ctx, key, "a", nil, time.Hour), ErrLeaseLost)
+	require.NoError(t, store.Release(ctx, key, "a"))
+
+	require.NoError(t, store.Complete(ctx, key, "b", []byte("done"), time.Hour))
+	rec, err = store.Acquire(ctx, key, "c", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, Record{State: StateCompleted, Owner: "b", Result: []byte("done")}, rec)
+
+	ttl, err := client.PTTL(ctx, redisKeyPrefix+key).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, 59*time.Minute)
+}
@@ -0,0 +1,55 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// Message identifica a mensagem em processamento no inbox: o mesmo evento pode
+// ser processado uma vez por consumer.
+type Message struct {
+	Consumer string
+	EventID  string
+}
+
+type messageKey struct{}
+
+// WithMessage coloca a mensagem no ctx do handler. O repositório do inbox a lê
+// dentro da transação do efeito.
+func WithMessage(ctx context.Context, m Message) context.Context {
+	return context.WithValue(ctx, messageKey{}, m)
+}
+
+func MessageFrom(ctx context.Context) (Message, bool) {
+	m, ok := ctx.Value(messageKey{}).(Message)
+	return m, ok
+}
+
+// Pruner apaga linhas do inbox mais antigas que a retenção.
+type Pruner interface {
+	DeleteOldInboxMessages(ctx context.Context, retentionSecs float64) (int64, error)
+}
+
+// RunCleanup apaga a cada interval as linhas com mais de retention. A retenção
+// precisa cobrir a maior janela de reentrega (retry + Parking/replay): depois
+// dela, uma reentrega do mesmo evento seria processada de novo.
+func RunCleanup(ctx context.Context, p Pruner, interval, retention time.Duration, log logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := p.DeleteOldInboxMessages(ctx, retention.Seconds())
+			if err != nil {
+				log.Error(ctx, "Inbox cleanup failed", logger.WithError(err))
+			} else if n > 0 {
+				log.Debug(ctx, "Pruned old inbox messages", logger.Int("count", int(n)))
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/database"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEventNotFound = errors.New("outbox event not found")
+	// ErrEmptySelection evita que um requeue sem ids devolva todas as falhas para a fila.
+	ErrEmptySelection = errors.New("selection requires ids or all")
+)
+
+// Statuses aceitos pela listagem do admin.
+var Statuses = []string{"PENDING", "PROCESSING", "PUBLISHED", "FAILED", StatusDead}
+
+// EventSummary é a visão de listagem: sem payload.
+type EventSummary struct {
+	ID            uuid.UUID `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	AggregateType string    `json:"aggregate_type"`
+	AggregateID   string    `json:"aggregate_id"`
+	AggregateSeq  int64     `json:"aggregate_seq"`
+	EventType     string    `json:"event_type"`
+	Topic         string    `json:"topic"`
+	Status        string    `json:"status"`
+	RetryCount    int32     `json:"retry_count"`
+	ErrorMsg      string    `json:"error_msg,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	ClaimedBy     string    `json:"claimed_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// EventDetail inclui payload e dados de entrega para inspeção.
+type EventDetail struct {
+	EventSummary
+	EventVersion   int32           `json:"event_version"`
+	Payload        json.RawMessage `json:"payload"`
+	PublishedAt    *time.Time      `json:"published_at,omitempty"`
+	LeaseExpiresAt *time.Time      `json:"lease_expires_at,omitempty"`
+}
+
+// State descreve esta réplica do relay. Paused é compartilhado por todas.
+type State struct {
+	Owner  string `json:"owner"`
+	Leader bool   `json:"leader"`
+	Paused bool   `json:"paused"`
+}
+
+func (r *Relay) ListEvents(ctx context.Context, status string, limit int32) ([]EventSummary, error) {
+	rows, err := r.db.ListOutboxEventsByStatus(ctx, database.ListOutboxEventsByStatusParams{
+		Status: status,
+		Lim:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]EventSummary, len(rows))
+	for i, row := range rows {
+		out[i] = EventSummary{
+			ID:            row.ID,
+			TenantID:      row.TenantID,
+			AggregateType: row.AggregateType,
+			AggregateID:   row.AggregateID,
+			AggregateSeq:  row.AggregateSeq,
+			EventType:     row.EventType,
+			Topic:         row.Topic,
+			Status:        row.Status,
+			RetryCount:    row.RetryCount,
+			ErrorMsg:      row.ErrorMsg.String,
+			NextAttemptAt: row.NextAttemptAt,
+			ClaimedBy:     row.ClaimedBy.String,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+		}
+	}
+	return out, nil
+}
+
+func (r *Relay) GetEvent(ctx context.Context, id uuid.UUID) (EventDetail, error) {
+	row, err := r.db.GetOutboxEvent(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return EventDetail{}, ErrEventNotFound
+	}
+	if err != nil {
+		return EventDetail{}, err
+	}
+
+	return EventDetail{
+		EventSummary: EventSummary{
+			ID:            row.ID,
+			TenantID:      row.TenantID,
+			AggregateType: row.AggregateType,
+			AggregateID:   row.AggregateID,
+			AggregateSeq:  row.AggregateSeq,
+			EventType:     row.EventType,
+			Topic:         row.Topic,
+			Status:        row.Status,
+			RetryCount:    row.RetryCount,
+			ErrorMsg:      row.ErrorMsg.String,
+			NextAttemptAt: row.NextAttemptAt,
+			ClaimedBy:     row.ClaimedBy.String,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+		},
+		EventVersion:   row.EventVersion,
+		Payload:        row.Payload,
+		PublishedAt:    nullTime(row.PublishedAt),
+		LeaseExpiresAt: nullTime(row.LeaseExpiresAt),
+	}, nil
+}
+
+// Requeue devolve eventos DEAD ou FAILED para a fila: os de ids ou, com all, todos.
+func (r *Relay) Requeue(ctx context.Context, ids []uuid.UUID, all bool) (int64, error) {
+	if len(ids) == 0 && !all {
+		return 0, ErrEmptySelection
+	}
+	return r.db.RequeueFailedOutboxEvents(ctx, database.RequeueFailedOutboxEventsParams{AllEvents: all, Ids: ids})
+}
+
+// Pause suspende a publicação em todas as réplicas: a flag fica em
+// outbox_control e o líder a relê antes de cada lote. Lotes em voo terminam normalmente.
+func (r *Relay) Pause(ctx context.Context) error {
+	if err := r.db.SetOutboxPaused(ctx, database.SetOutboxPausedParams{Paused: true, UpdatedBy: r.owner}); err != nil {
+		return err
+	}
+	r.paused.Store(true)
+	return nil
+}
+
+// Resume retoma a publicação. Se o líder for outra réplica, ela volta no próximo poll.
+func (r *Relay) Resume(ctx context.Context) error {
+	if err := r.db.SetOutboxPaused(ctx, database.SetOutboxPausedParams{Paused: false, UpdatedBy: r.owner}); err != nil {
+		return err
+	}
+	r.paused.Store(false)
+	signalWake(r.resume)
+	return nil
+}
+
+func (r *Relay) State(ctx context.Context) (State, error) {
+	paused, err := r.db.GetOutboxPaused(ctx)
+	if err != nil {
+		return State{}, err
+	}
+	r.paused.Store(paused)
+
+	leader := true
+	if l, ok := r.leader.(interface{ IsLeader() bool }); ok {
+		leader = l.IsLeader()
+	}
+	return State{Owner: r.owner, Leader: leader, Paused: paused}, nil
+}
+
+// isPaused lê a pausa compartilhada. Sem banco, fica com o último valor lido:
+// o lote falharia de qualquer forma, e uma pausa não é desfeita por uma queda.
+func (r *Relay) isPaused(ctx context.Context) bool {
+	paused, err := r.db.GetOutboxPaused(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error(ctx, "Failed to read outbox pause flag", logger.WithError(err))
+		}
+		return r.paused.Load()
+	}
+	r.paused.Store(paused)
+	return paused
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,536 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/database"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/DioGolang/GoFleet/pkg/otel"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+// RetryPolicy define o backoff de eventos cuja publicação falhou.
+// Após MaxAttempts tentativas a linha vira DEAD e só volta por requeue manual.
+type RetryPolicy struct {
+	MaxAttempts int32
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	BaseBackoff: time.Second,
+	MaxBackoff:  10 * time.Minute,
+}
+
+// StatusDead é o status de eventos que esgotaram as tentativas de publicação.
+const StatusDead = "DEAD"
+
+// LeaderLockKey identifica o advisory lock disputado pelas réplicas do relay.
+const LeaderLockKey int64 = 0x676f666c656574 // "gofleet"
+
+// Leader elege uma única réplica para rodar fn; fn recebe um contexto cancelado
+// quando a liderança é perdida.
+type Leader interface {
+	Lead(ctx context.Context, fn func(ctx context.Context))
+}
+
+// Relay publica o outbox no broker. Só o líder publica e faz o rescue; cada
+// linha reivindicada leva o owner da réplica e um lease renovado por heartbeat.
+type Relay struct {
+	db             *database.Queries
+	dbConn         *sql.DB
+	dispatcher     events.ConfirmingDispatcher
+	logger         logger.Logger
+	batchSize      int32
+	confirmTimeout time.Duration
+	retry          RetryPolicy
+	lanes          int
+	wakeup         <-chan struct{}
+	pollInterval   time.Duration
+	leader         Leader
+	owner          string
+	lease          time.Duration
+	rescueInterval time.Duration
+	retention      string
+	metrics        metrics.Metrics
+	statsInterval  time.Duration
+	// paused é o último valor lido de outbox_control, usado se o banco não responder.
+	paused atomic.Bool
+	resume chan struct{}
+}
+
+type Option func(*Relay)
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(r *Relay) {
+		r.retry = p
+	}
+}
+
+func WithBatchSize(n int32) Option {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithLanes define quantas lanes publicam em paralelo. Eventos do mesmo
+// agregado caem sempre na mesma lane e saem em ordem de aggregate_seq.
+func WithLanes(n int) Option {
+	return func(r *Relay) {
+		if n > 0 {
+			r.lanes = n
+		}
+	}
+}
+
+// WithWakeup acorda o relay a cada sinal (ex: LISTEN/NOTIFY do Postgres).
+// O polling vira só um fallback para notificações perdidas.
+func WithWakeup(ch <-chan struct{}) Option {
+	return func(r *Relay) {
+		r.wakeup = ch
+	}
+}
+
+func WithPollInterval(d time.Duration) Option {
+	return func(r *Relay) {
+		if d > 0 {
+			r.pollInterval = d
+		}
+	}
+}
+
+// WithLeader restringe o relay e o rescuer à réplica eleita.
+func WithLeader(l Leader) Option {
+	return func(r *Relay) {
+		r.leader = l
+	}
+}
+
+// WithLease define por quanto tempo uma linha reivindicada pertence a esta réplica
+// sem renovação. O heartbeat renova a cada lease/3.
+func WithLease(d time.Duration) Option {
+	return func(r *Relay) {
+		if d > 0 {
+			r.lease = d
+		}
+	}
+}
+
+// WithMetrics exporta backlog, idade do evento mais antigo e latência de publish.
+func WithMetrics(m metrics.Metrics) Option {
+	return func(r *Relay) {
+		r.metrics = m
+	}
+}
+
+func NewRelay(db *database.Queries, conn *sql.DB, disp events.ConfirmingDispatcher, log logger.Logger, opts ...Option) *Relay {
+	r := &Relay{
+		db:             db,
+		dbConn:         conn,
+		dispatcher:     disp,
+		logger:         log,
+		batchSize:      100,
+		confirmTimeout: 5 * time.Second,
+		retry:          DefaultRetryPolicy,
+		lanes:          8,
+		pollInterval:   100 * time.Millisecond,
+		owner:          defaultOwner(),
+		lease:          30 * time.Second,
+		rescueInterval: time.Minute,
+		retention:      "7 days",
+		statsInterval:  15 * time.Second,
+		resume:         make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// defaultOwner identifica a réplica: hostname (nome do pod) + pid + sufixo aleatório.
+func defaultOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
+
+// Owner é o valor gravado em claimed_by pelas linhas desta réplica.
+func (r *Relay) Owner() string {
+	return r.owner
+}
+
+// Start roda relay, heartbeat e rescuer até ctx terminar. Com um Leader, só a
+// réplica eleita trabalha; as demais ficam em espera.
+func (r *Relay) Start(ctx context.Context) {
+	// As métricas de backlog saem de qualquer réplica, líder ou não.
+	if r.metrics != nil {
+		go r.runStats(ctx)
+	}
+	if r.leader == nil {
+		r.runAll(ctx)
+		return
+	}
+	r.leader.Lead(ctx, r.runAll)
+}
+
+func (r *Relay) runAll(ctx context.Context) {
+	r.logger.Info(ctx, "Starting Outbox Relay", logger.String("owner", r.owner))
+
+	var wg sync.WaitGroup
+	for _, run := range []func(context.Context){r.Run, r.runHeartbeat, r.RunRescuer} {
+		wg.Add(1)
+		go func(run func(context.Context)) {
+			defer wg.Done()
+			run(ctx)
+		}(run)
+	}
+	wg.Wait()
+}
+
+// runHeartbeat renova o lease das linhas em voo desta réplica.
+func (r *Relay) runHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(r.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.db.RenewOutboxLeases(ctx, database.RenewOutboxLeasesParams{
+				LeaseSecs: r.lease.Seconds(),
+				Owner:     r.owner,
+			}); err != nil {
+				r.logger.Error(ctx, "Failed to renew outbox leases", logger.WithError(err))
+			}
+		}
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wakeup:
+		case <-r.resume:
+		case <-ticker.C:
+		}
+	}
+}
+
+// runStats atualiza os gauges de backlog a partir do banco.
+func (r *Relay) runStats(ctx context.Context) {
+	ticker := time.NewTicker(r.statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := r.db.OutboxStats(ctx)
+			if err != nil {
+				r.logger.Error(ctx, "Failed to collect outbox stats", logger.WithError(err))
+				continue
+			}
+			depth := make(map[string]int64, len(Statuses))
+			var oldestPending time.Duration
+			for _, st := range stats {
+				depth[st.Status] = st.Depth
+				if st.Status == "PENDING" {
+					oldestPending = time.Duration(st.OldestAgeSecs * float64(time.Second))
+				}
+			}
+			// Status sem linhas também precisam voltar a zero.
+			for _, status := range Statuses {
+				if status != "PUBLISHED" {
+					r.metrics.SetOutboxBacklog(status, depth[status])
+				}
+			}
+			r.metrics.SetOutboxOldestPendingAge(oldestPending)
+		}
+	}
+}
+
+// drain processa lotes em sequência enquanto a busca devolver linhas: um lote
+// cheio indica backlog, e um lote parcial pode ter liberado eventos seguintes
+// dos mesmos agregados. Para no primeiro lote vazio ou quando pausado (em
+// qualquer réplica: a pausa vem de outbox_control).
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil && !r.isPaused(ctx) {
+		if r.processBatch(ctx) == 0 {
+			return
+		}
+	}
+}
+
+// processBatch devolve quantos eventos foram reivindicados.
+func (r *Relay) processBatch(ctx context.Context) int {
+	// FASE 1: Fetch & Claim (Transação Curta)
+	eventsToProcess, err := r.fetchAndClaim(ctx)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			r.logger.Error(ctx, "Failed to fetch batch", logger.WithError(err))
+		}
+		return 0
+	}
+
+	if len(eventsToProcess) == 0 {
+		return 0
+	}
+
+	// FASE 2: Particiona por agregado; cada lane publica em sequência (Network I/O - Fora da Transação)
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		published = make([]uuid.UUID, 0, len(eventsToProcess))
+		released  []uuid.UUID
+	)
+	for _, lane := range partitionLanes(eventsToProcess, r.lanes) {
+		wg.Add(1)
+		go func(lane []database.FetchPendingOutboxEventsRow) {
+			defer wg.Done()
+			ok, skipped := r.processLane(ctx, lane)
+			mu.Lock()
+			published = append(published, ok...)
+			released = append(released, skipped...)
+			mu.Unlock()
+		}(lane)
+	}
+	wg.Wait()
+
+	// FASE 3: Só o que o broker confirmou vira PUBLISHED.
+	if len(released) > 0 {
+		if err := r.db.ReleaseOutboxEvents(context.Background(), database.ReleaseOutboxEventsParams{
+			Ids:   released,
+			Owner: r.owner,
+		}); err != nil {
+			r.logger.Error(ctx, "Failed to release blocked events", logger.WithError(err))
+		}
+	}
+	if len(published) == 0 {
+		return len(eventsToProcess)
+	}
+	if err := r.db.MarkOutboxAsPublished(context.Background(), published); err != nil {
+		// Os eventos ficam em PROCESSING e o rescuer os devolve: at-least-once.
+		r.logger.Error(ctx, "Failed to mark batch as published", logger.WithError(err))
+	}
+	return len(eventsToProcess)
+}
+
+// processLane publica a lane na ordem do lote. Agregados diferentes seguem com
+// confirms em lote; o próximo evento de um mesmo agregado só sai depois do confirm
+// do anterior. Se um evento falha, os seguintes do agregado são devolvidos sem
+// contar tentativa, para não passarem na frente dele.
+func (r *Relay) processLane(ctx context.Context, lane []database.FetchPendingOutboxEventsRow) (published, released []uuid.UUID) {
+	type inflight struct {
+		evt     database.FetchPendingOutboxEventsRow
+		conf    events.Confirmation
+		sentAt  time.Time
+		settled bool
+		err     error
+	}
+
+	confirmCtx, cancel := context.WithTimeout(ctx, r.confirmTimeout)
+	defer cancel()
+
+	pending := make([]*inflight, 0, len(lane))
+	last := make(map[string]*inflight)
+	blocked := make(map[string]bool)
+
+	settle := func(p *inflight) {
+		if p.settled {
+			return
+		}
+		p.settled = true
+		if p.err = p.conf.Wait(confirmCtx); p.err != nil {
+			r.markFailed(ctx, p.evt, p.err)
+			blocked[aggregateKey(p.evt)] = true
+			return
+		}
+		if r.metrics != nil {
+			r.metrics.ObserveOutboxPublishLatency(time.Since(p.sentAt))
+			r.metrics.IncOutboxEventsProcessed("published")
+		}
+	}
+
+	for _, evt := range lane {
+		key := aggregateKey(evt)
+		if prev, ok := last[key]; ok {
+			settle(prev)
+		}
+		if blocked[key] {
+			released = append(released, evt.ID)
+			continue
+		}
+
+		sentAt := time.Now()
+		conf, err := r.publish(ctx, evt)
+		if err != nil {
+			r.markFailed(ctx, evt, err)
+			blocked[key] = true
+			continue
+		}
+		p := &inflight{evt: evt, conf: conf, sentAt: sentAt}
+		last[key] = p
+		pending = append(pending, p)
+	}
+
+	for _, p := range pending {
+		settle(p)
+		if p.err == nil {
+			published = append(published, p.evt.ID)
+		}
+	}
+	return published, released
+}
+
+func aggregateKey(evt database.FetchPendingOutboxEventsRow) string {
+	return evt.TenantID + "/" + evt.AggregateType + "/" + evt.AggregateID
+}
+
+// partitionLanes distribui o lote por hash do agregado, preservando a ordem
+// relativa dentro de cada lane. Lanes vazias são descartadas.
+func partitionLanes(evts []database.FetchPendingOutboxEventsRow, n int) [][]database.FetchPendingOutboxEventsRow {
+	if n < 1 {
+		n = 1
+	}
+	lanes := make([][]database.FetchPendingOutboxEventsRow, n)
+	for _, evt := range evts {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(aggregateKey(evt)))
+		i := h.Sum32() % uint32(n)
+		lanes[i] = append(lanes[i], evt)
+	}
+
+	out := lanes[:0]
+	for _, lane := range lanes {
+		if len(lane) > 0 {
+			out = append(out, lane)
+		}
+	}
+	return out
+}
+
+func (r *Relay) fetchAndClaim(ctx context.Context) ([]database.FetchPendingOutboxEventsRow, error) {
+	tx, err := r.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.db.WithTx(tx)
+
+	events, err := qtx.FetchPendingOutboxEvents(ctx, r.batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+
+	if err := qtx.MarkOutboxAsProcessing(ctx, database.MarkOutboxAsProcessingParams{
+		Owner:     r.owner,
+		LeaseSecs: r.lease.Seconds(),
+		Ids:       ids,
+	}); err != nil {
+		return nil, err
+	}
+	return events, tx.Commit()
+}
+
+func (r *Relay) publish(ctx context.Context, evt database.FetchPendingOutboxEventsRow) (events.Confirmation, error) {
+	traceCtx := otel.InjectContextFromJSON(ctx, evt.TracingContext)
+	traceCtx = tenant.WithTenant(traceCtx, evt.TenantID)
+
+	return r.dispatcher.DispatchAsync(traceCtx, events.RawMessage{
+		ID:            evt.ID.String(),
+		AggregateType: evt.AggregateType,
+		AggregateID:   evt.AggregateID,
+		EventType:     evt.EventType,
+		Topic:         evt.Topic,
+		Time:          evt.CreatedAt,
+		Payload:       evt.Payload,
+		Extensions: map[string]string{
+			events.ExtTenantID:    evt.TenantID,
+			events.ExtSequence:    strconv.FormatInt(evt.AggregateSeq, 10),
+			events.ExtDataVersion: strconv.FormatInt(int64(evt.EventVersion), 10),
+		},
+		// Consumidores anteriores ao envelope ainda leem o tenant pelo header.
+		Headers: map[string]string{tenant.Header: evt.TenantID},
+	})
+}
+
+// markFailed devolve o evento para PENDING com backoff, ou DEAD se esgotou as tentativas.
+func (r *Relay) markFailed(ctx context.Context, evt database.FetchPendingOutboxEventsRow, cause error) {
+	res, err := r.db.ScheduleOutboxRetry(context.Background(), database.ScheduleOutboxRetryParams{
+		MaxAttempts:     r.retry.MaxAttempts,
+		ErrorMsg:        sql.NullString{String: cause.Error(), Valid: true},
+		MaxBackoffSecs:  r.retry.MaxBackoff.Seconds(),
+		BaseBackoffSecs: r.retry.BaseBackoff.Seconds(),
+		ID:              evt.ID,
+		Owner:           r.owner,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// O lease expirou e o rescue devolveu a linha: o novo dono decide o destino dela.
+		r.logger.Warn(ctx, "Lost lease before scheduling retry", logger.String("id", evt.ID.String()), logger.WithError(cause))
+		return
+	}
+	if err != nil {
+		r.logger.Error(ctx, "Failed to schedule event retry", logger.String("id", evt.ID.String()), logger.WithError(err))
+		return
+	}
+
+	if r.metrics != nil {
+		outcome := "retry"
+		if res.Status == StatusDead {
+			outcome = "dead"
+		}
+		r.metrics.IncOutboxEventsProcessed(outcome)
+	}
+	if res.Status == StatusDead {
+		r.logger.Error(ctx, "Event exhausted publish attempts, moved to DEAD",
+			logger.String("id", evt.ID.String()),
+			logger.Int("attempts", int(res.RetryCount)),
+			logger.WithError(cause))
+		return
+	}
+	r.logger.Warn(ctx, "Failed to publish event, retry scheduled",
+		logger.String("id", evt.ID.String()),
+		logger.Int("attempt", int(res.RetryCount)),
+		logger.WithError(cause))
+}
+
+// signalWake faz um envio não bloqueante: um sinal pendente basta.
+func signalWake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
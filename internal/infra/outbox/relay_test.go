@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/infra/database"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConfirming registra a sequência de publish/wait para checar a ordem por agregado.
+type fakeConfirming struct {
+	mu  sync.Mutex
+	log []string
+}
+
+type fakeConfirmation struct {
+	d   *fakeConfirming
+	key string
+}
+
+func (c fakeConfirmation) Wait(context.Context) error {
+	c.d.record("wait " + c.key)
+	return nil
+}
+
+func (d *fakeConfirming) record(s string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, s)
+}
+
+func (d *fakeConfirming) DispatchAsync(_ context.Context, msg events.RawMessage) (events.Confirmation, error) {
+	key := msg.AggregateID + "#" + msg.Extensions[events.ExtSequence]
+	d.record("publish " + key)
+	return fakeConfirmation{d: d, key: key}, nil
+}
+
+func row(aggregateID string, seq int64) database.FetchPendingOutboxEventsRow {
+	return database.FetchPendingOutboxEventsRow{
+		ID:            uuid.New(),
+		AggregateType: "Order",
+		EventType:     "OrderCreated",
+		AggregateID:   aggregateID,
+		TenantID:      "demo",
+		AggregateSeq:  seq,
+	}
+}
+
+func TestPartitionLanes_KeepsAggregateOnOneLaneInOrder(t *testing.T) {
+	var batch []database.FetchPendingOutboxEventsRow
+	for seq := int64(1); seq <= 3; seq++ {
+		for i := 0; i < 20; i++ {
+			batch = append(batch, row(fmt.Sprintf("order-%d", i), seq))
+		}
+	}
+
+	lanes := partitionLanes(batch, 4)
+	assert.LessOrEqual(t, len(lanes), 4)
+
+	laneOf := make(map[string]int)
+	total := 0
+	for i, lane := range lanes {
+		require.NotEmpty(t, lane)
+		lastSeq := make(map[string]int64)
+		for _, evt := range lane {
+			if l, ok := laneOf[evt.AggregateID]; ok {
+				assert.Equal(t, l, i, "aggregate %s split across lanes", evt.AggregateID)
+			}
+			laneOf[evt.AggregateID] = i
+			assert.Greater(t, evt.AggregateSeq, lastSeq[evt.AggregateID])
+			lastSeq[evt.AggregateID] = evt.AggregateSeq
+			total++
+		}
+	}
+	assert.Equal(t, len(batch), total)
+}
+
+func TestProcessLane_WaitsPreviousConfirmOfSameAggregate(t *testing.T) {
+	disp := &fakeConfirming{}
+	r := NewRelay(nil, nil, disp, logger.NewZapLogger("test", false))
+
+	lane := []database.FetchPendingOutboxEventsRow{row("a", 1), row("b", 1), row("a", 2)}
+	published, released := r.processLane(context.Background(), lane)
+
+	assert.Len(t, published, 3)
+	assert.Empty(t, released)
+	assert.Equal(t, []string{
+		"publish a#1",
+		"publish b#1",
+		"wait a#1",
+		"publish a#2",
+		"wait b#1",
+		"wait a#2",
+	}, disp.log)
+}
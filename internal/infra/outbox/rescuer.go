@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+// RunRescuer devolve linhas cujo lease expirou (dono morto ou travado) e apaga
+// eventos antigos. Roda só no líder, para não haver réplicas disputando a limpeza.
+func (r *Relay) RunRescuer(ctx context.Context) {
+	ticker := time.NewTicker(r.rescueInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.rescue(ctx)
+		}
+	}
+}
+
+func (r *Relay) rescue(ctx context.Context) {
+	n, err := r.db.ReclaimExpiredOutboxEvents(ctx)
+	if err != nil {
+		r.logger.Error(ctx, "Failed to reclaim expired outbox leases", logger.WithError(err))
+	} else if n > 0 {
+		r.logger.Warn(ctx, "Reclaimed outbox events with expired lease", logger.Int("count", int(n)))
+	}
+
+	if err := r.db.DeleteOldOutboxEvents(ctx, r.retention); err != nil {
+		r.logger.Error(ctx, "Cleanup failed", logger.WithError(err))
+	}
+}
@@ -0,0 +1,426 @@
+package parking
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/port/outbound"
+	"github.com/DioGolang/GoFleet/internal/infra/event"
+	"github.com/DioGolang/GoFleet/pkg/events"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	ErrMessageNotFound = errors.New("parked message not found")
+	ErrQueueNotFound   = errors.New("parking queue not found")
+	// ErrEmptySelection evita que um replay ou purge sem filtro atinja a fila inteira.
+	ErrEmptySelection = errors.New("selection requires ids, a filter or all")
+)
+
+const (
+	// Headers gravados no replay, para rastrear a mensagem de volta ao operador.
+	ReplayedByHeader = "x-replayed-by"
+	ReplayedAtHeader = "x-replayed-at"
+
+	// legacyTenant recebe a auditoria de mensagens sem tenant (mesmo default da migration 00004).
+	legacyTenant = "default"
+
+	defaultMaxScan = 10000
+)
+
+// Headers que descrevem a passagem anterior pelo pipeline e não voltam no replay.
+var strippedOnReplay = []string{
+	event.RetryHeader, "x-death", event.OriginalQueueHeader, event.FailReasonHeader, event.ParkedAtHeader,
+}
+
+// Message é a visão de listagem de uma mensagem estacionada: sem corpo.
+type Message struct {
+	ID          string         `json:"id"`
+	Queue       string         `json:"queue"`
+	Reason      string         `json:"reason"`
+	EventType   string         `json:"event_type,omitempty"`
+	TenantID    string         `json:"tenant_id,omitempty"`
+	Retries     int            `json:"retries"`
+	ContentType string         `json:"content_type,omitempty"`
+	ParkedAt    *time.Time     `json:"parked_at,omitempty"`
+	Size        int            `json:"size"`
+	Headers     map[string]any `json:"headers"`
+}
+
+// MessageDetail inclui o corpo: JSON como está, demais formatos em base64.
+type MessageDetail struct {
+	Message
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyBase64 []byte          `json:"body_base64,omitempty"`
+}
+
+// Filter seleciona mensagens por id, motivo ou tipo de evento. Critérios
+// preenchidos são combinados com AND; All seleciona tudo explicitamente.
+type Filter struct {
+	IDs       []string `json:"ids,omitempty"`
+	Reason    string   `json:"reason,omitempty"`
+	EventType string   `json:"event_type,omitempty"`
+	All       bool     `json:"all,omitempty"`
+}
+
+func (f Filter) Empty() bool {
+	return !f.All && len(f.IDs) == 0 && f.Reason == "" && f.EventType == ""
+}
+
+func (f Filter) Match(m Message) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, m.ID) {
+		return false
+	}
+	if f.Reason != "" && f.Reason != m.Reason {
+		return false
+	}
+	// Aceita o type completo (io.gofleet.order.OrderCreated) ou só o último segmento.
+	if f.EventType != "" && f.EventType != m.EventType && !strings.HasSuffix(m.EventType, "."+f.EventType) {
+		return false
+	}
+	return true
+}
+
+// Actor é quem executa a operação, gravado na auditoria.
+type Actor struct {
+	ID   string
+	Role string
+}
+
+// Result resume um replay ou purge.
+type Result struct {
+	Matched  int      `json:"matched"`
+	Replayed int      `json:"replayed,omitempty"`
+	Purged   int      `json:"purged,omitempty"`
+	Failed   []string `json:"failed,omitempty"`
+}
+
+type Option func(*Manager)
+
+// WithMaxScan limita quantas mensagens uma operação lê da fila.
+func WithMaxScan(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.maxScan = n
+		}
+	}
+}
+
+// Manager inspeciona e opera as filas de Parking (<fila>.parking).
+//
+// A leitura usa basic.get sem ack: as mensagens lidas ficam retidas no canal da
+// operação e voltam para a fila quando ele fecha. Só as selecionadas recebem ack,
+// depois de republicadas (replay) ou descartadas (purge). Nada é perdido se o
+// processo cair no meio.
+type Manager struct {
+	conn    event.Connector
+	audit   outbound.AuditRepository
+	logger  logger.Logger
+	maxScan int
+}
+
+func NewManager(conn event.Connector, audit outbound.AuditRepository, l logger.Logger, opts ...Option) *Manager {
+	m := &Manager{conn: conn, audit: audit, logger: l, maxScan: defaultMaxScan}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// List devolve até `limit` mensagens que casam com o filtro, da mais antiga à mais nova.
+func (m *Manager) List(ctx context.Context, queue string, f Filter, limit int) ([]Message, error) {
+	out := []Message{}
+	err := m.scan(ctx, queue, func(_ *amqp.Channel, d amqp.Delivery, msg Message) (bool, error) {
+		if f.Match(msg) {
+			out = append(out, msg)
+		}
+		return limit > 0 && len(out) >= limit, nil
+	})
+	return out, err
+}
+
+// Get devolve uma mensagem com o corpo.
+func (m *Manager) Get(ctx context.Context, queue, id string) (MessageDetail, error) {
+	var detail *MessageDetail
+	err := m.scan(ctx, queue, func(_ *amqp.Channel, d amqp.Delivery, msg Message) (bool, error) {
+		if msg.ID != id {
+			return false, nil
+		}
+		detail = toDetail(d, msg)
+		return true, nil
+	})
+	if err != nil {
+		return MessageDetail{}, err
+	}
+	if detail == nil {
+		return MessageDetail{}, ErrMessageNotFound
+	}
+	return *detail, nil
+}
+
+// Replay republica as mensagens selecionadas na fila original (x-original-queue),
+// com as tentativas zeradas. Cada mensagem só sai do Parking depois do ack do
+// broker, e cada replay é auditado no tenant da mensagem.
+func (m *Manager) Replay(ctx context.Context, queue string, f Filter, actor Actor) (Result, error) {
+	if f.Empty() {
+		return Result{}, ErrEmptySelection
+	}
+
+	var res Result
+	var publisher *event.ConfirmPublisher
+	// Um replay que falha de novo volta ao fim deste Parking durante o scan: não
+	// republica o mesmo evento duas vezes na mesma chamada.
+	replayed := map[string]bool{}
+	err := m.scan(ctx, queue, func(ch *amqp.Channel, d amqp.Delivery, msg Message) (bool, error) {
+		if !f.Match(msg) || replayed[msg.ID] {
+			return false, nil
+		}
+		res.Matched++
+
+		if publisher == nil {
+			var err error
+			if publisher, err = event.NewConfirmPublisher(ch); err != nil {
+				return true, err
+			}
+		}
+
+		target := msg.Queue
+		if target == "" {
+			target = queue
+		}
+		// mandatory: se a fila original não existir, o replay falha em vez de sumir.
+		conf, err := publisher.Publish(ctx, event.Destination{RoutingKey: target, Mandatory: true}, replayPublishing(d, actor, time.Now()))
+		if err == nil {
+			err = conf.Wait(ctx)
+		}
+		if err != nil {
+			m.logger.Error(ctx, "Failed to replay parked message",
+				logger.String("queue", queue),
+				logger.String("message_id", msg.ID),
+				logger.WithError(err),
+			)
+			res.Failed = append(res.Failed, msg.ID)
+			// Canal fechado ou contexto cancelado: não adianta seguir.
+			return ctx.Err() != nil || errors.Is(err, event.ErrChannelClosed), nil
+		}
+
+		if err := d.Ack(false); err != nil {
+			return true, fmt.Errorf("ack replayed message %s: %w", msg.ID, err)
+		}
+		replayed[msg.ID] = true
+		res.Replayed++
+		m.record(ctx, actor, "parking.replay", msg, map[string]any{"target_queue": target})
+		return false, nil
+	})
+	return res, err
+}
+
+// Purge descarta as mensagens selecionadas. Cada descarte é auditado.
+func (m *Manager) Purge(ctx context.Context, queue string, f Filter, actor Actor) (Result, error) {
+	if f.Empty() {
+		return Result{}, ErrEmptySelection
+	}
+
+	var res Result
+	err := m.scan(ctx, queue, func(_ *amqp.Channel, d amqp.Delivery, msg Message) (bool, error) {
+		if !f.Match(msg) {
+			return false, nil
+		}
+		res.Matched++
+		if err := d.Ack(false); err != nil {
+			return true, fmt.Errorf("ack purged message %s: %w", msg.ID, err)
+		}
+		res.Purged++
+		m.record(ctx, actor, "parking.purge", msg, nil)
+		return false, nil
+	})
+	return res, err
+}
+
+// scan lê a fila de Parking em um canal próprio até esvaziar, visit pedir parada
+// ou ler as mensagens que ela tinha no início (no máximo maxScan). Mensagens sem
+// ack voltam para a fila no Close do canal.
+func (m *Manager) scan(ctx context.Context, queue string, visit func(ch *amqp.Channel, d amqp.Delivery, msg Message) (bool, error)) error {
+	ch, err := m.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	parkingQueue := event.ParkingQueueName(queue)
+	q, err := ch.QueueDeclarePassive(parkingQueue, true, false, false, false, nil)
+	if err != nil {
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			return fmt.Errorf("%w: %s", ErrQueueNotFound, parkingQueue)
+		}
+		return fmt.Errorf("failed to inspect %s: %w", parkingQueue, err)
+	}
+
+	// O que entrar depois do início (ex: replay que falhou e foi reestacionado)
+	// fica para a próxima chamada.
+	return scanQueue(ctx, ch, parkingQueue, min(q.Messages, m.maxScan), func(d amqp.Delivery, msg Message) (bool, error) {
+		return visit(ch, d, msg)
+	})
+}
+
+// getter é a parte do canal usada pelo scan.
+type getter interface {
+	Get(queue string, autoAck bool) (amqp.Delivery, bool, error)
+}
+
+func scanQueue(ctx context.Context, g getter, parkingQueue string, limit int, visit func(d amqp.Delivery, msg Message) (bool, error)) error {
+	for range limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		d, ok, err := g.Get(parkingQueue, false)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", parkingQueue, err)
+		}
+		if !ok {
+			return nil
+		}
+		stop, err := visit(d, toMessage(d))
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+// record grava a auditoria no tenant da mensagem. Falha de auditoria não desfaz a
+// operação (a mensagem já saiu do Parking), mas fica registrada no log.
+func (m *Manager) record(ctx context.Context, actor Actor, action string, msg Message, metadata map[string]any) {
+	if m.audit == nil {
+		return
+	}
+	tenantID := msg.TenantID
+	if tenantID == "" {
+		tenantID = legacyTenant
+	}
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["parking_queue"] = event.ParkingQueueName(msg.Queue)
+	metadata["reason"] = msg.Reason
+	metadata["event_type"] = msg.EventType
+
+	err := m.audit.Record(tenant.WithTenant(ctx, tenantID), outbound.AuditEntry{
+		ActorID:      actor.ID,
+		ActorRole:    actor.Role,
+		Action:       action,
+		ResourceType: "ParkedMessage",
+		ResourceID:   msg.ID,
+		Metadata:     metadata,
+	})
+	if err != nil {
+		m.logger.Error(ctx, "Failed to audit parking operation",
+			logger.String("action", action),
+			logger.String("message_id", msg.ID),
+			logger.WithError(err),
+		)
+	}
+}
+
+func toMessage(d amqp.Delivery) Message {
+	msg := Message{
+		ID:          d.MessageId,
+		Queue:       headerString(d.Headers, event.OriginalQueueHeader),
+		Reason:      headerString(d.Headers, event.FailReasonHeader),
+		TenantID:    headerString(d.Headers, tenant.Header),
+		Retries:     retries(d.Headers),
+		ContentType: d.ContentType,
+		Size:        len(d.Body),
+		Headers:     d.Headers,
+	}
+	if msg.Headers == nil {
+		msg.Headers = map[string]any{}
+	}
+	if t, ok := d.Headers[event.ParkedAtHeader].(time.Time); ok {
+		msg.ParkedAt = &t
+	}
+
+	// Envelopes inválidos também vão ao Parking: o que não der para ler fica vazio.
+	if ce, err := event.ParseDelivery(d); err == nil {
+		msg.ID = ce.ID
+		msg.EventType = ce.Type
+		if id := ce.Extension(events.ExtTenantID); id != "" {
+			msg.TenantID = id
+		}
+	} else if msg.EventType == "" {
+		msg.EventType = d.Type
+	}
+
+	// Sem id no envelope nem message_id, o hash do corpo identifica a mensagem.
+	if msg.ID == "" {
+		sum := sha256.Sum256(d.Body)
+		msg.ID = "sha256:" + hex.EncodeToString(sum[:8])
+	}
+	return msg
+}
+
+func toDetail(d amqp.Delivery, msg Message) *MessageDetail {
+	detail := &MessageDetail{Message: msg}
+	if json.Valid(d.Body) {
+		detail.Body = json.RawMessage(d.Body)
+	} else {
+		detail.BodyBase64 = d.Body
+	}
+	return detail
+}
+
+// replayPublishing copia a mensagem estacionada sem o histórico de falhas, para
+// que ela volte à fila original com todas as tentativas da política de retry.
+func replayPublishing(d amqp.Delivery, actor Actor, now time.Time) amqp.Publishing {
+	headers := make(amqp.Table, len(d.Headers)+2)
+	for k, v := range d.Headers {
+		if !slices.Contains(strippedOnReplay, k) {
+			headers[k] = v
+		}
+	}
+	headers[ReplayedByHeader] = actor.ID
+	headers[ReplayedAtHeader] = now.UTC()
+
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		MessageId:       d.MessageId,
+		Type:            d.Type,
+		Timestamp:       d.Timestamp,
+		Body:            d.Body,
+		DeliveryMode:    amqp.Persistent,
+	}
+}
+
+func headerString(h amqp.Table, key string) string {
+	switch v := h[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func retries(h amqp.Table) int {
+	switch v := h[event.RetryHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
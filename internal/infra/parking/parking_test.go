@@ -0,0 +1,135 @@
+package parking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/infra/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parkedDelivery() amqp.Delivery {
+	return amqp.Delivery{
+		ContentType: "application/json",
+		MessageId:   "evt-1",
+		Body:        []byte(`{"id":"order-1"}`),
+		Headers: amqp.Table{
+			"cloudEvents_specversion": "1.0",
+			"cloudEvents_id":          "evt-1",
+			"cloudEvents_source":      "/gofleet/api",
+			"cloudEvents_type":        "io.gofleet.order.OrderCreated",
+			"cloudEvents_tenantid":    "acme",
+			event.RetryHeader:         int32(4),
+			event.OriginalQueueHeader: "orders.created",
+			event.FailReasonHeader:    "max-retries-exceeded",
+			event.ParkedAtHeader:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestToMessage(t *testing.T) {
+	msg := toMessage(parkedDelivery())
+
+	assert.Equal(t, "evt-1", msg.ID)
+	assert.Equal(t, "orders.created", msg.Queue)
+	assert.Equal(t, "max-retries-exceeded", msg.Reason)
+	assert.Equal(t, "io.gofleet.order.OrderCreated", msg.EventType)
+	assert.Equal(t, "acme", msg.TenantID)
+	assert.Equal(t, 4, msg.Retries)
+	assert.NotNil(t, msg.ParkedAt)
+}
+
+func TestToMessage_UnparseableFallsBackToBodyHash(t *testing.T) {
+	d := amqp.Delivery{Body: []byte("garbage"), Headers: amqp.Table{event.FailReasonHeader: "invalid-cloudevent"}}
+
+	a, b := toMessage(d), toMessage(d)
+	assert.Contains(t, a.ID, "sha256:")
+	assert.Equal(t, a.ID, b.ID)
+	assert.Equal(t, "invalid-cloudevent", a.Reason)
+
+	detail := toDetail(d, a)
+	assert.Nil(t, detail.Body)
+	assert.Equal(t, []byte("garbage"), detail.BodyBase64)
+}
+
+func TestFilter(t *testing.T) {
+	msg := toMessage(parkedDelivery())
+
+	tests := []struct {
+		name  string
+		f     Filter
+		empty bool
+		match bool
+	}{
+		{name: "empty", f: Filter{}, empty: true, match: true},
+		{name: "all", f: Filter{All: true}, match: true},
+		{name: "by id", f: Filter{IDs: []string{"evt-9", "evt-1"}}, match: true},
+		{name: "other id", f: Filter{IDs: []string{"evt-9"}}, match: false},
+		{name: "by reason", f: Filter{Reason: "max-retries-exceeded"}, match: true},
+		{name: "short event type", f: Filter{EventType: "OrderCreated"}, match: true},
+		{name: "full event type", f: Filter{EventType: "io.gofleet.order.OrderCreated"}, match: true},
+		{name: "criteria are combined", f: Filter{Reason: "max-retries-exceeded", EventType: "OrderCancelled"}, match: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.empty, tt.f.Empty())
+			assert.Equal(t, tt.match, tt.f.Match(msg))
+		})
+	}
+}
+
+func TestReplayPublishing_ResetsFailureHistory(t *testing.T) {
+	d := parkedDelivery()
+	d.Headers["x-death"] = []interface{}{amqp.Table{"count": int64(3)}}
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+
+	pub := replayPublishing(d, Actor{ID: "ops-1", Role: "admin"}, now)
+
+	for _, h := range strippedOnReplay {
+		assert.NotContains(t, pub.Headers, h)
+	}
+	assert.Equal(t, "acme", pub.Headers["cloudEvents_tenantid"])
+	assert.Equal(t, "ops-1", pub.Headers[ReplayedByHeader])
+	assert.Equal(t, now, pub.Headers[ReplayedAtHeader])
+	assert.Equal(t, d.Body, pub.Body)
+	assert.Equal(t, "evt-1", pub.MessageId)
+	assert.Equal(t, uint8(amqp.Persistent), pub.DeliveryMode)
+	// O original continua intacto caso o replay falhe e a mensagem volte à fila.
+	assert.Contains(t, d.Headers, event.RetryHeader)
+}
+
+// fakeParking é uma fila de Parking em memória: Get consome do início.
+type fakeParking struct {
+	queue []amqp.Delivery
+}
+
+func (f *fakeParking) Get(string, bool) (amqp.Delivery, bool, error) {
+	if len(f.queue) == 0 {
+		return amqp.Delivery{}, false, nil
+	}
+	d := f.queue[0]
+	f.queue = f.queue[1:]
+	return d, true, nil
+}
+
+func TestScanQueue_StopsBeforeReparkedMessages(t *testing.T) {
+	first, second := parkedDelivery(), parkedDelivery()
+	second.Headers = amqp.Table{"cloudEvents_id": "evt-2"}
+	second.MessageId = "evt-2"
+	broker := &fakeParking{queue: []amqp.Delivery{first, second}}
+
+	// Cada replay falha de novo e o broker reestaciona a mensagem no fim da fila.
+	visits := map[string]int{}
+	err := scanQueue(context.Background(), broker, "orders.parking", len(broker.queue), func(d amqp.Delivery, msg Message) (bool, error) {
+		visits[msg.ID]++
+		broker.queue = append(broker.queue, d)
+		return false, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"evt-1": 1, "evt-2": 1}, visits)
+	assert.Len(t, broker.queue, 2)
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+)
+
+// Quota define o GCRA: Rate requisições a cada Period, com rajada de até Burst.
+type Quota struct {
+	Rate   int           `yaml:"rate"`
+	Burst  int           `yaml:"burst"`
+	Period time.Duration `yaml:"period"`
+}
+
+// EmissionInterval é o intervalo "ideal" entre duas requisições.
+func (q Quota) EmissionInterval() time.Duration {
+	if q.Rate <= 0 {
+		return q.Period
+	}
+	return q.Period / time.Duration(q.Rate)
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // Só preenchido quando Allowed == false
+	ResetAfter time.Duration // Tempo até o balde estar cheio novamente
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, quota Quota) (Result, error)
+}
+
+const defaultFallbackCooldown = 5 * time.Second
+
+// FallbackLimiter usa o limiter distribuído (Redis) e cai para o limiter local
+// quando ele falha. Em fallback cada réplica aplica a cota isoladamente.
+//
+// Depois de uma falha o primário fica de fora por cooldown: com o Redis fora,
+// cada request não espera o timeout inteiro. Entrada e saída do fallback são
+// logadas uma vez por transição.
+type FallbackLimiter struct {
+	primary   Limiter
+	secondary Limiter
+	timeout   time.Duration
+	cooldown  time.Duration
+	logger    logger.Logger
+	metrics   metrics.Metrics
+	now       func() time.Time
+
+	mu       sync.Mutex
+	degraded bool
+	until    time.Time // Fim do cooldown; até lá nem tenta o primário
+}
+
+type FallbackOption func(*FallbackLimiter)
+
+// WithFallbackCooldown define por quanto tempo o primário é ignorado após uma falha.
+func WithFallbackCooldown(d time.Duration) FallbackOption {
+	return func(f *FallbackLimiter) {
+		if d > 0 {
+			f.cooldown = d
+		}
+	}
+}
+
+func WithFallbackMetrics(mt metrics.Metrics) FallbackOption {
+	return func(f *FallbackLimiter) {
+		f.metrics = mt
+	}
+}
+
+func NewFallbackLimiter(primary, secondary Limiter, timeout time.Duration, log logger.Logger, opts ...FallbackOption) *FallbackLimiter {
+	f := &FallbackLimiter{
+		primary:   primary,
+		secondary: secondary,
+		timeout:   timeout,
+		cooldown:  defaultFallbackCooldown,
+		logger:    log,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func (f *FallbackLimiter) Allow(ctx context.Context, key string, quota Quota) (Result, error) {
+	if f.coolingDown() {
+		return f.secondary.Allow(ctx, key, quota)
+	}
+
+	primaryCtx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	res, err := f.primary.Allow(primaryCtx, key, quota)
+	if err == nil {
+		f.recovered(ctx)
+		return res, nil
+	}
+
+	// Request cancelado pelo cliente não diz nada sobre o Redis.
+	if ctx.Err() == nil {
+		f.tripped(ctx, err)
+	}
+	return f.secondary.Allow(ctx, key, quota)
+}
+
+func (f *FallbackLimiter) coolingDown() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now().Before(f.until)
+}
+
+func (f *FallbackLimiter) tripped(ctx context.Context, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.until = f.now().Add(f.cooldown)
+	if f.degraded {
+		return
+	}
+	f.degraded = true
+	f.logger.Warn(ctx, "Distributed rate limiter unavailable, using in-memory fallback",
+		logger.String("cooldown", f.cooldown.String()),
+		logger.WithError(err),
+	)
+	if f.metrics != nil {
+		f.metrics.SetRateLimitFallback(true)
+	}
+}
+
+func (f *FallbackLimiter) recovered(ctx context.Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.degraded {
+		return
+	}
+	f.degraded = false
+	f.logger.Info(ctx, "Distributed rate limiter recovered")
+	if f.metrics != nil {
+		f.metrics.SetRateLimitFallback(false)
+	}
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flakyLimiter struct {
+	err   error
+	calls int
+}
+
+func (l *flakyLimiter) Allow(context.Context, string, Quota) (Result, error) {
+	l.calls++
+	if l.err != nil {
+		return Result{}, l.err
+	}
+	return Result{Allowed: true, Limit: 99}, nil
+}
+
+type fallbackMetrics struct {
+	metrics.Metrics
+	states []bool
+}
+
+func (m *fallbackMetrics) SetRateLimitFallback(active bool) { m.states = append(m.states, active) }
+
+func TestFallbackLimiter_SkipsPrimaryDuringCooldown(t *testing.T) {
+	ctx := context.Background()
+	primary := &flakyLimiter{err: errors.New("redis down")}
+	mt := &fallbackMetrics{}
+	now := time.Now()
+
+	f := NewFallbackLimiter(primary, NewMemoryLimiter(ctx, time.Minute, time.Minute), time.Second,
+		logger.NewZapLogger("test", false), WithFallbackCooldown(5*time.Second), WithFallbackMetrics(mt))
+	f.now = func() time.Time { return now }
+	q := Quota{Rate: 1, Burst: 10, Period: time.Minute}
+
+	// Só a primeira falha paga o timeout; o resto do cooldown vai direto à memória.
+	for range 5 {
+		res, err := f.Allow(ctx, "k", q)
+		require.NoError(t, err)
+		assert.Equal(t, 10, res.Limit)
+	}
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, []bool{true}, mt.states)
+
+	// Passado o cooldown, o primário é testado de novo: ainda fora, novo cooldown.
+	now = now.Add(6 * time.Second)
+	_, err := f.Allow(ctx, "k", q)
+	require.NoError(t, err)
+	_, err = f.Allow(ctx, "k", q)
+	require.NoError(t, err)
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, []bool{true}, mt.states, "still degraded: no new transition")
+
+	// Redis de volta: sai do fallback uma vez.
+	primary.err = nil
+	now = now.Add(6 * time.Second)
+	for range 2 {
+		res, err := f.Allow(ctx, "k", q)
+		require.NoError(t, err)
+		assert.Equal(t, 99, res.Limit)
+	}
+	assert.Equal(t, 4, primary.calls)
+	assert.Equal(t, []bool{true, false}, mt.states)
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// MemoryLimiter é o Token Bucket local (por processo). Usado como fallback
+// quando o Redis está fora. Clientes inativos são limpos periodicamente.
+type MemoryLimiter struct {
+	mu            sync.Mutex
+	visitors      map[string]*visitor
+	clientTimeout time.Duration
+}
+
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewMemoryLimiter(ctx context.Context, cleanupInterval, clientTimeout time.Duration) *MemoryLimiter {
+	m := &MemoryLimiter{
+		visitors:      make(map[string]*visitor),
+		clientTimeout: clientTimeout,
+	}
+	go m.cleanupLoop(ctx, cleanupInterval)
+	return m
+}
+
+func (m *MemoryLimiter) Allow(_ context.Context, key string, quota Quota) (Result, error) {
+	limiter := m.getVisitor(key, quota)
+	now := time.Now()
+
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); !reservation.OK() || delay > 0 {
+		reservation.CancelAt(now)
+		return Result{
+			Allowed:    false,
+			Limit:      quota.Burst,
+			RetryAfter: delay,
+			ResetAfter: refill(limiter, quota, now),
+		}, nil
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      quota.Burst,
+		Remaining:  int(math.Max(0, math.Floor(limiter.TokensAt(now)))),
+		ResetAfter: refill(limiter, quota, now),
+	}, nil
+}
+
+func refill(l *rate.Limiter, quota Quota, now time.Time) time.Duration {
+	missing := float64(quota.Burst) - l.TokensAt(now)
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing * float64(quota.EmissionInterval()))
+}
+
+func (m *MemoryLimiter) getVisitor(key string, quota Quota) *rate.Limiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, exists := m.visitors[key]
+	if !exists {
+		limiter := rate.NewLimiter(rate.Every(quota.EmissionInterval()), quota.Burst)
+		m.visitors[key] = &visitor{limiter: limiter, lastSeen: time.Now()}
+		return limiter
+	}
+
+	v.lastSeen = time.Now()
+	return v.limiter
+}
+
+func (m *MemoryLimiter) cleanupLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			for key, v := range m.visitors {
+				if time.Since(v.lastSeen) > m.clientTimeout {
+					delete(m.visitors, key)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type APIKey struct {
+	ClientID string `yaml:"client_id"`
+	Plan     string `yaml:"plan"`
+}
+
+// Policy é carregada de configs/ratelimit.yaml.
+type Policy struct {
+	AnonymousPlan string `yaml:"anonymous_plan"` // Sem API key e sem token
+	DefaultPlan   string `yaml:"default_plan"`   // Autenticado via JWT
+	// IPPlan é o teto por IP, antes da autenticação, de quem apresenta credencial.
+	// Vazio: anonymous_plan.
+	IPPlan string `yaml:"ip_plan"`
+	// TenantPlan é a cota somada de todos os usuários autenticados de um tenant,
+	// aplicada além da cota por sujeito. Tenants sobrescreve o plano por tenant.
+	// Sem os dois, não há limite por tenant.
+	TenantPlan string                      `yaml:"tenant_plan"`
+	Tenants    map[string]string           `yaml:"tenants"` // tenant -> plano
+	Plans      map[string]Quota            `yaml:"plans"`
+	Routes     map[string]map[string]Quota `yaml:"routes"` // "METHOD /pattern" -> plano -> cota
+	// APIKeys é indexado pelo SHA-256 (hex) da chave; a chave em claro nunca fica no arquivo.
+	APIKeys        map[string]APIKey `yaml:"api_keys"`
+	TrustedProxies []string          `yaml:"trusted_proxies"`
+
+	trusted []*net.IPNet
+}
+
+type Identity struct {
+	Key  string
+	Plan string
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit config: %w", err)
+	}
+	var p Policy
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+	if err := p.init(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *Policy) init() error {
+	if p.IPPlan == "" {
+		p.IPPlan = p.AnonymousPlan
+	}
+	for _, plan := range []string{p.AnonymousPlan, p.DefaultPlan, p.IPPlan} {
+		if _, ok := p.Plans[plan]; !ok {
+			return fmt.Errorf("rate limit plan %q is not defined", plan)
+		}
+	}
+	if _, ok := p.Plans[p.TenantPlan]; p.TenantPlan != "" && !ok {
+		return fmt.Errorf("rate limit plan %q is not defined", p.TenantPlan)
+	}
+	for tenantID, plan := range p.Tenants {
+		if _, ok := p.Plans[plan]; !ok {
+			return fmt.Errorf("rate limit plan %q of tenant %q is not defined", plan, tenantID)
+		}
+	}
+	for name, q := range p.Plans {
+		if q.Rate <= 0 || q.Burst <= 0 || q.Period <= 0 {
+			return fmt.Errorf("rate limit plan %q must have positive rate, burst and period", name)
+		}
+	}
+	for _, cidr := range p.TrustedProxies {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		p.trusted = append(p.trusted, network)
+	}
+	return nil
+}
+
+// QuotaFor retorna a cota da rota para o plano, caindo para a cota geral do plano.
+func (p *Policy) QuotaFor(plan, route string) Quota {
+	if perPlan, ok := p.Routes[route]; ok {
+		if q, ok := perPlan[plan]; ok {
+			return q
+		}
+	}
+	if q, ok := p.Plans[plan]; ok {
+		return q
+	}
+	return p.Plans[p.AnonymousPlan]
+}
+
+// Identify resolve quem está sendo limitado: API key > sujeito autenticado > IP.
+func (p *Policy) Identify(r *http.Request, subject string) Identity {
+	if raw := r.Header.Get(APIKeyHeader); raw != "" {
+		sum := sha256.Sum256([]byte(raw))
+		if key, ok := p.APIKeys[hex.EncodeToString(sum[:])]; ok {
+			return Identity{Key: "key:" + key.ClientID, Plan: key.Plan}
+		}
+	}
+	if subject != "" {
+		return Identity{Key: "sub:" + subject, Plan: p.DefaultPlan}
+	}
+	return Identity{Key: "ip:" + p.ClientIP(r), Plan: p.AnonymousPlan}
+}
+
+// IdentifyTenant resolve o balde compartilhado por todos os usuários do tenant.
+// ok é false quando o tenant não tem plano (nem tenant_plan configurado).
+func (p *Policy) IdentifyTenant(tenantID string) (Identity, bool) {
+	if tenantID == "" {
+		return Identity{}, false
+	}
+	plan, ok := p.Tenants[tenantID]
+	if !ok {
+		plan = p.TenantPlan
+	}
+	if plan == "" {
+		return Identity{}, false
+	}
+	return Identity{Key: "tenant:" + tenantID, Plan: plan}, true
+}
+
+// IdentifyIP resolve o limite por IP, aplicado antes da autenticação. Sem
+// credencial vale o plano anônimo; com credencial (ainda não verificada) vale o
+// teto ip_plan, que segura enxurradas de tokens inválidos sem estrangular
+// clientes legítimos atrás do mesmo NAT.
+func (p *Policy) IdentifyIP(r *http.Request) Identity {
+	key := "ip:" + p.ClientIP(r)
+	if r.Header.Get("Authorization") == "" && r.Header.Get(APIKeyHeader) == "" {
+		return Identity{Key: key, Plan: p.AnonymousPlan}
+	}
+	return Identity{Key: key + "/cred", Plan: p.IPPlan}
+}
+
+// ClientIP só confia em X-Forwarded-For quando a conexão vem de um proxy confiável,
+// e nesse caso usa o endereço mais à direita que não é um proxy confiável.
+func (p *Policy) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !p.isTrusted(host) {
+		return host
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" || net.ParseIP(hop) == nil {
+			continue
+		}
+		if !p.isTrusted(hop) {
+			return hop
+		}
+	}
+	return host
+}
+
+func (p *Policy) isTrusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range p.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
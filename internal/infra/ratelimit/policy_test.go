@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPolicy(t *testing.T) *Policy {
+	sum := sha256.Sum256([]byte("secret-key"))
+	p := &Policy{
+		AnonymousPlan: "anonymous",
+		DefaultPlan:   "standard",
+		TenantPlan:    "tenant",
+		Tenants:       map[string]string{"brand-vip": "tenant_vip"},
+		Plans: map[string]Quota{
+			"anonymous":  {Rate: 1, Burst: 2, Period: time.Second},
+			"standard":   {Rate: 10, Burst: 20, Period: time.Second},
+			"partner":    {Rate: 100, Burst: 200, Period: time.Second},
+			"tenant":     {Rate: 50, Burst: 100, Period: time.Second},
+			"tenant_vip": {Rate: 500, Burst: 1000, Period: time.Second},
+		},
+		Routes: map[string]map[string]Quota{
+			"POST /api/v1/orders": {"standard": {Rate: 5, Burst: 10, Period: time.Second}},
+		},
+		APIKeys:        map[string]APIKey{hex.EncodeToString(sum[:]): {ClientID: "merchant-1", Plan: "partner"}},
+		TrustedProxies: []string{"10.0.0.0/8"},
+	}
+	require.NoError(t, p.init())
+	return p
+}
+
+func TestPolicy_ClientIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	p := testPolicy(t)
+
+	direct := httptest.NewRequest("GET", "/", nil)
+	direct.RemoteAddr = "203.0.113.7:5555"
+	direct.Header.Set("X-Forwarded-For", "1.2.3.4")
+	assert.Equal(t, "203.0.113.7", p.ClientIP(direct))
+
+	proxied := httptest.NewRequest("GET", "/", nil)
+	proxied.RemoteAddr = "10.0.0.2:5555"
+	proxied.Header.Set("X-Forwarded-For", "1.2.3.4, 198.51.100.9, 10.0.0.3")
+	assert.Equal(t, "198.51.100.9", p.ClientIP(proxied))
+}
+
+func TestPolicy_IdentifyAndQuota(t *testing.T) {
+	p := testPolicy(t)
+
+	r := httptest.NewRequest("POST", "/api/v1/orders", nil)
+	r.Header.Set(APIKeyHeader, "secret-key")
+	assert.Equal(t, Identity{Key: "key:merchant-1", Plan: "partner"}, p.Identify(r, "cust-1"))
+
+	r.Header.Set(APIKeyHeader, "unknown-key")
+	id := p.Identify(r, "cust-1")
+	assert.Equal(t, Identity{Key: "sub:cust-1", Plan: "standard"}, id)
+
+	assert.Equal(t, 10, p.QuotaFor("standard", "POST /api/v1/orders").Burst)
+	assert.Equal(t, 200, p.QuotaFor("partner", "POST /api/v1/orders").Burst)
+	assert.Equal(t, 2, p.QuotaFor("missing", "GET /x").Burst)
+}
+
+func TestPolicy_IdentifyTenant(t *testing.T) {
+	p := testPolicy(t)
+
+	tests := []struct {
+		name     string
+		tenantID string
+		want     Identity
+		ok       bool
+	}{
+		{"default tenant plan", "brand-a", Identity{Key: "tenant:brand-a", Plan: "tenant"}, true},
+		{"per-tenant plan", "brand-vip", Identity{Key: "tenant:brand-vip", Plan: "tenant_vip"}, true},
+		{"no tenant", "", Identity{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := p.IdentifyTenant(tt.tenantID)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+
+	// Sem tenant_plan, só tenants listados têm balde próprio.
+	p.TenantPlan = ""
+	_, ok := p.IdentifyTenant("brand-a")
+	assert.False(t, ok)
+	_, ok = p.IdentifyTenant("brand-vip")
+	assert.True(t, ok)
+}
+
+func TestPolicy_RejectsUndefinedTenantPlan(t *testing.T) {
+	p := &Policy{
+		AnonymousPlan: "anonymous",
+		DefaultPlan:   "anonymous",
+		Tenants:       map[string]string{"brand-a": "missing"},
+		Plans:         map[string]Quota{"anonymous": {Rate: 1, Burst: 1, Period: time.Second}},
+	}
+	assert.ErrorContains(t, p.init(), `"missing"`)
+}
+
+func TestPolicy_IdentifyIP(t *testing.T) {
+	p := testPolicy(t)
+
+	r := httptest.NewRequest("GET", "/health", nil)
+	r.RemoteAddr = "203.0.113.7:5555"
+	assert.Equal(t, Identity{Key: "ip:203.0.113.7", Plan: "anonymous"}, p.IdentifyIP(r))
+
+	// Sem ip_plan configurado, quem manda credencial cai no plano anônimo.
+	r.Header.Set("Authorization", "Bearer not-a-jwt")
+	assert.Equal(t, Identity{Key: "ip:203.0.113.7/cred", Plan: "anonymous"}, p.IdentifyIP(r))
+
+	p.IPPlan = "partner"
+	assert.Equal(t, Identity{Key: "ip:203.0.113.7/cred", Plan: "partner"}, p.IdentifyIP(r))
+}
+
+func TestMemoryLimiter_Burst(t *testing.T) {
+	m := NewMemoryLimiter(context.Background(), time.Minute, time.Minute)
+	q := Quota{Rate: 1, Burst: 2, Period: time.Minute}
+
+	first, _ := m.Allow(context.Background(), "k", q)
+	second, _ := m.Allow(context.Background(), "k", q)
+	third, _ := m.Allow(context.Background(), "k", q)
+
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.False(t, third.Allowed)
+	assert.Greater(t, third.RetryAfter, time.Duration(0))
+}
+
+func TestLoadPolicy_DefaultConfig(t *testing.T) {
+	p, err := LoadPolicy("../../../configs/ratelimit.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, p.Plans[p.DefaultPlan].Period)
+	assert.NotEmpty(t, p.Routes["POST /api/v1/orders"])
+	assert.Contains(t, p.Plans, p.IPPlan)
+	assert.Contains(t, p.Plans, p.TenantPlan)
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript implementa o Generic Cell Rate Algorithm de forma atômica.
+// O relógio é o do próprio Redis (TIME), evitando skew entre réplicas da API.
+//
+// KEYS[1] = chave do cliente
+// ARGV[1] = emission interval (µs)
+// ARGV[2] = burst
+//
+// Retorno: {allowed, remaining, retry_after_us, reset_after_us}
+var gcraScript = redis.NewScript(`
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tolerance = emission * burst
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - tolerance
+if now < allow_at then
+  return {0, 0, allow_at - now, tat - now}
+end
+
+local reset_after = new_tat - now
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil(reset_after / 1000))
+local remaining = math.floor((tolerance - reset_after) / emission)
+return {1, remaining, 0, reset_after}
+`)
+
+type RedisLimiter struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: "ratelimit:"}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, quota Quota) (Result, error) {
+	emission := quota.EmissionInterval().Microseconds()
+	values, err := gcraScript.Run(ctx, l.client, []string{l.prefix + key}, emission, quota.Burst).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("redis gcra failed: %w", err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected gcra reply: %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      quota.Burst,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
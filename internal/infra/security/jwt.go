@@ -0,0 +1,101 @@
+package security
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoVerificationKey = errors.New("no jwt verification key configured")
+	ErrInvalidRole       = errors.New("token has an invalid role claim")
+)
+
+type VerifierConfig struct {
+	HS256Secret string // Segredo compartilhado (HS256). Vazio desabilita.
+	JWKSFile    string // JWKS local com chaves públicas (RS256). Vazio desabilita.
+	Issuer      string
+	Audience    string
+}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Role     string `json:"role"`
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+type JWTVerifier struct {
+	secret  []byte
+	rsaKeys map[string]*rsa.PublicKey
+	parser  *jwt.Parser
+}
+
+func NewJWTVerifier(cfg VerifierConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{}
+	var methods []string
+
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, ErrNoVerificationKey
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+func (v *JWTVerifier) Verify(tokenString string) (auth.Principal, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(tokenString, &claims, v.keyFunc); err != nil {
+		return auth.Principal{}, err
+	}
+
+	role := auth.Role(claims.Role)
+	if !role.Valid() {
+		return auth.Principal{}, fmt.Errorf("%w: %q", ErrInvalidRole, claims.Role)
+	}
+	if claims.Subject == "" {
+		return auth.Principal{}, errors.New("token has no subject")
+	}
+
+	return auth.Principal{Subject: claims.Subject, Role: role, TenantID: claims.TenantID}, nil
+}
+
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.rsaKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
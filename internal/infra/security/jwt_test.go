@@ -0,0 +1,99 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func claims(sub, role string, exp time.Time) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   sub,
+			Issuer:    "gofleet",
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		Role:     role,
+		TenantID: "brand-a",
+	}
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	v, err := NewJWTVerifier(VerifierConfig{HS256Secret: "s3cr3t", Issuer: "gofleet"})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims("cust-1", "customer", time.Now().Add(time.Hour))).
+		SignedString([]byte("s3cr3t"))
+	require.NoError(t, err)
+
+	p, err := v.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "cust-1", Role: auth.RoleCustomer, TenantID: "brand-a"}, p)
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims("cust-1", "customer", time.Now().Add(-time.Minute))).
+			SignedString([]byte("s3cr3t"))
+		_, err := v.Verify(expired)
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("rejects unknown roles", func(t *testing.T) {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims("x", "root", time.Now().Add(time.Hour))).
+			SignedString([]byte("s3cr3t"))
+		_, err := v.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidRole)
+	})
+
+	t.Run("rejects wrong secret", func(t *testing.T) {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims("x", "admin", time.Now().Add(time.Hour))).
+			SignedString([]byte("other"))
+		_, err := v.Verify(token)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTVerifier_RS256WithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	set := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	raw, _ := json.Marshal(set)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+
+	v, err := NewJWTVerifier(VerifierConfig{JWKSFile: path})
+	require.NoError(t, err)
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims("drv-7", "driver", time.Now().Add(time.Hour)))
+	tok.Header["kid"] = "k1"
+	signed, err := tok.SignedString(key)
+	require.NoError(t, err)
+
+	p, err := v.Verify(signed)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleDriver, p.Role)
+
+	// HS256 não está habilitado: um token HMAC deve ser recusado (algorithm confusion).
+	hmac, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims("drv-7", "admin", time.Now().Add(time.Hour))).
+		SignedString([]byte("k1"))
+	_, err = v.Verify(hmac)
+	assert.Error(t, err)
+}
@@ -2,21 +2,40 @@ package handler
 
 import (
 	"encoding/json"
+	"net/http"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
 	"github.com/DioGolang/GoFleet/internal/application/usecase/order"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
 	"github.com/DioGolang/GoFleet/pkg/logger"
-	"net/http"
+	"github.com/go-chi/chi/v5"
 )
 
 type Order struct {
-	EventService       any
-	CreateOrderUseCase order.CreateUseCase
-	Logger             logger.Logger
+	EventService        any
+	CreateOrderUseCase  order.CreateUseCase
+	GetOrderUseCase     order.GetUseCase
+	CancelOrderUseCase  order.CancelUseCase
+	DeliverOrderUseCase order.DeliverUseCase
+	AssignOrderUseCase  order.AssignUseCase
+	Logger              logger.Logger
 }
 
-func NewOrderHandler(uc order.CreateUseCase, l logger.Logger) *Order {
+func NewOrderHandler(
+	create order.CreateUseCase,
+	get order.GetUseCase,
+	cancel order.CancelUseCase,
+	deliver order.DeliverUseCase,
+	assign order.AssignUseCase,
+	l logger.Logger,
+) *Order {
 	return &Order{
-		CreateOrderUseCase: uc,
-		Logger:             l,
+		CreateOrderUseCase:  create,
+		GetOrderUseCase:     get,
+		CancelOrderUseCase:  cancel,
+		DeliverOrderUseCase: deliver,
+		AssignOrderUseCase:  assign,
+		Logger:              l,
 	}
 }
 
@@ -26,7 +45,8 @@ func (h *Order) Create(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewDecoder(r.Body).Decode(&dto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, problem.New(r, http.StatusBadRequest, "malformed_body", "request body is not valid JSON"))
+		return
 	}
 
 	h.Logger.Info(ctx, "creating new order",
@@ -36,17 +56,65 @@ func (h *Order) Create(w http.ResponseWriter, r *http.Request) {
 
 	output, err := h.CreateOrderUseCase.Execute(r.Context(), dto)
 	if err != nil {
-		h.Logger.Error(ctx, "order creation failed",
-			logger.WithError(err),
-			logger.String("order_id", dto.ID),
-		)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if apperror.KindOf(err) == apperror.KindValidation {
+			h.Logger.Warn(ctx, "order rejected by validation",
+				logger.WithError(err),
+				logger.String("order_id", dto.ID),
+			)
+		} else {
+			h.Logger.Error(ctx, "order creation failed",
+				logger.WithError(err),
+				logger.String("order_id", dto.ID),
+			)
+		}
+		problem.WriteError(w, r, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		h.Logger.Error(ctx, "failed to encode response", logger.WithError(err))
+	}
+}
+
+func (h *Order) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.GetOrderUseCase.Execute(r.Context(), order.GetInput{OrderID: chi.URLParam(r, "id")})
+	h.respond(w, r, http.StatusOK, output, err)
+}
+
+func (h *Order) Cancel(w http.ResponseWriter, r *http.Request) {
+	output, err := h.CancelOrderUseCase.Execute(r.Context(), order.CancelInput{OrderID: chi.URLParam(r, "id")})
+	h.respond(w, r, http.StatusOK, output, err)
+}
 
-	err = json.NewEncoder(w).Encode(output)
+func (h *Order) Deliver(w http.ResponseWriter, r *http.Request) {
+	output, err := h.DeliverOrderUseCase.Execute(r.Context(), order.DeliverInput{OrderID: chi.URLParam(r, "id")})
+	h.respond(w, r, http.StatusOK, output, err)
+}
+
+func (h *Order) Assign(w http.ResponseWriter, r *http.Request) {
+	var input order.AssignInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, problem.New(r, http.StatusBadRequest, "malformed_body", "request body is not valid JSON"))
+		return
+	}
+	input.OrderID = chi.URLParam(r, "id")
+
+	output, err := h.AssignOrderUseCase.Execute(r.Context(), input)
+	h.respond(w, r, http.StatusOK, output, err)
+}
+
+func (h *Order) respond(w http.ResponseWriter, r *http.Request, status int, output any, err error) {
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		h.Logger.Error(r.Context(), "failed to encode response", logger.WithError(err))
 	}
 }
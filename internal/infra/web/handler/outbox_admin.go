@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/DioGolang/GoFleet/internal/infra/outbox"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultOutboxListLimit = 50
+	maxOutboxListLimit     = 500
+)
+
+// OutboxOperator é o que o admin precisa do relay.
+type OutboxOperator interface {
+	ListEvents(ctx context.Context, status string, limit int32) ([]outbox.EventSummary, error)
+	GetEvent(ctx context.Context, id uuid.UUID) (outbox.EventDetail, error)
+	Requeue(ctx context.Context, ids []uuid.UUID, all bool) (int64, error)
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+	State(ctx context.Context) (outbox.State, error)
+}
+
+// OutboxAdmin expõe operações manuais sobre o outbox para admins da plataforma.
+type OutboxAdmin struct {
+	Operator OutboxOperator
+	Logger   logger.Logger
+}
+
+func NewOutboxAdminHandler(op OutboxOperator, l logger.Logger) *OutboxAdmin {
+	return &OutboxAdmin{Operator: op, Logger: l}
+}
+
+type requeueRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+	All bool        `json:"all"`
+}
+
+type requeueResponse struct {
+	Requeued int64 `json:"requeued"`
+}
+
+type listResponse struct {
+	Events []outbox.EventSummary `json:"events"`
+}
+
+// List lista eventos de um status (?status=DEAD&limit=50), do mais antigo ao mais novo.
+func (h *OutboxAdmin) List(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if !slices.Contains(outbox.Statuses, status) {
+		problem.Write(w, problem.New(r, http.StatusUnprocessableEntity, "invalid_status", "status must be one of PENDING, PROCESSING, PUBLISHED, FAILED, DEAD"))
+		return
+	}
+
+	limit := defaultOutboxListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxOutboxListLimit {
+			problem.Write(w, problem.New(r, http.StatusUnprocessableEntity, "invalid_limit", "limit must be between 1 and 500"))
+			return
+		}
+		limit = n
+	}
+
+	evts, err := h.Operator.ListEvents(r.Context(), status, int32(limit))
+	if err != nil {
+		h.Logger.Error(r.Context(), "Failed to list outbox events", logger.WithError(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+	if evts == nil {
+		evts = []outbox.EventSummary{}
+	}
+	h.writeJSON(w, r, listResponse{Events: evts})
+}
+
+// Get devolve um evento com payload e error_msg.
+func (h *OutboxAdmin) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, problem.New(r, http.StatusUnprocessableEntity, "invalid_id", "id must be a UUID"))
+		return
+	}
+
+	evt, err := h.Operator.GetEvent(r.Context(), id)
+	if errors.Is(err, outbox.ErrEventNotFound) {
+		problem.Write(w, problem.New(r, http.StatusNotFound, "outbox_event_not_found", "outbox event not found"))
+		return
+	}
+	if err != nil {
+		h.Logger.Error(r.Context(), "Failed to get outbox event", logger.WithError(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+	h.writeJSON(w, r, evt)
+}
+
+// Requeue devolve eventos FAILED/DEAD para PENDING. Exige ids ou {"all": true}.
+func (h *OutboxAdmin) Requeue(w http.ResponseWriter, r *http.Request) {
+	var req requeueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		problem.Write(w, problem.New(r, http.StatusBadRequest, "malformed_body", `body must be {"ids": [uuid, ...]} or {"all": true}`))
+		return
+	}
+
+	n, err := h.Operator.Requeue(r.Context(), req.IDs, req.All)
+	if errors.Is(err, outbox.ErrEmptySelection) {
+		problem.Write(w, problem.New(r, http.StatusUnprocessableEntity, "empty_selection", err.Error()))
+		return
+	}
+	if err != nil {
+		h.Logger.Error(r.Context(), "Failed to requeue outbox events", logger.WithError(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	h.Logger.Info(r.Context(), "Failed outbox events requeued",
+		logger.Int("requested", len(req.IDs)),
+		logger.Any("all", req.All),
+		logger.Int("requeued", int(n)),
+	)
+	h.writeJSON(w, r, requeueResponse{Requeued: n})
+}
+
+// Pause suspende a publicação em todas as réplicas do relay.
+func (h *OutboxAdmin) Pause(w http.ResponseWriter, r *http.Request) {
+	if err := h.Operator.Pause(r.Context()); err != nil {
+		h.Logger.Error(r.Context(), "Failed to pause outbox publishing", logger.WithError(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+	h.Logger.Warn(r.Context(), "Outbox publishing paused")
+	h.State(w, r)
+}
+
+func (h *OutboxAdmin) Resume(w http.ResponseWriter, r *http.Request) {
+	if err := h.Operator.Resume(r.Context()); err != nil {
+		h.Logger.Error(r.Context(), "Failed to resume outbox publishing", logger.WithError(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+	h.Logger.Info(r.Context(), "Outbox publishing resumed")
+	h.State(w, r)
+}
+
+// State informa owner e liderança desta réplica e a pausa compartilhada.
+func (h *OutboxAdmin) State(w http.ResponseWriter, r *http.Request) {
+	state, err := h.Operator.State(r.Context())
+	if err != nil {
+		h.Logger.Error(r.Context(), "Failed to read outbox state", logger.WithError(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+	h.writeJSON(w, r, state)
+}
+
+func (h *OutboxAdmin) writeJSON(w http.ResponseWriter, r *http.Request, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.Logger.Error(r.Context(), "failed to encode response", logger.WithError(err))
+	}
+}
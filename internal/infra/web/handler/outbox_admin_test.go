@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/infra/outbox"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOutboxOperator struct {
+	paused     bool
+	err        error
+	listStatus string
+	listLimit  int32
+	requeued   int
+}
+
+func (f *fakeOutboxOperator) ListEvents(_ context.Context, status string, limit int32) ([]outbox.EventSummary, error) {
+	f.listStatus, f.listLimit = status, limit
+	return nil, nil
+}
+
+func (f *fakeOutboxOperator) GetEvent(context.Context, uuid.UUID) (outbox.EventDetail, error) {
+	return outbox.EventDetail{}, outbox.ErrEventNotFound
+}
+
+func (f *fakeOutboxOperator) Requeue(_ context.Context, ids []uuid.UUID, all bool) (int64, error) {
+	if len(ids) == 0 && !all {
+		return 0, outbox.ErrEmptySelection
+	}
+	f.requeued = len(ids)
+	if all {
+		f.requeued = 7
+	}
+	return int64(f.requeued), nil
+}
+
+func (f *fakeOutboxOperator) Pause(context.Context) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.paused = true
+	return nil
+}
+
+func (f *fakeOutboxOperator) Resume(context.Context) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.paused = false
+	return nil
+}
+
+func (f *fakeOutboxOperator) State(context.Context) (outbox.State, error) {
+	return outbox.State{Paused: f.paused}, f.err
+}
+
+func newAdminRouter(op OutboxOperator) http.Handler {
+	h := NewOutboxAdminHandler(op, logger.NewZapLogger("test", false))
+	r := chi.NewRouter()
+	r.Get("/events", h.List)
+	r.Get("/events/{id}", h.Get)
+	r.Post("/requeue", h.Requeue)
+	r.Post("/pause", h.Pause)
+	return r
+}
+
+func TestOutboxAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+		check  func(t *testing.T, op *fakeOutboxOperator, body string)
+	}{
+		{
+			name: "list uses default limit", method: http.MethodGet, target: "/events?status=DEAD", want: http.StatusOK,
+			check: func(t *testing.T, op *fakeOutboxOperator, body string) {
+				assert.Equal(t, "DEAD", op.listStatus)
+				assert.EqualValues(t, 50, op.listLimit)
+				assert.JSONEq(t, `{"events":[]}`, body)
+			},
+		},
+		{name: "list rejects unknown status", method: http.MethodGet, target: "/events?status=LOST", want: http.StatusUnprocessableEntity},
+		{name: "list rejects oversized limit", method: http.MethodGet, target: "/events?status=DEAD&limit=1000", want: http.StatusUnprocessableEntity},
+		{name: "get rejects invalid id", method: http.MethodGet, target: "/events/abc", want: http.StatusUnprocessableEntity},
+		{name: "get maps not found", method: http.MethodGet, target: "/events/" + uuid.NewString(), want: http.StatusNotFound},
+		{name: "requeue rejects empty body", method: http.MethodPost, target: "/requeue", want: http.StatusUnprocessableEntity},
+		{name: "requeue rejects empty ids", method: http.MethodPost, target: "/requeue", body: `{"ids": []}`, want: http.StatusUnprocessableEntity},
+		{
+			name: "requeue selected ids", method: http.MethodPost, target: "/requeue", body: `{"ids": ["` + uuid.NewString() + `"]}`, want: http.StatusOK,
+			check: func(t *testing.T, op *fakeOutboxOperator, body string) {
+				assert.Equal(t, 1, op.requeued)
+				assert.JSONEq(t, `{"requeued":1}`, body)
+			},
+		},
+		{
+			name: "requeue all is explicit", method: http.MethodPost, target: "/requeue", body: `{"all": true}`, want: http.StatusOK,
+			check: func(t *testing.T, op *fakeOutboxOperator, body string) {
+				assert.JSONEq(t, `{"requeued":7}`, body)
+			},
+		},
+		{
+			name: "pause reports state", method: http.MethodPost, target: "/pause", want: http.StatusOK,
+			check: func(t *testing.T, op *fakeOutboxOperator, body string) {
+				assert.True(t, op.paused)
+				assert.JSONEq(t, `{"owner":"","leader":false,"paused":true}`, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &fakeOutboxOperator{}
+			rec := httptest.NewRecorder()
+			newAdminRouter(op).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.check != nil {
+				tt.check(t, op, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestOutboxAdmin_PauseFailsWhenControlIsUnavailable(t *testing.T) {
+	op := &fakeOutboxOperator{err: errors.New("connection refused")}
+	rec := httptest.NewRecorder()
+	newAdminRouter(op).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pause", nil))
+
+	// Sem gravar em outbox_control, as outras réplicas continuariam publicando.
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.False(t, op.paused)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/parking"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultParkingListLimit = 50
+	maxParkingListLimit     = 500
+)
+
+// ParkingOperator é o que o admin precisa das filas de Parking.
+type ParkingOperator interface {
+	List(ctx context.Context, queue string, f parking.Filter, limit int) ([]parking.Message, error)
+	Get(ctx context.Context, queue, id string) (parking.MessageDetail, error)
+	Replay(ctx context.Context, queue string, f parking.Filter, actor parking.Actor) (parking.Result, error)
+	Purge(ctx context.Context, queue string, f parking.Filter, actor parking.Actor) (parking.Result, error)
+}
+
+// ParkingAdmin expõe inspeção, replay e purge das filas de Parking do Worker.
+// {queue} é a fila de trabalho (ex: orders.created), não a .parking.
+type ParkingAdmin struct {
+	Operator ParkingOperator
+	Logger   logger.Logger
+}
+
+func NewParkingAdminHandler(op ParkingOperator, l logger.Logger) *ParkingAdmin {
+	return &ParkingAdmin{Operator: op, Logger: l}
+}
+
+type parkingListResponse struct {
+	Messages []parking.Message `json:"messages"`
+}
+
+// List lista mensagens estacionadas (?reason=...&event_type=...&limit=50).
+func (h *ParkingAdmin) List(w http.ResponseWriter, r *http.Request) {
+	limit := defaultParkingListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxParkingListLimit {
+			problem.Write(w, problem.New(r, http.StatusUnprocessableEntity, "invalid_limit", "limit must be between 1 and 500"))
+			return
+		}
+		limit = n
+	}
+
+	f := parking.Filter{
+		Reason:    r.URL.Query().Get("reason"),
+		EventType: r.URL.Query().Get("event_type"),
+	}
+	msgs, err := h.Operator.List(r.Context(), chi.URLParam(r, "queue"), f, limit)
+	if err != nil {
+		h.writeError(w, r, "Failed to list parked messages", err)
+		return
+	}
+	h.writeJSON(w, r, parkingListResponse{Messages: msgs})
+}
+
+// Get devolve uma mensagem com headers e corpo.
+func (h *ParkingAdmin) Get(w http.ResponseWriter, r *http.Request) {
+	msg, err := h.Operator.Get(r.Context(), chi.URLParam(r, "queue"), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeError(w, r, "Failed to get parked message", err)
+		return
+	}
+	h.writeJSON(w, r, msg)
+}
+
+// Replay devolve as mensagens selecionadas à fila original.
+// Corpo: {"ids": [...]}, {"reason": "...", "event_type": "..."} ou {"all": true}.
+func (h *ParkingAdmin) Replay(w http.ResponseWriter, r *http.Request) {
+	f, ok := h.decodeFilter(w, r)
+	if !ok {
+		return
+	}
+	res, err := h.Operator.Replay(r.Context(), chi.URLParam(r, "queue"), f, actorFrom(r.Context()))
+	if err != nil {
+		h.writeError(w, r, "Failed to replay parked messages", err)
+		return
+	}
+
+	h.Logger.Info(r.Context(), "Parked messages replayed",
+		logger.String("queue", chi.URLParam(r, "queue")),
+		logger.Int("matched", res.Matched),
+		logger.Int("replayed", res.Replayed),
+		logger.Int("failed", len(res.Failed)),
+	)
+	h.writeJSON(w, r, res)
+}
+
+// Purge descarta as mensagens selecionadas. Mesmo corpo do replay.
+func (h *ParkingAdmin) Purge(w http.ResponseWriter, r *http.Request) {
+	f, ok := h.decodeFilter(w, r)
+	if !ok {
+		return
+	}
+	res, err := h.Operator.Purge(r.Context(), chi.URLParam(r, "queue"), f, actorFrom(r.Context()))
+	if err != nil {
+		h.writeError(w, r, "Failed to purge parked messages", err)
+		return
+	}
+
+	h.Logger.Warn(r.Context(), "Parked messages purged",
+		logger.String("queue", chi.URLParam(r, "queue")),
+		logger.Int("purged", res.Purged),
+	)
+	h.writeJSON(w, r, res)
+}
+
+func (h *ParkingAdmin) decodeFilter(w http.ResponseWriter, r *http.Request) (parking.Filter, bool) {
+	var f parking.Filter
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		problem.Write(w, problem.New(r, http.StatusBadRequest, "malformed_body", `body must be {"ids": [...]}, a reason/event_type filter or {"all": true}`))
+		return f, false
+	}
+	if f.Empty() {
+		problem.Write(w, problem.New(r, http.StatusUnprocessableEntity, "empty_selection", parking.ErrEmptySelection.Error()))
+		return f, false
+	}
+	return f, true
+}
+
+func (h *ParkingAdmin) writeError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, parking.ErrQueueNotFound):
+		problem.Write(w, problem.New(r, http.StatusNotFound, "parking_queue_not_found", "parking queue not found"))
+	case errors.Is(err, parking.ErrMessageNotFound):
+		problem.Write(w, problem.New(r, http.StatusNotFound, "parked_message_not_found", "parked message not found"))
+	default:
+		h.Logger.Error(r.Context(), msg, logger.WithError(err))
+		problem.WriteError(w, r, err)
+	}
+}
+
+func (h *ParkingAdmin) writeJSON(w http.ResponseWriter, r *http.Request, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.Logger.Error(r.Context(), "failed to encode response", logger.WithError(err))
+	}
+}
+
+// actorFrom identifica o operador para a auditoria.
+func actorFrom(ctx context.Context) parking.Actor {
+	p, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return parking.Actor{ID: "anonymous"}
+	}
+	return parking.Actor{ID: p.Subject, Role: string(p.Role)}
+}
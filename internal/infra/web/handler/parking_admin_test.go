@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/parking"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeParkingOperator struct {
+	queue  string
+	filter parking.Filter
+	limit  int
+	actor  parking.Actor
+}
+
+func (f *fakeParkingOperator) List(_ context.Context, queue string, flt parking.Filter, limit int) ([]parking.Message, error) {
+	f.queue, f.filter, f.limit = queue, flt, limit
+	if queue == "missing" {
+		return nil, parking.ErrQueueNotFound
+	}
+	return []parking.Message{}, nil
+}
+
+func (f *fakeParkingOperator) Get(context.Context, string, string) (parking.MessageDetail, error) {
+	return parking.MessageDetail{}, parking.ErrMessageNotFound
+}
+
+func (f *fakeParkingOperator) Replay(_ context.Context, queue string, flt parking.Filter, actor parking.Actor) (parking.Result, error) {
+	f.queue, f.filter, f.actor = queue, flt, actor
+	return parking.Result{Matched: 2, Replayed: 2}, nil
+}
+
+func (f *fakeParkingOperator) Purge(_ context.Context, queue string, flt parking.Filter, actor parking.Actor) (parking.Result, error) {
+	f.queue, f.filter, f.actor = queue, flt, actor
+	return parking.Result{Matched: 1, Purged: 1}, nil
+}
+
+func newParkingRouter(op ParkingOperator) http.Handler {
+	h := NewParkingAdminHandler(op, logger.NewZapLogger("test", false))
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := auth.WithPrincipal(r.Context(), auth.Principal{Subject: "ops-1", Role: auth.RoleAdmin})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Get("/{queue}/messages", h.List)
+	r.Get("/{queue}/messages/{id}", h.Get)
+	r.Post("/{queue}/replay", h.Replay)
+	r.Post("/{queue}/purge", h.Purge)
+	return r
+}
+
+func TestParkingAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+		check  func(t *testing.T, op *fakeParkingOperator, body string)
+	}{
+		{
+			name: "list passes filter and default limit", method: http.MethodGet,
+			target: "/orders.created/messages?reason=schema-violation", want: http.StatusOK,
+			check: func(t *testing.T, op *fakeParkingOperator, body string) {
+				assert.Equal(t, "orders.created", op.queue)
+				assert.Equal(t, "schema-violation", op.filter.Reason)
+				assert.Equal(t, 50, op.limit)
+				assert.JSONEq(t, `{"messages":[]}`, body)
+			},
+		},
+		{name: "list rejects oversized limit", method: http.MethodGet, target: "/orders.created/messages?limit=1000", want: http.StatusUnprocessableEntity},
+		{name: "list maps unknown queue", method: http.MethodGet, target: "/missing/messages", want: http.StatusNotFound},
+		{name: "get maps not found", method: http.MethodGet, target: "/orders.created/messages/evt-1", want: http.StatusNotFound},
+		{name: "replay rejects malformed body", method: http.MethodPost, target: "/orders.created/replay", body: `[`, want: http.StatusBadRequest},
+		{name: "replay requires a selection", method: http.MethodPost, target: "/orders.created/replay", body: `{}`, want: http.StatusUnprocessableEntity},
+		{
+			name: "replay records the actor", method: http.MethodPost, target: "/orders.created/replay",
+			body: `{"ids":["evt-1","evt-2"]}`, want: http.StatusOK,
+			check: func(t *testing.T, op *fakeParkingOperator, body string) {
+				assert.Equal(t, []string{"evt-1", "evt-2"}, op.filter.IDs)
+				assert.Equal(t, parking.Actor{ID: "ops-1", Role: "admin"}, op.actor)
+				assert.JSONEq(t, `{"matched":2,"replayed":2}`, body)
+			},
+		},
+		{
+			name: "purge all", method: http.MethodPost, target: "/orders.created/purge", body: `{"all":true}`, want: http.StatusOK,
+			check: func(t *testing.T, op *fakeParkingOperator, body string) {
+				assert.True(t, op.filter.All)
+				assert.JSONEq(t, `{"matched":1,"purged":1}`, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := &fakeParkingOperator{}
+			rec := httptest.NewRecorder()
+			newParkingRouter(op).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.check != nil {
+				tt.check(t, op, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+type TokenVerifier interface {
+	Verify(token string) (auth.Principal, error)
+}
+
+// Authenticate exige um Bearer token válido e coloca o Principal no contexto
+// (e nos campos de log de toda a requisição).
+func Authenticate(verifier TokenVerifier, log logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gofleet"`)
+				problem.WriteError(w, r, apperror.Unauthenticated("missing_token", "bearer token is required", nil))
+				return
+			}
+
+			principal, err := verifier.Verify(token)
+			if err != nil {
+				log.Warn(r.Context(), "Authentication failed",
+					logger.String("path", r.URL.Path),
+					logger.WithError(err),
+				)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gofleet", error="invalid_token"`)
+				problem.WriteError(w, r, apperror.Unauthenticated("invalid_token", "bearer token is invalid or expired", err))
+				return
+			}
+
+			ctx := auth.WithPrincipal(r.Context(), principal)
+			ctx = logger.ContextWithFields(ctx,
+				logger.String("principal_id", principal.Subject),
+				logger.String("principal_role", string(principal.Role)),
+			)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole é a checagem grossa por rota. Regras de posse (ex: "apenas os
+// próprios pedidos") ficam nos use cases.
+func RequireRole(roles ...auth.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFrom(r.Context())
+			if !ok {
+				problem.WriteError(w, r, apperror.Unauthenticated("missing_token", "bearer token is required", nil))
+				return
+			}
+			if !principal.HasRole(roles...) {
+				problem.WriteError(w, r, apperror.Forbidden("insufficient_role", "your role cannot perform this operation"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/security"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "s3cr3t"
+
+func signToken(t *testing.T, secret, sub, role string, exp time.Time) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, security.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   sub,
+			Issuer:    "gofleet",
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		Role:     role,
+		TenantID: "brand-a",
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthenticate(t *testing.T) {
+	verifier, err := security.NewJWTVerifier(security.VerifierConfig{HS256Secret: testJWTSecret, Issuer: "gofleet"})
+	require.NoError(t, err)
+
+	valid := signToken(t, testJWTSecret, "cust-1", "customer", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+		expectedCode   string
+		expectedAuth   string
+	}{
+		{"valid token", "Bearer " + valid, http.StatusOK, "", ""},
+		{"scheme is case insensitive", "bearer " + valid, http.StatusOK, "", ""},
+		{"missing header", "", http.StatusUnauthorized, "missing_token", `Bearer realm="gofleet"`},
+		{"wrong scheme", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, "missing_token", `Bearer realm="gofleet"`},
+		{"empty bearer", "Bearer   ", http.StatusUnauthorized, "missing_token", `Bearer realm="gofleet"`},
+		{"malformed token", "Bearer not-a-jwt", http.StatusUnauthorized, "invalid_token", `Bearer realm="gofleet", error="invalid_token"`},
+		{"wrong signature", "Bearer " + signToken(t, "other", "cust-1", "customer", time.Now().Add(time.Hour)), http.StatusUnauthorized, "invalid_token", `Bearer realm="gofleet", error="invalid_token"`},
+		{"expired token", "Bearer " + signToken(t, testJWTSecret, "cust-1", "customer", time.Now().Add(-time.Minute)), http.StatusUnauthorized, "invalid_token", `Bearer realm="gofleet", error="invalid_token"`},
+		{"unknown role", "Bearer " + signToken(t, testJWTSecret, "cust-1", "root", time.Now().Add(time.Hour)), http.StatusUnauthorized, "invalid_token", `Bearer realm="gofleet", error="invalid_token"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal auth.Principal
+			var called bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				principal, _ = auth.PrincipalFrom(r.Context())
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			Authenticate(verifier, logger.NewZapLogger("test", false))(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.True(t, called)
+				assert.Equal(t, auth.Principal{Subject: "cust-1", Role: auth.RoleCustomer, TenantID: "brand-a"}, principal)
+				return
+			}
+
+			assert.False(t, called)
+			assert.Equal(t, tt.expectedAuth, w.Header().Get("WWW-Authenticate"))
+			var body problem.Details
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.expectedCode, body.Code)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *auth.Principal
+		roles          []auth.Role
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"allowed role", &auth.Principal{Subject: "d1", Role: auth.RoleDriver}, []auth.Role{auth.RoleDriver}, http.StatusOK, ""},
+		{"one of several roles", &auth.Principal{Subject: "c1", Role: auth.RoleCustomer}, []auth.Role{auth.RoleCustomer, auth.RoleDispatcher}, http.StatusOK, ""},
+		{"admin always passes", &auth.Principal{Subject: "a1", Role: auth.RoleAdmin}, []auth.Role{auth.RoleDriver}, http.StatusOK, ""},
+		{"role rejected", &auth.Principal{Subject: "c1", Role: auth.RoleCustomer}, []auth.Role{auth.RoleDriver}, http.StatusForbidden, "insufficient_role"},
+		{"admin-only route", &auth.Principal{Subject: "dsp", Role: auth.RoleDispatcher}, []auth.Role{auth.RoleAdmin}, http.StatusForbidden, "insufficient_role"},
+		{"no principal", nil, []auth.Role{auth.RoleDriver}, http.StatusUnauthorized, "missing_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/deliver", nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), *tt.principal))
+			}
+			w := httptest.NewRecorder()
+
+			RequireRole(tt.roles...)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+			if tt.expectedCode != "" {
+				var body problem.Details
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Equal(t, tt.expectedCode, body.Code)
+			}
+		})
+	}
+}
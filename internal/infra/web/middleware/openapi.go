@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/DioGolang/GoFleet/internal/infra/web/openapi"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+)
+
+const maxRequestBodyBytes = 1 << 20 // 1 MiB
+
+// ValidateRequest rejeita requisições que não respeitam o contrato OpenAPI
+// antes de chegarem ao handler. Rotas fora da especificação (ex: /health) passam direto.
+func ValidateRequest(spec *openapi.Spec, log logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op, ok := spec.FindOperation(r.Method, r.URL.Path)
+			if !ok || op.RequestBody == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+			if err != nil {
+				problem.Write(w, problem.New(r, http.StatusRequestEntityTooLarge, "body_too_large", "request body exceeds 1 MiB"))
+				return
+			}
+			_ = r.Body.Close()
+
+			if err := spec.ValidateBody(op, body); err != nil {
+				log.Warn(r.Context(), "request rejected by OpenAPI validation",
+					logger.String("operation", op.OperationID),
+					logger.WithError(err),
+				)
+				if errors.Is(err, openapi.ErrMalformedBody) {
+					problem.Write(w, problem.New(r, http.StatusBadRequest, "malformed_body", "request body is not valid JSON"))
+					return
+				}
+				problem.WriteError(w, r, err)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,93 +1,126 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/ratelimit"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
 	"github.com/DioGolang/GoFleet/pkg/logger"
-	"golang.org/x/time/rate"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	"github.com/go-chi/chi/v5"
 )
 
-type RateLimiterConfig struct {
-	RequestsPerSecond int           // Quantos tokens entram por segundo (r)
-	Burst             int           // Tamanho máximo do balde (b)
-	CleanupInterval   time.Duration // Frequência de limpeza de IPs inativos
-	ClientTimeout     time.Duration // Tempo para considerar um IP inativo
-}
-
-type IPDispatcher struct {
-	mu       sync.Mutex
-	visitors map[string]*visitor
-	config   RateLimiterConfig
-}
-
-type visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
-
-func NewRateLimiter(conf RateLimiterConfig) *IPDispatcher {
-	d := &IPDispatcher{
-		visitors: make(map[string]*visitor),
-		config:   conf,
+// RateLimitByIP limita por IP antes de Authenticate e deve ser montado no
+// router inteiro: alcança quem não tem token (ou tem um inválido), /health e
+// /admin. Um balde por IP, sem cota por rota, já que aqui o chi ainda não
+// resolveu o padrão da rota.
+func RateLimitByIP(limiter ratelimit.Limiter, policy *ratelimit.Policy, log logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity := policy.IdentifyIP(r)
+			b := bucket{identity: identity, key: identity.Key, quota: policy.QuotaFor(identity.Plan, "")}
+			enforce(w, r, next, limiter, log, r.Method+" "+r.URL.Path, b)
+		})
 	}
-
-	go d.cleanupLoop()
-
-	return d
 }
 
-func (d *IPDispatcher) Handler(log logger.Logger) func(next http.Handler) http.Handler {
+// RateLimit aplica a cota do plano do cliente (API key, sujeito do token ou IP)
+// por rota e, para usuários autenticados, a cota compartilhada do tenant. Deve
+// rodar depois de Authenticate para enxergar o Principal.
+func RateLimit(limiter ratelimit.Limiter, policy *ratelimit.Policy, log logger.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := r.RemoteAddr
-			// Se estiver atrás de proxy (Nginx/Cloudflare), use X-Forwarded-For:
-			if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-				ip = forwarded
-			}
-
-			limiter := d.getVisitor(ip)
+			ctx := r.Context()
 
-			if !limiter.Allow() {
-				log.Warn(r.Context(), "Rate limit exceeded",
-					logger.String("ip", ip),
-					logger.String("path", r.URL.Path),
-				)
+			route := r.URL.Path
+			if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			route = r.Method + " " + route
+
+			var subject, tenantID string
+			if p, ok := auth.PrincipalFrom(ctx); ok {
+				subject = p.Subject
+				// O mesmo sub pode existir em tenants diferentes: cotas não se misturam.
+				if id, ok := tenant.FromContext(ctx); ok {
+					tenantID = id
+					subject = tenantID + "/" + subject
+				}
+			}
 
-				w.Header().Set("Retry-After", "1")
-				http.Error(w, "Too Many Requests - Slow down", http.StatusTooManyRequests)
-				return
+			identity := policy.Identify(r, subject)
+			buckets := []bucket{{identity: identity, key: identity.Key + ":" + route, quota: policy.QuotaFor(identity.Plan, route)}}
+			if t, ok := policy.IdentifyTenant(tenantID); ok {
+				buckets = append(buckets, bucket{identity: t, key: t.Key, quota: policy.QuotaFor(t.Plan, "")})
 			}
-			next.ServeHTTP(w, r)
+			enforce(w, r, next, limiter, log, route, buckets...)
 		})
 	}
 }
 
-func (d *IPDispatcher) cleanupLoop() {
-	ticker := time.NewTicker(d.config.CleanupInterval)
-	for range ticker.C {
-		d.mu.Lock()
-		for ip, v := range d.visitors {
-			if time.Since(v.lastSeen) > d.config.ClientTimeout {
-				delete(d.visitors, ip)
-			}
-		}
-		d.mu.Unlock()
-	}
+type bucket struct {
+	identity ratelimit.Identity
+	key      string
+	quota    ratelimit.Quota
 }
 
-func (d *IPDispatcher) getVisitor(ip string) *rate.Limiter {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+// enforce consome uma ficha de cada balde, em ordem, e responde 429 no primeiro
+// sem cota. Os cabeçalhos RateLimit-* descrevem o balde mais apertado.
+func enforce(
+	w http.ResponseWriter,
+	r *http.Request,
+	next http.Handler,
+	limiter ratelimit.Limiter,
+	log logger.Logger,
+	route string,
+	buckets ...bucket,
+) {
+	ctx := r.Context()
+
+	var tightest *ratelimit.Result
+	var tightestQuota ratelimit.Quota
+	for _, b := range buckets {
+		res, err := limiter.Allow(ctx, b.key, b.quota)
+		if err != nil {
+			// Nem o fallback respondeu: fail-open para não derrubar a API.
+			log.Error(ctx, "Rate limiter failed, allowing request", logger.WithError(err))
+			continue
+		}
 
-	v, exists := d.visitors[ip]
-	if !exists {
-		limiter := rate.NewLimiter(rate.Limit(d.config.RequestsPerSecond), d.config.Burst)
-		d.visitors[ip] = &visitor{limiter: limiter, lastSeen: time.Now()}
-		return limiter
+		if !res.Allowed {
+			log.Warn(ctx, "Rate limit exceeded",
+				logger.String("client", b.identity.Key),
+				logger.String("plan", b.identity.Plan),
+				logger.String("route", route),
+			)
+			writeRateLimitHeaders(w, res, b.quota)
+			w.Header().Set("Retry-After", seconds(res.RetryAfter))
+			problem.Write(w, problem.New(r, http.StatusTooManyRequests, "rate_limit_exceeded", "too many requests, slow down"))
+			return
+		}
+		if tightest == nil || res.Remaining < tightest.Remaining {
+			tightest, tightestQuota = &res, b.quota
+		}
+	}
+
+	if tightest != nil {
+		writeRateLimitHeaders(w, *tightest, tightestQuota)
 	}
+	next.ServeHTTP(w, r)
+}
+
+func writeRateLimitHeaders(w http.ResponseWriter, res ratelimit.Result, quota ratelimit.Quota) {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", seconds(res.ResetAfter))
+	h.Set("RateLimit-Policy", strconv.Itoa(quota.Burst)+";w="+seconds(quota.Period))
+}
 
-	v.lastSeen = time.Now()
-	return v.limiter
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
 }
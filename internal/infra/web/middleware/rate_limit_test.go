@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/ratelimit"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRateLimitPolicy = `
+anonymous_plan: anonymous
+default_plan: standard
+ip_plan: per_ip
+tenant_plan: tenant
+plans:
+  anonymous: { rate: 1, burst: 2, period: 1m }
+  standard:  { rate: 1, burst: 3, period: 1m }
+  per_ip:    { rate: 1, burst: 4, period: 1m }
+  tenant:    { rate: 1, burst: 5, period: 1m }
+`
+
+func loadTestPolicy(t *testing.T) *ratelimit.Policy {
+	path := filepath.Join(t.TempDir(), "ratelimit.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRateLimitPolicy), 0o600))
+	p, err := ratelimit.LoadPolicy(path)
+	require.NoError(t, err)
+	return p
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Quota) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis down")
+}
+
+// principalRouter monta RateLimit como no /api/v1: num grupo (o padrão da rota
+// já está resolvido) e depois de um Principal no contexto.
+func principalRouter(limiter ratelimit.Limiter, policy *ratelimit.Policy) http.Handler {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p := auth.Principal{Subject: "cust-1", Role: auth.RoleCustomer}
+				next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+			})
+		})
+		r.Use(RateLimit(limiter, policy, logger.NewZapLogger("test", false)))
+		r.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+	return r
+}
+
+// tenantRouter autentica cada request como o sub do header X-Test-Sub no tenant brand-a.
+func tenantRouter(limiter ratelimit.Limiter, policy *ratelimit.Policy) http.Handler {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p := auth.Principal{Subject: r.Header.Get("X-Test-Sub"), Role: auth.RoleCustomer, TenantID: "brand-a"}
+				ctx := tenant.WithTenant(auth.WithPrincipal(r.Context(), p), "brand-a")
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+		r.Use(RateLimit(limiter, policy, logger.NewZapLogger("test", false)))
+		r.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+	return r
+}
+
+func serve(h http.Handler, path, authorization string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.RemoteAddr = "203.0.113.7:5555"
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimit_HeadersAndTooManyRequests(t *testing.T) {
+	h := principalRouter(ratelimit.NewMemoryLimiter(context.Background(), time.Minute, time.Minute), loadTestPolicy(t))
+
+	for i, remaining := range []string{"2", "1", "0"} {
+		// O balde é por rota (padrão do chi), não por URL.
+		w := serve(h, "/orders/"+string(rune('a'+i)), "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, remaining, w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3;w=60", w.Header().Get("RateLimit-Policy"))
+		assert.NotEmpty(t, w.Header().Get("RateLimit-Reset"))
+	}
+
+	w := serve(h, "/orders/d", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var body problem.Details
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, http.StatusTooManyRequests, body.Status)
+	assert.Equal(t, "rate_limit_exceeded", body.Code)
+}
+
+func TestRateLimit_TenantQuotaIsSharedBySubjects(t *testing.T) {
+	h := tenantRouter(ratelimit.NewMemoryLimiter(context.Background(), time.Minute, time.Minute), loadTestPolicy(t))
+
+	as := func(sub string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+		r.Header.Set("X-Test-Sub", sub)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// Cada sub tem burst 3, mas o tenant inteiro tem burst 5.
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, as("cust-1").Code)
+	}
+	w := as("cust-2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	// Cabeçalhos do balde mais apertado: o do tenant, com 1 ficha.
+	assert.Equal(t, "5", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusOK, as("cust-3").Code)
+	w = as("cust-4")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5", w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_FallbackKeepsLimiting(t *testing.T) {
+	limiter := ratelimit.NewFallbackLimiter(
+		failingLimiter{},
+		ratelimit.NewMemoryLimiter(context.Background(), time.Minute, time.Minute),
+		10*time.Millisecond,
+		logger.NewZapLogger("test", false),
+	)
+	h := principalRouter(limiter, loadTestPolicy(t))
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serve(h, "/orders/1", "").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, "/orders/1", "").Code)
+}
+
+func TestRateLimit_FailsOpenWhenNoLimiterAnswers(t *testing.T) {
+	h := principalRouter(failingLimiter{}, loadTestPolicy(t))
+
+	for i := 0; i < 5; i++ {
+		w := serve(h, "/orders/1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitByIP_LimitsBeforeAuthentication(t *testing.T) {
+	policy := loadTestPolicy(t)
+	limiter := ratelimit.NewMemoryLimiter(context.Background(), time.Minute, time.Minute)
+
+	// Authenticate rejeita tudo: o limite por IP precisa agir mesmo assim.
+	r := chi.NewRouter()
+	r.Use(RateLimitByIP(limiter, policy, logger.NewZapLogger("test", false)))
+	r.Use(Authenticate(rejectingVerifier{}, logger.NewZapLogger("test", false)))
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	// Sem credencial: plano anônimo (burst 2).
+	assert.Equal(t, http.StatusUnauthorized, serve(r, "/health", "").Code)
+	w := serve(r, "/health", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(r, "/health", "").Code)
+
+	// Token inválido: teto por IP (burst 4), em balde separado.
+	for i := 0; i < 4; i++ {
+		w := serve(r, "/health", "Bearer forged")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "4", w.Header().Get("RateLimit-Limit"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve(r, "/health", "Bearer forged").Code)
+}
+
+type rejectingVerifier struct{}
+
+func (rejectingVerifier) Verify(string) (auth.Principal, error) {
+	return auth.Principal{}, errors.New("signature is invalid")
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/web/problem"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+)
+
+// TenantHeader permite que tokens da plataforma (sem claim tenant_id) escolham o tenant.
+const TenantHeader = "X-Tenant-ID"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ResolveTenant define o tenant da requisição. A claim do token sempre vence:
+// o header só é aceito quando bate com ela ou quando um admin da plataforma
+// (token sem tenant) precisa agir em nome de um tenant.
+func ResolveTenant(log logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(TenantHeader)
+			principal, _ := auth.PrincipalFrom(r.Context())
+
+			tenantID := principal.TenantID
+			switch {
+			case tenantID != "" && header != "" && header != tenantID:
+				log.Warn(r.Context(), "Cross-tenant request rejected",
+					logger.String("token_tenant", tenantID),
+					logger.String("header_tenant", header),
+				)
+				problem.WriteError(w, r, apperror.Forbidden("tenant_mismatch", "token does not grant access to the requested tenant"))
+				return
+			case tenantID == "" && header != "":
+				if principal.Role != auth.RoleAdmin {
+					problem.WriteError(w, r, apperror.Forbidden("tenant_header_not_allowed", "only platform admins may select a tenant by header"))
+					return
+				}
+				tenantID = header
+			}
+
+			if tenantID == "" {
+				problem.WriteError(w, r, apperror.Validation("tenant_required", "a tenant is required",
+					apperror.FieldError{Field: TenantHeader, Code: "required", Message: "tenant is required"}))
+				return
+			}
+			if !tenantIDPattern.MatchString(tenantID) {
+				problem.WriteError(w, r, apperror.Validation("tenant_invalid", "tenant id is invalid",
+					apperror.FieldError{Field: TenantHeader, Code: "pattern", Message: "tenant must match " + tenantIDPattern.String()}))
+				return
+			}
+
+			ctx := tenant.WithTenant(r.Context(), tenantID)
+			ctx = logger.ContextWithFields(ctx, logger.String("tenant_id", tenantID))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
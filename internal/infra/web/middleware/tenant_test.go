@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/pkg/logger"
+	"github.com/DioGolang/GoFleet/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTenant(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      auth.Principal
+		header         string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{"claim only", auth.Principal{Subject: "c1", Role: auth.RoleCustomer, TenantID: "brand-a"}, "", http.StatusOK, "brand-a"},
+		{"header matching claim", auth.Principal{Subject: "c1", Role: auth.RoleCustomer, TenantID: "brand-a"}, "brand-a", http.StatusOK, "brand-a"},
+		{"header for another tenant", auth.Principal{Subject: "c1", Role: auth.RoleCustomer, TenantID: "brand-a"}, "brand-b", http.StatusForbidden, ""},
+		{"admin with tenant claim cannot switch", auth.Principal{Subject: "a1", Role: auth.RoleAdmin, TenantID: "brand-a"}, "brand-b", http.StatusForbidden, ""},
+		{"platform admin selects tenant", auth.Principal{Subject: "a1", Role: auth.RoleAdmin}, "brand-b", http.StatusOK, "brand-b"},
+		{"non-admin without claim cannot select", auth.Principal{Subject: "d1", Role: auth.RoleDispatcher}, "brand-b", http.StatusForbidden, ""},
+		{"no tenant at all", auth.Principal{Subject: "a1", Role: auth.RoleAdmin}, "", http.StatusUnprocessableEntity, ""},
+		{"invalid tenant id", auth.Principal{Subject: "a1", Role: auth.RoleAdmin}, "../brand", http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolved string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resolved, _ = tenant.FromContext(r.Context())
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil)
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+			if tt.header != "" {
+				r.Header.Set(TenantHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			ResolveTenant(logger.NewZapLogger("test", false))(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedTenant, resolved)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+)
+
+var ErrMalformedBody = errors.New("request body is not valid JSON")
+
+// ValidateBody valida o corpo JSON contra o schema da operação.
+// Retorna ErrMalformedBody para JSON inválido ou um *apperror.Error de validação
+// com os detalhes por campo.
+func (s *Spec) ValidateBody(op *Operation, body []byte) error {
+	if op.RequestBody == nil {
+		return nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return apperror.Validation("body_required", "request body is required")
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedBody, err)
+	}
+	if dec.More() {
+		return ErrMalformedBody
+	}
+
+	var fields []apperror.FieldError
+	if err := s.validate(media.Schema, value, "", &fields); err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return apperror.Validation("request_validation_failed", "request does not match the API contract", fields...)
+	}
+	return nil
+}
+
+func (s *Spec) validate(schema *Schema, value any, path string, fields *[]apperror.FieldError) error {
+	schema, err := s.resolve(schema)
+	if err != nil || schema == nil {
+		return err
+	}
+
+	add := func(code, format string, args ...any) {
+		*fields = append(*fields, apperror.FieldError{Field: path, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			add("type", "must be an object")
+			return nil
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				*fields = append(*fields, apperror.FieldError{Field: join(path, name), Code: "required", Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, known := schema.Properties[name]
+			if !known {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					*fields = append(*fields, apperror.FieldError{Field: join(path, name), Code: "unknown_field", Message: "is not allowed"})
+				}
+				continue
+			}
+			if err := s.validate(prop, obj[name], join(path, name), fields); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			add("type", "must be an array")
+			return nil
+		}
+		for i, item := range arr {
+			if err := s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), fields); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			add("type", "must be a string")
+			return nil
+		}
+		length := utf8.RuneCountInString(str)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			add("min_length", "must have at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			add("max_length", "must have at most %d characters", *schema.MaxLength)
+		}
+
+	case "number", "integer":
+		num, ok := value.(json.Number)
+		if !ok {
+			add("type", "must be a %s", schema.Type)
+			return nil
+		}
+		if schema.Type == "integer" {
+			if _, err := num.Int64(); err != nil {
+				add("type", "must be an integer")
+				return nil
+			}
+		}
+		f, err := num.Float64()
+		if err != nil {
+			add("type", "must be a %s", schema.Type)
+			return nil
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			add("minimum", "must be greater than or equal to %v", *schema.Minimum)
+		}
+		if schema.ExclusiveMinimum != nil && f <= *schema.ExclusiveMinimum {
+			add("exclusive_minimum", "must be greater than %v", *schema.ExclusiveMinimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			add("maximum", "must be less than or equal to %v", *schema.Maximum)
+		}
+		if schema.ExclusiveMaximum != nil && f >= *schema.ExclusiveMaximum {
+			add("exclusive_maximum", "must be less than %v", *schema.ExclusiveMaximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			add("type", "must be a boolean")
+		}
+	}
+	return nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBody_CreateOrder(t *testing.T) {
+	spec, err := Load()
+	require.NoError(t, err)
+
+	op, ok := spec.FindOperation("POST", "/api/v1/orders")
+	require.True(t, ok)
+
+	tests := []struct {
+		name          string
+		body          string
+		expectedField string
+		expectedCode  string
+	}{
+		{"missing price", `{"id":"1","tax":1}`, "price", "required"},
+		{"price zero", `{"id":"1","price":0,"tax":1}`, "price", "exclusive_minimum"},
+		{"negative tax", `{"id":"1","price":10,"tax":-1}`, "tax", "minimum"},
+		{"price as string", `{"id":"1","price":"10","tax":1}`, "price", "type"},
+		{"empty id", `{"id":"","price":10,"tax":1}`, "id", "min_length"},
+		{"unknown field", `{"id":"1","price":10,"tax":1,"discount":5}`, "discount", "unknown_field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := spec.ValidateBody(op, []byte(tt.body))
+
+			appErr := apperror.As(err)
+			require.NotNil(t, appErr)
+			assert.Equal(t, apperror.KindValidation, appErr.Kind)
+			require.Len(t, appErr.Fields, 1)
+			assert.Equal(t, tt.expectedField, appErr.Fields[0].Field)
+			assert.Equal(t, tt.expectedCode, appErr.Fields[0].Code)
+		})
+	}
+
+	assert.NoError(t, spec.ValidateBody(op, []byte(`{"id":"1","price":10,"tax":0}`)))
+	assert.ErrorIs(t, spec.ValidateBody(op, []byte(`{"id":`)), ErrMalformedBody)
+}
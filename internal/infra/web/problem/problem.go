@@ -0,0 +1,88 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContentType definido pela RFC 7807 (Problem Details for HTTP APIs).
+const ContentType = "application/problem+json"
+
+const typePrefix = "urn:gofleet:problem:"
+
+type Details struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code,omitempty"`
+	TraceID  string                `json:"trace_id,omitempty"`
+	Errors   []apperror.FieldError `json:"errors,omitempty"`
+}
+
+func StatusFor(kind apperror.Kind) int {
+	switch kind {
+	case apperror.KindValidation:
+		return http.StatusUnprocessableEntity
+	case apperror.KindNotFound:
+		return http.StatusNotFound
+	case apperror.KindConflict:
+		return http.StatusConflict
+	case apperror.KindUnavailable:
+		return http.StatusServiceUnavailable
+	case apperror.KindUnauthenticated:
+		return http.StatusUnauthorized
+	case apperror.KindForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func New(r *http.Request, status int, code, detail string) Details {
+	p := Details{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	}
+	if code != "" {
+		p.Type = typePrefix + code
+	}
+	if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+		p.TraceID = sc.TraceID().String()
+	}
+	return p
+}
+
+// FromError converte qualquer erro da aplicação em Problem Details.
+// Erros internos nunca expõem a mensagem original ao cliente.
+func FromError(r *http.Request, err error) Details {
+	appErr := apperror.As(err)
+	status := StatusFor(appErr.Kind)
+
+	detail := appErr.Message
+	if appErr.Kind == apperror.KindInternal {
+		detail = "an unexpected error occurred"
+	}
+
+	p := New(r, status, appErr.Code, detail)
+	p.Errors = appErr.Fields
+	return p
+}
+
+func Write(w http.ResponseWriter, p Details) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	Write(w, FromError(r, err))
+}
@@ -0,0 +1,82 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/application/apperror"
+	"github.com/DioGolang/GoFleet/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromError_DomainErrors(t *testing.T) {
+	tests := []struct {
+		err            error
+		expectedStatus int
+		expectedField  string
+	}{
+		{entity.ErrIDIsRequired, http.StatusUnprocessableEntity, "id"},
+		{entity.ErrInvalidID, http.StatusUnprocessableEntity, "id"},
+		{entity.ErrPriceIsRequired, http.StatusUnprocessableEntity, "price"},
+		{entity.ErrPriceMustBePos, http.StatusUnprocessableEntity, "price"},
+		{entity.ErrTaxMustBePos, http.StatusUnprocessableEntity, "tax"},
+		{entity.ErrInvalidStateTransition, http.StatusConflict, ""},
+		{entity.ErrUnknownState, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil)
+
+			p := FromError(r, fmt.Errorf("wrapped: %w", tt.err))
+
+			assert.Equal(t, tt.expectedStatus, p.Status)
+			assert.Equal(t, "/api/v1/orders", p.Instance)
+			assert.NotEmpty(t, p.Code)
+			if tt.expectedField != "" {
+				require.Len(t, p.Errors, 1)
+				assert.Equal(t, tt.expectedField, p.Errors[0].Field)
+			} else {
+				assert.Empty(t, p.Errors)
+			}
+		})
+	}
+}
+
+func TestFromError_ApplicationKinds(t *testing.T) {
+	tests := []struct {
+		err            error
+		expectedStatus int
+	}{
+		{apperror.NotFound("order_not_found", "order not found", nil), http.StatusNotFound},
+		{apperror.Conflict("order_already_exists", "order already exists", nil), http.StatusConflict},
+		{apperror.Unavailable("order_storage_unavailable", "down", nil), http.StatusServiceUnavailable},
+		{apperror.Unauthenticated("invalid_token", "invalid token", nil), http.StatusUnauthorized},
+		{apperror.Forbidden("forbidden", "not allowed"), http.StatusForbidden},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			assert.Equal(t, tt.expectedStatus, FromError(r, tt.err).Status)
+		})
+	}
+}
+
+func TestWriteError_DoesNotLeakInternalErrors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	WriteError(w, r, errors.New("pq: password authentication failed"))
+
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	var body Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.NotContains(t, body.Detail, "password")
+}
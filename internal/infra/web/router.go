@@ -0,0 +1,93 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/DioGolang/GoFleet/internal/application/auth"
+	"github.com/DioGolang/GoFleet/internal/infra/web/handler"
+	"github.com/DioGolang/GoFleet/internal/infra/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type APIHandlers struct {
+	Order   *handler.Order
+	OpenAPI http.Handler
+
+	// Authenticate valida o Bearer token, Tenant resolve o tenant da requisição,
+	// RateLimit aplica a cota do cliente e Validate aplica o contrato OpenAPI.
+	// Rodam apenas nas rotas protegidas, nessa ordem. O limite por IP
+	// (middleware.RateLimitByIP) fica no router, antes de tudo isso.
+	Authenticate func(http.Handler) http.Handler
+	Tenant       func(http.Handler) http.Handler
+	RateLimit    func(http.Handler) http.Handler
+	Validate     func(http.Handler) http.Handler
+}
+
+// RegisterAPIRoutes registra as rotas versionadas da API.
+// Toda rota aqui precisa estar declarada em openapi/openapi.json (ver router_test.go).
+func RegisterAPIRoutes(r chi.Router, h APIHandlers) {
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/openapi.json", h.OpenAPI.ServeHTTP)
+
+		r.Group(func(r chi.Router) {
+			r.Use(
+				orPassthrough(h.Authenticate),
+				orPassthrough(h.Tenant),
+				orPassthrough(h.RateLimit),
+				orPassthrough(h.Validate),
+			)
+
+			r.With(middleware.RequireRole(auth.RoleCustomer, auth.RoleDispatcher)).
+				Post("/orders", h.Order.Create)
+			r.Get("/orders/{id}", h.Order.Get)
+			r.With(middleware.RequireRole(auth.RoleCustomer, auth.RoleDispatcher)).
+				Post("/orders/{id}/cancel", h.Order.Cancel)
+			r.With(middleware.RequireRole(auth.RoleDriver)).
+				Post("/orders/{id}/deliver", h.Order.Deliver)
+			r.With(middleware.RequireRole(auth.RoleDispatcher)).
+				Post("/orders/{id}/assign", h.Order.Assign)
+		})
+	})
+}
+
+// AdminHandlers: cada processo registra só o que opera (relay: outbox; worker: parking).
+type AdminHandlers struct {
+	Outbox       *handler.OutboxAdmin
+	Parking      *handler.ParkingAdmin
+	Authenticate func(http.Handler) http.Handler
+}
+
+// RegisterAdminRoutes registra rotas operacionais, fora do contrato público /api/v1.
+// Não passam por tenant: atuam sobre a plataforma inteira e exigem papel admin.
+func RegisterAdminRoutes(r chi.Router, h AdminHandlers) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(orPassthrough(h.Authenticate), middleware.RequireRole(auth.RoleAdmin))
+
+		if h.Outbox != nil {
+			r.Route("/outbox", func(r chi.Router) {
+				r.Get("/events", h.Outbox.List)
+				r.Get("/events/{id}", h.Outbox.Get)
+				r.Post("/requeue", h.Outbox.Requeue)
+				r.Get("/state", h.Outbox.State)
+				r.Post("/pause", h.Outbox.Pause)
+				r.Post("/resume", h.Outbox.Resume)
+			})
+		}
+
+		if h.Parking != nil {
+			r.Route("/parking/{queue}", func(r chi.Router) {
+				r.Get("/messages", h.Parking.List)
+				r.Get("/messages/{id}", h.Parking.Get)
+				r.Post("/replay", h.Parking.Replay)
+				r.Post("/purge", h.Parking.Purge)
+			})
+		}
+	})
+}
+
+func orPassthrough(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	if mw == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return mw
+}
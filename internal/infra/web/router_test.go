@@ -0,0 +1,42 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DioGolang/GoFleet/internal/infra/web/handler"
+	"github.com/DioGolang/GoFleet/internal/infra/web/openapi"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoutesMatchOpenAPISpec falha quando uma rota /api/v1 é registrada no chi
+// sem estar documentada, ou quando o documento descreve uma rota inexistente.
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	RegisterAPIRoutes(r, APIHandlers{
+		Order:   &handler.Order{},
+		OpenAPI: openapi.Handler(),
+	})
+
+	var registered []string
+	err = chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/v1") {
+			registered = append(registered, method+" "+strings.TrimSuffix(route, "/"))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	documented := spec.Routes()
+
+	sort.Strings(registered)
+	sort.Strings(documented)
+	assert.Equal(t, documented, registered, "chi routes and openapi.json drifted apart")
+}
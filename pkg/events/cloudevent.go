@@ -0,0 +1,221 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SpecVersion é a versão do CloudEvents emitida pelo GoFleet.
+const SpecVersion = "1.0"
+
+// StructuredContentType marca uma mensagem em structured content mode:
+// atributos e dados vão juntos no corpo JSON.
+const StructuredContentType = "application/cloudevents+json"
+
+// Extensões CloudEvents emitidas pelo GoFleet.
+const (
+	ExtTenantID    = "tenantid"
+	ExtSequence    = "sequence"
+	ExtDataVersion = "dataversion"
+)
+
+const typePrefix = "io.gofleet."
+
+var (
+	ErrInvalidCloudEvent = errors.New("invalid cloudevent")
+
+	extensionName = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+)
+
+// CloudEvent é o envelope CloudEvents 1.0 de toda mensagem publicada.
+type CloudEvent struct {
+	ID              string
+	Source          string
+	Type            string
+	SpecVersion     string
+	Time            time.Time
+	Subject         string
+	DataContentType string
+	Data            []byte
+	Extensions      map[string]string
+}
+
+// TypeFor monta o type CloudEvents (ex: io.gofleet.order.OrderCreated).
+func TypeFor(aggregateType, eventType string) string {
+	if aggregateType == "" || aggregateType == "*" {
+		return typePrefix + eventType
+	}
+	return typePrefix + strings.ToLower(aggregateType) + "." + eventType
+}
+
+// EventType devolve o nome do evento sem o prefixo do type (ex: OrderCreated).
+func (e CloudEvent) EventType() string {
+	return e.Type[strings.LastIndex(e.Type, ".")+1:]
+}
+
+// DataVersion lê a extensão dataversion. Eventos sem ela são da versão 1.
+func (e CloudEvent) DataVersion() (int32, error) {
+	raw := e.Extensions[ExtDataVersion]
+	if raw == "" {
+		return 1, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("%w: invalid dataversion %q", ErrInvalidCloudEvent, raw)
+	}
+	return int32(v), nil
+}
+
+// Extension devolve um atributo de extensão, ou "" se ausente.
+func (e CloudEvent) Extension(name string) string {
+	return e.Extensions[name]
+}
+
+// Validate checa os atributos obrigatórios da spec e o formato das extensões.
+func (e CloudEvent) Validate() error {
+	switch {
+	case e.ID == "":
+		return fmt.Errorf("%w: missing id", ErrInvalidCloudEvent)
+	case e.Source == "":
+		return fmt.Errorf("%w: missing source", ErrInvalidCloudEvent)
+	case e.Type == "":
+		return fmt.Errorf("%w: missing type", ErrInvalidCloudEvent)
+	case !strings.HasPrefix(e.SpecVersion, "1."):
+		return fmt.Errorf("%w: unsupported specversion %q", ErrInvalidCloudEvent, e.SpecVersion)
+	}
+	for name := range e.Extensions {
+		if !extensionName.MatchString(name) {
+			return fmt.Errorf("%w: invalid extension name %q", ErrInvalidCloudEvent, name)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON gera o formato structured (JSON event format).
+func (e CloudEvent) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, 8+len(e.Extensions))
+	for k, v := range e.Extensions {
+		m[k] = v
+	}
+	m["specversion"] = e.SpecVersion
+	m["id"] = e.ID
+	m["source"] = e.Source
+	m["type"] = e.Type
+	if !e.Time.IsZero() {
+		m["time"] = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	if e.Subject != "" {
+		m["subject"] = e.Subject
+	}
+	if e.DataContentType != "" {
+		m["datacontenttype"] = e.DataContentType
+	}
+	if len(e.Data) > 0 {
+		if isJSONContentType(e.DataContentType) && json.Valid(e.Data) {
+			m["data"] = json.RawMessage(e.Data)
+		} else {
+			m["data_base64"] = e.Data
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON lê o formato structured. Atributos desconhecidos viram extensões.
+func (e *CloudEvent) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCloudEvent, err)
+	}
+
+	*e = CloudEvent{}
+	for k, v := range raw {
+		switch k {
+		case "data":
+			e.Data = v
+			// Dados JSON string chegam com aspas; o payload original é o texto.
+			var s string
+			if !isJSONContentType(contentTypeOf(raw)) && json.Unmarshal(v, &s) == nil {
+				e.Data = []byte(s)
+			}
+		case "data_base64":
+			if err := json.Unmarshal(v, &e.Data); err != nil {
+				return fmt.Errorf("%w: data_base64: %v", ErrInvalidCloudEvent, err)
+			}
+		case "time":
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return fmt.Errorf("%w: time: %v", ErrInvalidCloudEvent, err)
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return fmt.Errorf("%w: time: %v", ErrInvalidCloudEvent, err)
+			}
+			e.Time = t
+		default:
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				// Extensões não-string (número, bool) são mantidas na forma JSON.
+				s = string(v)
+			}
+			e.set(k, s)
+		}
+	}
+	return nil
+}
+
+// set atribui um atributo de contexto pelo nome usado na spec.
+func (e *CloudEvent) set(name, value string) {
+	switch name {
+	case "id":
+		e.ID = value
+	case "source":
+		e.Source = value
+	case "type":
+		e.Type = value
+	case "specversion":
+		e.SpecVersion = value
+	case "subject":
+		e.Subject = value
+	case "datacontenttype":
+		e.DataContentType = value
+	default:
+		if e.Extensions == nil {
+			e.Extensions = make(map[string]string)
+		}
+		e.Extensions[name] = value
+	}
+}
+
+// SetAttribute atribui um atributo de contexto (inclusive time) a partir do texto.
+func (e *CloudEvent) SetAttribute(name, value string) error {
+	if name == "time" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("%w: time: %v", ErrInvalidCloudEvent, err)
+		}
+		e.Time = t
+		return nil
+	}
+	e.set(name, value)
+	return nil
+}
+
+func contentTypeOf(raw map[string]json.RawMessage) string {
+	var ct string
+	_ = json.Unmarshal(raw["datacontenttype"], &ct)
+	return ct
+}
+
+// isJSONContentType segue a regra da spec: sem datacontenttype, data é JSON.
+func isJSONContentType(ct string) bool {
+	if ct == "" {
+		return true
+	}
+	ct = strings.ToLower(strings.TrimSpace(strings.SplitN(ct, ";", 2)[0]))
+	return ct == "application/json" || ct == "text/json" || strings.HasSuffix(ct, "+json")
+}
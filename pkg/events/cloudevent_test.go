@@ -0,0 +1,78 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEvent_StructuredRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 123000000, time.UTC)
+	evt := CloudEvent{
+		ID:              "4b1c0f4e-1d53-4c1a-9f3e-1f3c0a9d0b11",
+		Source:          "/gofleet/api",
+		Type:            TypeFor("Order", "OrderCreated"),
+		SpecVersion:     SpecVersion,
+		Time:            at,
+		Subject:         "order-1",
+		DataContentType: "application/json",
+		Data:            []byte(`{"id":"order-1"}`),
+		Extensions:      map[string]string{ExtTenantID: "acme", ExtSequence: "3"},
+	}
+
+	b, err := json.Marshal(evt)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"order-1"}`, string(mustField(t, b, "data")))
+
+	var got CloudEvent
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, "io.gofleet.order.OrderCreated", got.Type)
+	assert.True(t, at.Equal(got.Time))
+	got.Time = at
+	assert.JSONEq(t, string(evt.Data), string(got.Data))
+	got.Data = evt.Data
+	assert.Equal(t, evt, got)
+}
+
+func TestCloudEvent_BinaryDataUsesBase64(t *testing.T) {
+	evt := CloudEvent{ID: "1", Source: "/s", Type: "t", SpecVersion: SpecVersion,
+		DataContentType: "application/octet-stream", Data: []byte{0xff, 0x00}}
+
+	b, err := json.Marshal(evt)
+	require.NoError(t, err)
+	assert.NotNil(t, mustField(t, b, "data_base64"))
+
+	var got CloudEvent
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, evt.Data, got.Data)
+}
+
+func TestCloudEvent_Validate(t *testing.T) {
+	valid := CloudEvent{ID: "1", Source: "/s", Type: "t", SpecVersion: SpecVersion}
+	require.NoError(t, valid.Validate())
+
+	cases := map[string]func(e *CloudEvent){
+		"missing id":         func(e *CloudEvent) { e.ID = "" },
+		"missing source":     func(e *CloudEvent) { e.Source = "" },
+		"missing type":       func(e *CloudEvent) { e.Type = "" },
+		"old specversion":    func(e *CloudEvent) { e.SpecVersion = "0.3" },
+		"bad extension name": func(e *CloudEvent) { e.Extensions = map[string]string{"tenant_id": "x"} },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := valid
+			mutate(&e)
+			assert.ErrorIs(t, e.Validate(), ErrInvalidCloudEvent)
+		})
+	}
+}
+
+func mustField(t *testing.T, b []byte, name string) json.RawMessage {
+	t.Helper()
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &m))
+	return m[name]
+}
@@ -12,15 +12,42 @@ type Event interface {
 	SetPayload(payload interface{})
 }
 
+// RawMessage é um evento já serializado (ex: linha do outbox) pronto para publicação.
+// O dispatcher decide exchange e routing key a partir de AggregateType/EventType/Topic
+// e embrulha o payload num CloudEvent (ID, AggregateID como subject, Time).
+type RawMessage struct {
+	ID            string
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Topic         string
+	Time          time.Time
+	Payload       []byte
+	// Extensions são atributos de extensão CloudEvents (ex: tenantid, sequence).
+	Extensions map[string]string
+	// Headers são headers de transporte fora do envelope.
+	Headers map[string]string
+}
+
 type EventDispatcher interface {
 	Register(eventName string, handler EventHandler) error
-	Dispatch(ctx context.Context, event Event) error
-	DispatchRaw(ctx context.Context, routingKey string, payload []byte, headers map[string]string) error
+	DispatchRaw(ctx context.Context, msg RawMessage) error
 	Remove(eventName string, handler EventHandler) error
 	Has(eventName string, handler EventHandler) bool
 	Clear()
 }
 
+// Confirmation é uma publicação aguardando a confirmação do broker.
+type Confirmation interface {
+	Wait(ctx context.Context) error
+}
+
+// ConfirmingDispatcher publica sem esperar o broker, para que lotes inteiros
+// sejam confirmados de uma vez.
+type ConfirmingDispatcher interface {
+	DispatchAsync(ctx context.Context, msg RawMessage) (Confirmation, error)
+}
+
 type EventHandler interface {
 	Handler(event Event)
 }
@@ -0,0 +1,174 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+var (
+	ErrUnknownEventType = errors.New("unknown event type")
+	// ErrUnknownVersion indica um evento de versão mais nova que a deste binário:
+	// não há como interpretá-lo, e retentar não ajuda.
+	ErrUnknownVersion  = errors.New("unknown event version")
+	ErrMissingUpcaster = errors.New("missing upcaster")
+)
+
+// Upcaster migra o payload de uma versão para a seguinte (v -> v+1).
+type Upcaster func(payload []byte) ([]byte, error)
+
+// schemaFile casa arquivos no formato <EventType>.v<N>.json.
+var schemaFile = regexp.MustCompile(`^([A-Za-z0-9_]+)\.v([0-9]+)\.json$`)
+
+// SchemaRegistry guarda o contrato de cada versão de cada tipo de evento e os
+// upcasters entre versões consecutivas. A maior versão registrada é a atual:
+// produtores gravam nela e consumidores recebem tudo migrado para ela.
+type SchemaRegistry struct {
+	mu        sync.RWMutex
+	schemas   map[string]map[int32]*Schema
+	upcasters map[string]map[int32]Upcaster
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas:   make(map[string]map[int32]*Schema),
+		upcasters: make(map[string]map[int32]Upcaster),
+	}
+}
+
+// Register associa um JSON Schema a (eventType, version).
+func (r *SchemaRegistry) Register(eventType string, version int32, schema []byte) error {
+	if version < 1 {
+		return fmt.Errorf("schema %s.v%d: version must be >= 1", eventType, version)
+	}
+	s, err := CompileSchema(schema)
+	if err != nil {
+		return fmt.Errorf("schema %s.v%d: %w", eventType, version, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemas[eventType] == nil {
+		r.schemas[eventType] = make(map[int32]*Schema)
+	}
+	r.schemas[eventType][version] = s
+	return nil
+}
+
+// RegisterUpcaster registra a migração de `from` para `from+1`.
+func (r *SchemaRegistry) RegisterUpcaster(eventType string, from int32, up Upcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.upcasters[eventType] == nil {
+		r.upcasters[eventType] = make(map[int32]Upcaster)
+	}
+	r.upcasters[eventType][from] = up
+}
+
+// LoadFS registra todos os arquivos <EventType>.v<N>.json de um diretório.
+func (r *SchemaRegistry) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		m := schemaFile.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[2], 10, 32)
+		if err != nil {
+			return fmt.Errorf("schema %s: %w", e.Name(), err)
+		}
+		doc, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := r.Register(m[1], int32(version), doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CurrentVersion devolve a maior versão registrada de um tipo.
+func (r *SchemaRegistry) CurrentVersion(eventType string) (int32, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.currentLocked(eventType)
+}
+
+func (r *SchemaRegistry) currentLocked(eventType string) (int32, error) {
+	versions, ok := r.schemas[eventType]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+	var current int32
+	for v := range versions {
+		current = max(current, v)
+	}
+	return current, nil
+}
+
+// Validate checa um payload contra o schema exato da sua versão.
+func (r *SchemaRegistry) Validate(eventType string, version int32, payload []byte) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	current, err := r.currentLocked(eventType)
+	if err != nil {
+		return err
+	}
+	s, ok := r.schemas[eventType][version]
+	if !ok {
+		if version > current {
+			return fmt.Errorf("%w: %s v%d (current v%d)", ErrUnknownVersion, eventType, version, current)
+		}
+		return fmt.Errorf("%w: %s v%d has no schema", ErrUnknownVersion, eventType, version)
+	}
+	if err := s.Validate(payload); err != nil {
+		return fmt.Errorf("%s v%d: %w", eventType, version, err)
+	}
+	return nil
+}
+
+// Upcast valida o payload na versão recebida e o migra, passo a passo, até a
+// versão atual. O resultado também é validado contra o schema atual.
+func (r *SchemaRegistry) Upcast(eventType string, version int32, payload []byte) ([]byte, int32, error) {
+	if err := r.Validate(eventType, version, payload); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	current, _ := r.currentLocked(eventType)
+	chain := make([]Upcaster, 0, current-version)
+	for v := version; v < current; v++ {
+		up, ok := r.upcasters[eventType][v]
+		if !ok {
+			r.mu.RUnlock()
+			return nil, 0, fmt.Errorf("%w: %s v%d -> v%d", ErrMissingUpcaster, eventType, v, v+1)
+		}
+		chain = append(chain, up)
+	}
+	r.mu.RUnlock()
+
+	if len(chain) == 0 {
+		return payload, version, nil
+	}
+
+	out := payload
+	for i, up := range chain {
+		var err error
+		if out, err = up(out); err != nil {
+			return nil, 0, fmt.Errorf("upcast %s v%d -> v%d: %w", eventType, version+int32(i), version+int32(i)+1, err)
+		}
+	}
+	if err := r.Validate(eventType, current, out); err != nil {
+		return nil, 0, err
+	}
+	return out, current, nil
+}
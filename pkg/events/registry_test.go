@@ -0,0 +1,100 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	greetV1 = `{"type":"object","required":["name"],"properties":{"name":{"type":"string","minLength":1}}}`
+	greetV2 = `{"type":"object","required":["first","last"],"additionalProperties":false,
+		"properties":{"first":{"type":"string"},"last":{"type":"string"},"lang":{"enum":["pt","en"]}}}`
+)
+
+func newGreetRegistry(t *testing.T) *SchemaRegistry {
+	t.Helper()
+	reg := NewSchemaRegistry()
+	require.NoError(t, reg.LoadFS(fstest.MapFS{
+		"schemas/Greeted.v1.json": {Data: []byte(greetV1)},
+		"schemas/Greeted.v2.json": {Data: []byte(greetV2)},
+		"schemas/README.md":       {Data: []byte("ignorado")},
+	}, "schemas"))
+	reg.RegisterUpcaster("Greeted", 1, func(p []byte) ([]byte, error) {
+		var v1 struct{ Name string }
+		if err := json.Unmarshal(p, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"first": v1.Name, "last": ""})
+	})
+	return reg
+}
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	reg := newGreetRegistry(t)
+
+	current, err := reg.CurrentVersion("Greeted")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), current)
+
+	assert.NoError(t, reg.Validate("Greeted", 2, []byte(`{"first":"Ana","last":"Lima","lang":"pt"}`)))
+
+	cases := map[string]string{
+		"missing required": `{"first":"Ana"}`,
+		"wrong type":       `{"first":1,"last":"Lima"}`,
+		"not in enum":      `{"first":"Ana","last":"Lima","lang":"es"}`,
+		"extra property":   `{"first":"Ana","last":"Lima","age":3}`,
+		"not json":         `{`,
+	}
+	for name, payload := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, reg.Validate("Greeted", 2, []byte(payload)), ErrSchemaViolation)
+		})
+	}
+
+	assert.ErrorIs(t, reg.Validate("Greeted", 3, []byte(`{}`)), ErrUnknownVersion)
+	assert.ErrorIs(t, reg.Validate("Waved", 1, []byte(`{}`)), ErrUnknownEventType)
+}
+
+func TestSchemaRegistry_Upcast(t *testing.T) {
+	reg := newGreetRegistry(t)
+
+	out, version, err := reg.Upcast("Greeted", 1, []byte(`{"name":"Ana"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), version)
+	assert.JSONEq(t, `{"first":"Ana","last":""}`, string(out))
+
+	// Já na versão atual: passa intacto.
+	in := []byte(`{"first":"Ana","last":"Lima"}`)
+	out, version, err = reg.Upcast("Greeted", 2, in)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), version)
+	assert.Equal(t, in, out)
+
+	// A versão de origem também é validada.
+	_, _, err = reg.Upcast("Greeted", 1, []byte(`{"name":""}`))
+	assert.ErrorIs(t, err, ErrSchemaViolation)
+
+	_, _, err = reg.Upcast("Greeted", 7, []byte(`{}`))
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+}
+
+func TestSchemaRegistry_MissingUpcaster(t *testing.T) {
+	reg := NewSchemaRegistry()
+	require.NoError(t, reg.Register("Greeted", 1, []byte(greetV1)))
+	require.NoError(t, reg.Register("Greeted", 2, []byte(greetV2)))
+
+	_, _, err := reg.Upcast("Greeted", 1, []byte(`{"name":"Ana"}`))
+	assert.ErrorIs(t, err, ErrMissingUpcaster)
+}
+
+func TestCompileSchema_RejectsUnsupportedKeywords(t *testing.T) {
+	_, err := CompileSchema([]byte(`{"type":"object","oneOf":[]}`))
+	assert.Error(t, err)
+
+	_, err = CompileSchema([]byte(`{"type":"string","format":"uuid","description":"ok"}`))
+	assert.NoError(t, err)
+}
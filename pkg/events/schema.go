@@ -0,0 +1,303 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrSchemaViolation indica um payload que não respeita o schema da sua versão.
+var ErrSchemaViolation = errors.New("event payload violates schema")
+
+// Schema é o subconjunto de JSON Schema (draft 2020-12) que os contratos de evento
+// usam: type, properties, required, additionalProperties, items, enum, const,
+// minLength/maxLength, minimum/maximum/exclusiveMinimum/exclusiveMaximum e
+// minItems/maxItems. Palavras-chave fora disso são recusadas na compilação para
+// que um contrato não pareça validado sem estar.
+type Schema struct {
+	Types                []string
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *bool
+	Items                *Schema
+	Enum                 []any
+	Const                *any
+	MinLength, MaxLength *int
+	Minimum, Maximum     *float64
+	ExclusiveMinimum     *float64
+	ExclusiveMaximum     *float64
+	MinItems, MaxItems   *int
+}
+
+// Anotações sem efeito na validação.
+var schemaAnnotations = []string{"$schema", "$id", "$comment", "title", "description", "examples", "default", "deprecated", "format"}
+
+// CompileSchema lê um documento JSON Schema.
+func CompileSchema(doc []byte) (*Schema, error) {
+	var raw any
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return compileNode(raw, "#")
+}
+
+func compileNode(raw any, path string) (*Schema, error) {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: must be an object", path)
+	}
+
+	s := &Schema{}
+	for key, v := range node {
+		var err error
+		switch key {
+		case "type":
+			s.Types, err = stringList(v)
+		case "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("schema %s/properties: must be an object", path)
+			}
+			s.Properties = make(map[string]*Schema, len(props))
+			for name, p := range props {
+				if s.Properties[name], err = compileNode(p, path+"/properties/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.Required, err = stringList(v)
+		case "additionalProperties":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("schema %s/additionalProperties: only boolean is supported", path)
+			}
+			s.AdditionalProperties = &b
+		case "items":
+			s.Items, err = compileNode(v, path+"/items")
+		case "enum":
+			list, ok := v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("schema %s/enum: must be an array", path)
+			}
+			s.Enum = normalizeAll(list)
+		case "const":
+			c := normalize(v)
+			s.Const = &c
+		case "minLength":
+			s.MinLength, err = intKeyword(v)
+		case "maxLength":
+			s.MaxLength, err = intKeyword(v)
+		case "minItems":
+			s.MinItems, err = intKeyword(v)
+		case "maxItems":
+			s.MaxItems, err = intKeyword(v)
+		case "minimum":
+			s.Minimum, err = numberKeyword(v)
+		case "maximum":
+			s.Maximum, err = numberKeyword(v)
+		case "exclusiveMinimum":
+			s.ExclusiveMinimum, err = numberKeyword(v)
+		case "exclusiveMaximum":
+			s.ExclusiveMaximum, err = numberKeyword(v)
+		default:
+			if !slices.Contains(schemaAnnotations, key) {
+				return nil, fmt.Errorf("schema %s: unsupported keyword %q", path, key)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("schema %s/%s: %w", path, key, err)
+		}
+	}
+	return s, nil
+}
+
+// Validate checa um documento JSON contra o schema.
+func (s *Schema) Validate(payload []byte) error {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: invalid json: %v", ErrSchemaViolation, err)
+	}
+	return s.validate(normalize(doc), "$")
+}
+
+func (s *Schema) validate(v any, path string) error {
+	if len(s.Types) > 0 && !slices.ContainsFunc(s.Types, func(t string) bool { return hasType(v, t) }) {
+		return violation(path, "expected %s", strings.Join(s.Types, " or "))
+	}
+	if s.Const != nil && !equalJSON(v, *s.Const) {
+		return violation(path, "must be %v", *s.Const)
+	}
+	if s.Enum != nil && !slices.ContainsFunc(s.Enum, func(e any) bool { return equalJSON(v, e) }) {
+		return violation(path, "must be one of %v", s.Enum)
+	}
+
+	switch t := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(t)
+		if s.MinLength != nil && n < *s.MinLength {
+			return violation(path, "shorter than %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return violation(path, "longer than %d", *s.MaxLength)
+		}
+	case float64:
+		if s.Minimum != nil && t < *s.Minimum {
+			return violation(path, "less than %v", *s.Minimum)
+		}
+		if s.Maximum != nil && t > *s.Maximum {
+			return violation(path, "greater than %v", *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && t <= *s.ExclusiveMinimum {
+			return violation(path, "must be greater than %v", *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && t >= *s.ExclusiveMaximum {
+			return violation(path, "must be less than %v", *s.ExclusiveMaximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(t) < *s.MinItems {
+			return violation(path, "fewer than %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(t) > *s.MaxItems {
+			return violation(path, "more than %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range t {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := t[name]; !ok {
+				return violation(path, "missing required property %q", name)
+			}
+		}
+		for name, val := range t {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return violation(path, "unexpected property %q", name)
+				}
+				continue
+			}
+			if err := prop.validate(val, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func violation(path, format string, args ...any) error {
+	return fmt.Errorf("%w: %s: %s", ErrSchemaViolation, path, fmt.Sprintf(format, args...))
+}
+
+func hasType(v any, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return false
+}
+
+// normalize converte json.Number em float64 para comparar e validar números.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		f, _ := t.Float64()
+		return f
+	case []any:
+		return normalizeAll(t)
+	case map[string]any:
+		for k, val := range t {
+			t[k] = normalize(val)
+		}
+		return t
+	}
+	return v
+}
+
+func normalizeAll(list []any) []any {
+	for i, v := range list {
+		list[i] = normalize(v)
+	}
+	return list
+}
+
+func equalJSON(a, b any) bool {
+	ab, errA := json.Marshal(a)
+	bb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ab, bb)
+}
+
+func stringList(v any) ([]string, error) {
+	if s, ok := v.(string); ok {
+		return []string{s}, nil
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("must be a string or an array of strings")
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, errors.New("must be an array of strings")
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func intKeyword(v any) (*int, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, errors.New("must be an integer")
+	}
+	i, err := n.Int64()
+	if err != nil || i < 0 {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	out := int(i)
+	return &out, nil
+}
+
+func numberKeyword(v any) (*float64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, errors.New("must be a number")
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
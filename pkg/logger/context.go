@@ -0,0 +1,23 @@
+package logger
+
+import "context"
+
+type contextFieldsKey struct{}
+
+// ContextWithFields anexa campos ao contexto. Todo log emitido com esse ctx
+// os inclui automaticamente (ex: principal autenticado, tenant).
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	existing := FieldsFromContext(ctx)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]Field)
+	return fields
+}
@@ -69,7 +69,9 @@ func (z *zapLogger) With(fields ...Field) Logger {
 
 // enrichment com OpenTelemetry
 func (z *zapLogger) enrich(ctx context.Context, fields []Field) []zap.Field {
-	zapFields := make([]zap.Field, 0, len(fields)+2)
+	ctxFields := FieldsFromContext(ctx)
+	zapFields := make([]zap.Field, 0, len(ctxFields)+len(fields)+2)
+	zapFields = append(zapFields, z.convertFields(ctxFields)...)
 	zapFields = append(zapFields, z.convertFields(fields)...)
 
 	span := trace.SpanFromContext(ctx)
@@ -16,4 +16,13 @@ type Metrics interface {
 	IncCacheHit(cacheType string)
 	IncCacheMiss(cacheType string)
 	IncOutboxEventsProcessed(status string)
+	ObserveOutboxPublishLatency(duration time.Duration)
+	SetOutboxBacklog(status string, depth int64)
+	SetOutboxOldestPendingAge(age time.Duration)
+	SetRabbitMQConnected(connected bool)
+	IncRabbitMQReconnects()
+	SetConsumerWorkers(queue string, workers int)
+	SetConsumerPrefetch(queue string, prefetch int)
+	IncConsumerDrainLate(queue string)
+	SetRateLimitFallback(active bool)
 }
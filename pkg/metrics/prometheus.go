@@ -7,15 +7,24 @@ import (
 )
 
 type Prometheus struct {
-	orderCreated    *prometheus.CounterVec
-	orderDispatched *prometheus.CounterVec
-	useCaseTotal    *prometheus.CounterVec
-	useCaseDuration *prometheus.HistogramVec
-	httpDuration    *prometheus.HistogramVec
-	grpcDuration    *prometheus.HistogramVec
-	cacheHits       *prometheus.CounterVec
-	cacheMisses     *prometheus.CounterVec
-	outboxEvents    *prometheus.CounterVec
+	orderCreated      *prometheus.CounterVec
+	orderDispatched   *prometheus.CounterVec
+	useCaseTotal      *prometheus.CounterVec
+	useCaseDuration   *prometheus.HistogramVec
+	httpDuration      *prometheus.HistogramVec
+	grpcDuration      *prometheus.HistogramVec
+	cacheHits         *prometheus.CounterVec
+	cacheMisses       *prometheus.CounterVec
+	outboxEvents      *prometheus.CounterVec
+	outboxLatency     prometheus.Histogram
+	outboxBacklog     *prometheus.GaugeVec
+	outboxOldestAge   prometheus.Gauge
+	amqpConnected     prometheus.Gauge
+	amqpReconnects    prometheus.Counter
+	consumerWorkers   *prometheus.GaugeVec
+	consumerPrefetch  *prometheus.GaugeVec
+	drainLate         *prometheus.CounterVec
+	rateLimitFallback prometheus.Gauge
 }
 
 func NewPrometheusMetrics(reg prometheus.Registerer, serviceName string) *Prometheus {
@@ -70,6 +79,52 @@ func NewPrometheusMetrics(reg prometheus.Registerer, serviceName string) *Promet
 			Help:        "Total outbox events processed.",
 			ConstLabels: prometheus.Labels{"service": serviceName},
 		}, []string{"status"}),
+		outboxLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "app_outbox_publish_latency_seconds",
+			Help:        "Time from publish to broker confirm of an outbox event.",
+			Buckets:     []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+		outboxBacklog: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "app_outbox_backlog_depth",
+			Help:        "Outbox rows not yet published, by status.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"status"}),
+		outboxOldestAge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "app_outbox_oldest_pending_age_seconds",
+			Help:        "Age of the oldest PENDING outbox row.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+		amqpConnected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "app_rabbitmq_connected",
+			Help:        "1 while the RabbitMQ connection is up, 0 while connecting or reconnecting.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+		amqpReconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "app_rabbitmq_reconnects_total",
+			Help:        "Total successful RabbitMQ reconnections.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
+		consumerWorkers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "app_consumer_workers",
+			Help:        "Worker goroutines consuming each queue.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"queue"}),
+		consumerPrefetch: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "app_consumer_prefetch",
+			Help:        "AMQP prefetch count of each queue's consumer.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"queue"}),
+		drainLate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "app_consumer_drain_late_total",
+			Help:        "Messages still in flight when the shutdown drain deadline expired.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}, []string{"queue"}),
+		rateLimitFallback: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "app_ratelimit_fallback",
+			Help:        "1 while the rate limiter is using the in-memory fallback instead of Redis.",
+			ConstLabels: prometheus.Labels{"service": serviceName},
+		}),
 	}
 
 	reg.MustRegister(
@@ -82,6 +137,15 @@ func NewPrometheusMetrics(reg prometheus.Registerer, serviceName string) *Promet
 		m.cacheHits,
 		m.cacheMisses,
 		m.outboxEvents,
+		m.outboxLatency,
+		m.outboxBacklog,
+		m.outboxOldestAge,
+		m.amqpConnected,
+		m.amqpReconnects,
+		m.consumerWorkers,
+		m.consumerPrefetch,
+		m.drainLate,
+		m.rateLimitFallback,
 	)
 	reg.MustRegister(collectors.NewGoCollector())
 	reg.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
@@ -125,3 +189,47 @@ func (p *Prometheus) IncCacheMiss(cacheType string) {
 func (p *Prometheus) IncOutboxEventsProcessed(status string) {
 	p.outboxEvents.WithLabelValues(status).Inc()
 }
+
+func (p *Prometheus) ObserveOutboxPublishLatency(duration time.Duration) {
+	p.outboxLatency.Observe(duration.Seconds())
+}
+
+func (p *Prometheus) SetOutboxBacklog(status string, depth int64) {
+	p.outboxBacklog.WithLabelValues(status).Set(float64(depth))
+}
+
+func (p *Prometheus) SetOutboxOldestPendingAge(age time.Duration) {
+	p.outboxOldestAge.Set(age.Seconds())
+}
+
+func (p *Prometheus) SetRabbitMQConnected(connected bool) {
+	if connected {
+		p.amqpConnected.Set(1)
+		return
+	}
+	p.amqpConnected.Set(0)
+}
+
+func (p *Prometheus) IncRabbitMQReconnects() {
+	p.amqpReconnects.Inc()
+}
+
+func (p *Prometheus) SetConsumerWorkers(queue string, workers int) {
+	p.consumerWorkers.WithLabelValues(queue).Set(float64(workers))
+}
+
+func (p *Prometheus) SetConsumerPrefetch(queue string, prefetch int) {
+	p.consumerPrefetch.WithLabelValues(queue).Set(float64(prefetch))
+}
+
+func (p *Prometheus) IncConsumerDrainLate(queue string) {
+	p.drainLate.WithLabelValues(queue).Inc()
+}
+
+func (p *Prometheus) SetRateLimitFallback(active bool) {
+	if active {
+		p.rateLimitFallback.Set(1)
+		return
+	}
+	p.rateLimitFallback.Set(0)
+}
@@ -0,0 +1,55 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+const (
+	// Header usado em HTTP (X-Tenant-ID) e AMQP (x-tenant-id).
+	Header     = "x-tenant-id"
+	baggageKey = "tenant_id"
+)
+
+var ErrTenantRequired = errors.New("tenant is required")
+
+type tenantKey struct{}
+
+// WithTenant coloca o tenant no contexto e no OTel Baggage, para que ele
+// atravesse AMQP (via tracing_context do outbox) e gRPC sem parâmetros extras.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	ctx = context.WithValue(ctx, tenantKey{}, tenantID)
+
+	member, err := baggage.NewMember(baggageKey, tenantID)
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// FromContext lê o tenant do contexto, caindo para o Baggage propagado
+// por um serviço anterior (ex: Fleet recebendo do Worker via gRPC).
+func FromContext(ctx context.Context) (string, bool) {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id, true
+	}
+	if id := baggage.FromContext(ctx).Member(baggageKey).Value(); id != "" {
+		return id, true
+	}
+	return "", false
+}
+
+// Require é o atalho para repositórios: sem tenant, nenhuma query roda.
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrTenantRequired
+	}
+	return id, nil
+}
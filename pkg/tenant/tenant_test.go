@@ -0,0 +1,29 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestWithTenant_SurvivesBaggagePropagation(t *testing.T) {
+	ctx := WithTenant(context.Background(), "brand-a")
+
+	// Simula a ida para outro serviço: só o Baggage atravessa a fronteira.
+	carrier := propagation.MapCarrier{}
+	propagation.Baggage{}.Inject(ctx, carrier)
+	remote := propagation.Baggage{}.Extract(context.Background(), carrier)
+
+	id, ok := FromContext(remote)
+	assert.True(t, ok)
+	assert.Equal(t, "brand-a", id)
+	assert.Equal(t, "brand-a", baggage.FromContext(remote).Member("tenant_id").Value())
+}
+
+func TestRequire_WithoutTenant(t *testing.T) {
+	_, err := Require(context.Background())
+	assert.ErrorIs(t, err, ErrTenantRequired)
+}
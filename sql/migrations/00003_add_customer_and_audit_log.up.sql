@@ -0,0 +1,23 @@
+ALTER TABLE orders
+    ADD COLUMN customer_id VARCHAR(255);
+
+CREATE INDEX idx_orders_customer
+    ON orders(customer_id);
+
+CREATE TABLE audit_log (
+                           id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                           actor_id      VARCHAR(255) NOT NULL, -- sub do token
+                           actor_role    VARCHAR(50)  NOT NULL, -- customer | driver | dispatcher | admin
+                           action        VARCHAR(100) NOT NULL, -- ex: "order.cancel"
+                           resource_type VARCHAR(100) NOT NULL, -- ex: "Order"
+                           resource_id   VARCHAR(255) NOT NULL,
+                           metadata      JSONB NOT NULL DEFAULT '{}'::jsonb,
+                           trace_id      VARCHAR(32),
+                           occurred_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_resource
+    ON audit_log(resource_type, resource_id);
+
+CREATE INDEX idx_audit_log_actor
+    ON audit_log(actor_id, occurred_at);
@@ -0,0 +1,31 @@
+-- Linhas anteriores ao multi-tenant pertencem ao tenant "default".
+ALTER TABLE orders
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE orders
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- O mesmo ID de pedido pode existir em tenants diferentes.
+ALTER TABLE orders DROP CONSTRAINT orders_pkey;
+ALTER TABLE orders ADD PRIMARY KEY (tenant_id, id);
+
+DROP INDEX idx_orders_customer;
+CREATE INDEX idx_orders_customer
+    ON orders(tenant_id, customer_id);
+
+ALTER TABLE outbox
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE outbox
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+DROP INDEX idx_outbox_aggregate;
+CREATE INDEX idx_outbox_aggregate
+    ON outbox(tenant_id, aggregate_type, aggregate_id);
+
+ALTER TABLE audit_log
+    ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE audit_log
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+DROP INDEX idx_audit_log_resource;
+CREATE INDEX idx_audit_log_resource
+    ON audit_log(tenant_id, resource_type, resource_id);
@@ -0,0 +1,18 @@
+-- Falhas de publicação voltam para PENDING com backoff; esgotadas as tentativas, DEAD.
+-- FAILED permanece válido apenas para linhas antigas.
+ALTER TABLE outbox
+    ADD COLUMN next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+ALTER TABLE outbox DROP CONSTRAINT outbox_status_check;
+ALTER TABLE outbox
+    ADD CONSTRAINT outbox_status_check
+        CHECK (status IN ('PENDING', 'PROCESSING', 'PUBLISHED', 'FAILED', 'DEAD'));
+
+DROP INDEX idx_outbox_fetch_pending;
+CREATE INDEX idx_outbox_fetch_pending
+    ON outbox(next_attempt_at, created_at)
+    WHERE status = 'PENDING';
+
+CREATE INDEX idx_outbox_dead
+    ON outbox(updated_at)
+    WHERE status = 'DEAD';
@@ -0,0 +1,32 @@
+-- Contador por agregado: sobrevive à limpeza de linhas antigas do outbox
+-- e serializa escritores concorrentes do mesmo agregado (lock de linha no upsert).
+CREATE TABLE outbox_aggregate_sequences (
+    tenant_id      VARCHAR(64)  NOT NULL,
+    aggregate_type VARCHAR(255) NOT NULL,
+    aggregate_id   VARCHAR(255) NOT NULL,
+    last_seq       BIGINT       NOT NULL,
+    PRIMARY KEY (tenant_id, aggregate_type, aggregate_id)
+);
+
+ALTER TABLE outbox ADD COLUMN aggregate_seq BIGINT;
+
+UPDATE outbox o
+SET aggregate_seq = s.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY tenant_id, aggregate_type, aggregate_id
+        ORDER BY created_at, id
+    ) AS seq
+    FROM outbox
+) s
+WHERE o.id = s.id;
+
+INSERT INTO outbox_aggregate_sequences (tenant_id, aggregate_type, aggregate_id, last_seq)
+SELECT tenant_id, aggregate_type, aggregate_id, MAX(aggregate_seq)
+FROM outbox
+GROUP BY tenant_id, aggregate_type, aggregate_id;
+
+ALTER TABLE outbox ALTER COLUMN aggregate_seq SET NOT NULL;
+
+CREATE UNIQUE INDEX uq_outbox_aggregate_seq
+    ON outbox(tenant_id, aggregate_type, aggregate_id, aggregate_seq);
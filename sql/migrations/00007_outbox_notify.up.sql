@@ -0,0 +1,14 @@
+-- Acorda o relay a cada INSERT no outbox. O NOTIFY só é entregue no commit e
+-- o Postgres funde notificações idênticas da mesma transação, então um lote
+-- grande gera um único aviso.
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_outbox_notify
+    AFTER INSERT ON outbox
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION notify_outbox_event();
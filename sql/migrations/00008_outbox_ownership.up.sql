@@ -0,0 +1,9 @@
+-- Dono e lease de cada linha em PROCESSING: o rescuer só devolve linhas cujo
+-- dono parou de renovar o lease.
+ALTER TABLE outbox ADD COLUMN claimed_by VARCHAR(255);
+ALTER TABLE outbox ADD COLUMN lease_expires_at TIMESTAMPTZ;
+
+-- Linhas em voo antes da migração não têm dono: ficam elegíveis ao rescue.
+UPDATE outbox SET lease_expires_at = NOW() WHERE status = 'PROCESSING';
+
+CREATE INDEX idx_outbox_lease ON outbox(lease_expires_at) WHERE status = 'PROCESSING';
@@ -0,0 +1,16 @@
+-- Chaves de idempotência dos consumers. Em PROCESSING, expires_at é o lease do
+-- dono (renovado enquanto o handler roda); em COMPLETED, por quanto tempo a
+-- chave ainda deduplica. Chave vencida pode ser retomada por outro dono.
+CREATE TABLE idempotency_keys (
+                        key        VARCHAR(512) PRIMARY KEY,
+                        state      VARCHAR(20) NOT NULL,
+                        CONSTRAINT idempotency_keys_state_check
+                            CHECK (state IN ('PROCESSING', 'COMPLETED')),
+                        owner      VARCHAR(255) NOT NULL,
+                        result     BYTEA,
+                        expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                        updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_idempotency_keys_expires ON idempotency_keys(expires_at);
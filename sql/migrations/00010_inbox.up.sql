@@ -0,0 +1,11 @@
+-- Inbox dos consumers: uma linha por (consumer, event_id), gravada na mesma
+-- transação do efeito. A PK é a deduplicação: a segunda entrega do mesmo evento
+-- esbarra nela e a transação inteira é descartada.
+CREATE TABLE inbox (
+                        consumer    VARCHAR(255) NOT NULL,
+                        event_id    VARCHAR(255) NOT NULL,
+                        received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+                        PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX idx_inbox_cleanup ON inbox(received_at);
@@ -0,0 +1,13 @@
+-- Linhas FAILED anteriores à política de retry (00005) nunca voltavam para a
+-- fila e eram apagadas pela limpeza. Podem ter meses e não devem ser
+-- republicadas em massa no deploy: viram DEAD, com o erro original, e voltam
+-- só por requeue do admin (POST /admin/outbox/requeue).
+UPDATE outbox
+SET status = 'DEAD',
+    updated_at = NOW()
+WHERE status = 'FAILED';
+
+DROP INDEX idx_outbox_cleanup;
+CREATE INDEX idx_outbox_cleanup
+    ON outbox(created_at)
+    WHERE status = 'PUBLISHED';
@@ -0,0 +1,10 @@
+-- Controle do relay compartilhado por todas as réplicas. Linha única (id sempre
+-- TRUE): pausar pelo admin de qualquer réplica para a publicação do líder.
+CREATE TABLE outbox_control (
+                                id         BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+                                paused     BOOLEAN NOT NULL DEFAULT FALSE,
+                                updated_by VARCHAR(255) NOT NULL DEFAULT '',
+                                updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO outbox_control (id) VALUES (TRUE);
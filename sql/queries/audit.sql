@@ -0,0 +1,13 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_log (
+    tenant_id,
+    actor_id,
+    actor_role,
+    action,
+    resource_type,
+    resource_id,
+    metadata,
+    trace_id
+) VALUES (
+             $1, $2, $3, $4, $5, $6, $7, $8
+         );
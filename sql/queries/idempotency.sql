@@ -0,0 +1,53 @@
+-- name: AcquireIdempotencyKey :one
+-- Cria a chave ou retoma uma vencida (lease de dono morto, ou COMPLETED fora do TTL).
+-- Sem linha de volta: a chave está viva com outro dono.
+INSERT INTO idempotency_keys (key, state, owner, expires_at)
+VALUES (@key, 'PROCESSING', @owner, NOW() + make_interval(secs => @lease_secs::float8))
+ON CONFLICT (key) DO UPDATE
+    SET state = 'PROCESSING',
+        owner = EXCLUDED.owner,
+        result = NULL,
+        expires_at = EXCLUDED.expires_at,
+        updated_at = NOW()
+    WHERE idempotency_keys.expires_at < NOW()
+RETURNING state, owner, result;
+
+-- name: GetIdempotencyKey :one
+SELECT state, owner, result
+FROM idempotency_keys
+WHERE key = $1
+  AND expires_at >= NOW();
+
+-- name: RenewIdempotencyKey :execrows
+-- Concluída na transação do efeito: continua do dono, sem trocar o TTL pelo lease.
+UPDATE idempotency_keys
+SET expires_at = CASE WHEN state = 'PROCESSING'
+                          THEN NOW() + make_interval(secs => @lease_secs::float8)
+                      ELSE expires_at END,
+    updated_at = NOW()
+WHERE key = @key
+  AND owner = @owner;
+
+-- name: CompleteIdempotencyKey :execrows
+-- Upsert: dentro da transação do efeito, grava a conclusão mesmo que a linha
+-- vencida já tenha sido apagada pelo purger. Só não sobrescreve a chave viva de outro dono.
+INSERT INTO idempotency_keys (key, state, owner, result, expires_at)
+VALUES (@key, 'COMPLETED', @owner, @result, NOW() + make_interval(secs => @ttl_secs::float8))
+ON CONFLICT (key) DO UPDATE
+    SET state = 'COMPLETED',
+        owner = EXCLUDED.owner,
+        result = EXCLUDED.result,
+        expires_at = EXCLUDED.expires_at,
+        updated_at = NOW()
+    WHERE idempotency_keys.owner = EXCLUDED.owner
+       OR idempotency_keys.expires_at < NOW();
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE key = @key
+  AND owner = @owner
+  AND state = 'PROCESSING';
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < NOW();
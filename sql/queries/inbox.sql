@@ -0,0 +1,8 @@
+-- name: InsertInboxMessage :exec
+-- Sem ON CONFLICT: a violação da PK é o sinal de duplicata e aborta a transação.
+INSERT INTO inbox (consumer, event_id)
+VALUES ($1, $2);
+
+-- name: DeleteOldInboxMessages :execrows
+DELETE FROM inbox
+WHERE received_at < NOW() - make_interval(secs => @retention_secs::float8);
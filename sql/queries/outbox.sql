@@ -1,6 +1,15 @@
 -- name: CreateOutboxEvent :exec
+-- aggregate_seq vem do contador por agregado, incrementado na mesma transação.
+WITH seq AS (
+    INSERT INTO outbox_aggregate_sequences (tenant_id, aggregate_type, aggregate_id, last_seq)
+    VALUES ($2, $3, $4, 1)
+    ON CONFLICT (tenant_id, aggregate_type, aggregate_id)
+        DO UPDATE SET last_seq = outbox_aggregate_sequences.last_seq + 1
+    RETURNING last_seq
+)
 INSERT INTO outbox (
     id,
+    tenant_id,
     aggregate_type,
     aggregate_id,
     event_type,
@@ -8,46 +17,150 @@ INSERT INTO outbox (
     payload,
     topic,
     tracing_context,
+    aggregate_seq,
     status
 ) VALUES (
-             $1, $2, $3, $4, $5, $6, $7, $8, 'PENDING'
+             $1, $2, $3, $4, $5, $6, $7, $8, $9, (SELECT last_seq FROM seq), 'PENDING'
          );
 
 -- name: FetchPendingOutboxEvents :many
-SELECT id, event_type, aggregate_id, event_version, payload, topic, tracing_context
-FROM outbox
-WHERE status = 'PENDING'
-ORDER BY created_at ASC
+-- O relay é um processo de sistema: varre todos os tenants e repassa
+-- tenant_id como header da mensagem.
+-- Um evento só é elegível se nenhum evento anterior do mesmo agregado está em voo
+-- (PROCESSING), em backoff, ou foi criado depois dele (commit fora de ordem). O
+-- advisory lock impede que dois relays reivindiquem o mesmo agregado ao mesmo tempo.
+SELECT o.id, o.aggregate_type, o.event_type, o.aggregate_id, o.event_version, o.payload, o.topic, o.tracing_context, o.tenant_id, o.aggregate_seq, o.created_at
+FROM outbox o
+WHERE o.status = 'PENDING'
+  AND o.next_attempt_at <= NOW()
+  AND NOT EXISTS (
+      SELECT 1
+      FROM outbox prev
+      WHERE prev.tenant_id = o.tenant_id
+        AND prev.aggregate_type = o.aggregate_type
+        AND prev.aggregate_id = o.aggregate_id
+        AND prev.aggregate_seq < o.aggregate_seq
+        AND (
+            prev.status = 'PROCESSING'
+            OR (prev.status = 'PENDING' AND (prev.next_attempt_at > NOW() OR prev.created_at > o.created_at))
+        )
+  )
+  AND pg_try_advisory_xact_lock(hashtextextended(o.tenant_id || '/' || o.aggregate_type || '/' || o.aggregate_id, 0))
+ORDER BY o.created_at ASC, o.aggregate_seq ASC
 LIMIT $1
-FOR UPDATE SKIP LOCKED;
+FOR UPDATE OF o SKIP LOCKED;
 
 -- name: MarkOutboxAsProcessing :exec
+-- Reivindica as linhas para um relay (owner) por lease_secs segundos.
 UPDATE outbox
-SET status = 'PROCESSING', updated_at = NOW()
+SET status = 'PROCESSING',
+    claimed_by = @owner::text,
+    lease_expires_at = NOW() + make_interval(secs => @lease_secs::float8),
+    updated_at = NOW()
 WHERE id = ANY(@ids::uuid[]);
 
+-- name: RenewOutboxLeases :execrows
+-- Heartbeat do relay: estende o lease de tudo que ele ainda tem em voo.
+UPDATE outbox
+SET lease_expires_at = NOW() + make_interval(secs => @lease_secs::float8)
+WHERE status = 'PROCESSING'
+  AND claimed_by = @owner::text;
+
 -- name: MarkOutboxAsPublished :exec
+-- Não exige o lease: se o broker confirmou, o evento saiu.
 UPDATE outbox
-SET status = 'PUBLISHED', published_at = NOW(), updated_at = NOW()
-WHERE id = $1;
+SET status = 'PUBLISHED',
+    claimed_by = NULL,
+    lease_expires_at = NULL,
+    published_at = NOW(),
+    updated_at = NOW()
+WHERE id = ANY(@ids::uuid[]);
 
--- name: MarkOutboxAsFailed :exec
+-- name: ReleaseOutboxEvents :exec
+-- Devolve eventos reivindicados sem contar tentativa (ex: um evento anterior do
+-- mesmo agregado falhou e eles não podem sair antes dele).
 UPDATE outbox
-SET status = 'FAILED',
-error_msg = $2,
-retry_count = retry_count + 1,
-updated_at = NOW()
-WHERE id = $1;
+SET status = 'PENDING', claimed_by = NULL, lease_expires_at = NULL, updated_at = NOW()
+WHERE id = ANY(@ids::uuid[])
+  AND status = 'PROCESSING'
+  AND claimed_by = @owner::text;
+
+-- name: ScheduleOutboxRetry :one
+-- Backoff exponencial com jitter (50%-100% do atraso), limitado a max_backoff_secs.
+-- Na última tentativa a linha vira DEAD e só volta por requeue manual.
+-- Sem linha de retorno, o relay perdeu o lease e outro dono assumiu o evento.
+UPDATE outbox
+SET status = CASE WHEN retry_count + 1 >= sqlc.arg(max_attempts)::int THEN 'DEAD' ELSE 'PENDING' END,
+    error_msg = sqlc.arg(error_msg),
+    retry_count = retry_count + 1,
+    next_attempt_at = NOW() + make_interval(secs => LEAST(
+        sqlc.arg(max_backoff_secs)::float8,
+        sqlc.arg(base_backoff_secs)::float8 * power(2, retry_count)
+    ) * (0.5 + random() / 2)),
+    claimed_by = NULL,
+    lease_expires_at = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'PROCESSING'
+  AND claimed_by = sqlc.arg(owner)::text
+RETURNING status, retry_count;
 
+-- name: RequeueFailedOutboxEvents :execrows
+-- Só devolve todas as linhas DEAD (e FAILED legadas) com all explícito; ids vazio não seleciona nada.
+UPDATE outbox
+SET status = 'PENDING',
+    retry_count = 0,
+    error_msg = NULL,
+    next_attempt_at = NOW(),
+    updated_at = NOW()
+WHERE status IN ('FAILED', 'DEAD')
+  AND (sqlc.arg(all_events)::bool OR id = ANY(sqlc.arg(ids)::uuid[]));
 
 -- name: DeleteOldOutboxEvents :exec
+-- Só PUBLISHED: falhas (DEAD) ficam até alguém decidir (requeue).
 DELETE FROM outbox
-WHERE status IN ('PUBLISHED', 'FAILED')
+WHERE status = 'PUBLISHED'
   -- O cast ::text força o SQLC a gerar o argumento como string no Go
   AND created_at < NOW() - (sqlc.arg(interval)::text)::interval;
 
--- name: ResetStuckEvents :exec
+-- name: ReclaimExpiredOutboxEvents :execrows
+-- Só devolve linhas cujo dono parou de renovar o lease (relay morto ou travado).
 UPDATE outbox
-SET status = 'PENDING', error_msg = 'stuck_recovery'
+SET status = 'PENDING',
+    claimed_by = NULL,
+    lease_expires_at = NULL,
+    error_msg = 'lease_expired',
+    updated_at = NOW()
 WHERE status = 'PROCESSING'
-  AND updated_at < NOW() - (sqlc.arg(interval)::text)::interval;
\ No newline at end of file
+  AND lease_expires_at < NOW();
+
+-- name: ListOutboxEventsByStatus :many
+-- Listagem do admin: sem payload, do mais antigo para o mais novo.
+SELECT id, tenant_id, aggregate_type, aggregate_id, aggregate_seq, event_type, topic,
+       status, retry_count, error_msg, next_attempt_at, claimed_by, created_at, updated_at
+FROM outbox
+WHERE status = @status
+ORDER BY created_at ASC
+LIMIT @lim;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox
+WHERE id = $1;
+
+-- name: OutboxStats :many
+-- Profundidade e idade da linha mais antiga de cada status ainda não publicado.
+SELECT status,
+       COUNT(*) AS depth,
+       EXTRACT(EPOCH FROM NOW() - MIN(created_at))::float8 AS oldest_age_secs
+FROM outbox
+WHERE status <> 'PUBLISHED'
+GROUP BY status;
+
+-- name: GetOutboxPaused :one
+SELECT paused FROM outbox_control WHERE id = TRUE;
+
+-- name: SetOutboxPaused :exec
+-- Vale para todas as réplicas: o líder relê a flag antes de cada lote.
+UPDATE outbox_control
+SET paused = @paused, updated_by = @updated_by, updated_at = NOW()
+WHERE id = TRUE;
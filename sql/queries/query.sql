@@ -1,16 +1,16 @@
 -- name: CreateOrder :exec
-INSERT INTO orders (id, price, tax, final_price, status, driver_id)
-VALUES ($1, $2, $3, $4, $5, $6);
+INSERT INTO orders (tenant_id, id, price, tax, final_price, status, driver_id, customer_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
 
 -- name: GetOrder :one
-SELECT id, price, tax, final_price, status, driver_id FROM orders
-WHERE id = $1;
+SELECT id, price, tax, final_price, status, driver_id, customer_id, tenant_id FROM orders
+WHERE tenant_id = $1 AND id = $2;
 
 -- name: ListOrders :many
-SELECT id, price, tax, final_price FROM orders;
+SELECT id, price, tax, final_price FROM orders
+WHERE tenant_id = $1;
 
--- name: UpdateOrderStatus :exec
+-- name: UpdateOrderStatus :execrows
 UPDATE orders
 SET status = $1, driver_id = $2
-WHERE id = $3;
-
+WHERE tenant_id = $3 AND id = $4;